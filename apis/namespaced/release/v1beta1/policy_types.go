@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A ReleasePolicyRule is a CEL expression that every matching rendered object
+// of a Release must satisfy.
+type ReleasePolicyRule struct {
+	// Name of the rule, reported in policy violations.
+	Name string `json:"name"`
+	// Kinds restricts the rule to rendered objects of the listed API groups
+	// and kinds. The rule applies to all rendered objects if empty.
+	// +optional
+	Kinds []KindSelector `json:"kinds,omitempty"`
+	// Expression is a CEL expression that must evaluate to true for the
+	// rendered object to be allowed. The rendered object is available as
+	// `object` and the Release as `release`, with the fields `name`,
+	// `namespace` and `targetNamespace`.
+	Expression string `json:"expression"`
+	// Message is reported when the expression does not evaluate to true.
+	// Defaults to the expression.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ReleasePolicySpec defines the rules enforced on the rendered manifests of
+// Releases.
+type ReleasePolicySpec struct {
+	// NamespaceSelector selects the Releases the policy applies to by the
+	// labels of their namespace, so that a Release cannot escape the policy
+	// by changing its own labels. The policy applies to all Releases if not
+	// set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Rules evaluated against every rendered object.
+	// +kubebuilder:validation:MinItems=1
+	Rules []ReleasePolicyRule `json:"rules"`
+}
+
+// A PolicyViolation describes a rendered object that violates a rule of a
+// ReleasePolicy.
+type PolicyViolation struct {
+	// Policy is the name of the violated ReleasePolicy.
+	Policy string `json:"policy"`
+	// Rule is the name of the violated rule.
+	Rule string `json:"rule"`
+	// Object identifies the rendered object as Kind/namespace/name.
+	Object string `json:"object"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ReleasePolicy enforces rules on the rendered manifests of namespaced
+// Releases. Install and upgrade are blocked while any rendered object
+// violates a rule of a policy that applies to the Release.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,helm}
+type ReleasePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReleasePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleasePolicyList contains a list of ReleasePolicy
+type ReleasePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReleasePolicy `json:"items"`
+}
+
+// A KindSelector matches rendered objects by API group and kind.
+type KindSelector struct {
+	// Group of the object, empty for the core API group. "*" matches all
+	// groups.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind of the object. "*" matches all kinds.
+	Kind string `json:"kind"`
+}
+
+// A ReleaseTenancyPolicySpec restricts the namespaces and kinds of objects a
+// Release may deploy.
+type ReleaseTenancyPolicySpec struct {
+	// NamespaceSelector selects the Releases the policy applies to by the
+	// labels of their namespace, so that a Release cannot escape the policy
+	// by changing its own labels. The policy applies to all Releases if not
+	// set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// AllowedNamespaces are the namespaces, in addition to the Release's own
+	// namespace, that a Release may install into and render objects into.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// AllowedKinds are the only kinds of objects a Release may render. All
+	// kinds are allowed if empty.
+	// +optional
+	AllowedKinds []KindSelector `json:"allowedKinds,omitempty"`
+	// DeniedKinds are kinds of objects a Release must not render. They take
+	// precedence over AllowedKinds.
+	// +optional
+	DeniedKinds []KindSelector `json:"deniedKinds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ReleaseTenancyPolicy restricts the target namespaces and the kinds of
+// rendered objects of namespaced Releases. Install and upgrade are blocked
+// while a Release violates a policy that applies to it.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,helm}
+type ReleaseTenancyPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReleaseTenancyPolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleaseTenancyPolicyList contains a list of ReleaseTenancyPolicy
+type ReleaseTenancyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReleaseTenancyPolicy `json:"items"`
+}
@@ -49,10 +49,37 @@ var (
 	ReleaseGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseKind)
 )
 
+// ReleasePolicy type metadata.
+var (
+	ReleasePolicyKind             = reflect.TypeOf(ReleasePolicy{}).Name()
+	ReleasePolicyGroupKind        = schema.GroupKind{Group: Group, Kind: ReleasePolicyKind}.String()
+	ReleasePolicyKindAPIVersion   = ReleasePolicyKind + "." + SchemeGroupVersion.String()
+	ReleasePolicyGroupVersionKind = SchemeGroupVersion.WithKind(ReleasePolicyKind)
+)
+
+// ReleaseTenancyPolicy type metadata.
+var (
+	ReleaseTenancyPolicyKind             = reflect.TypeOf(ReleaseTenancyPolicy{}).Name()
+	ReleaseTenancyPolicyGroupKind        = schema.GroupKind{Group: Group, Kind: ReleaseTenancyPolicyKind}.String()
+	ReleaseTenancyPolicyKindAPIVersion   = ReleaseTenancyPolicyKind + "." + SchemeGroupVersion.String()
+	ReleaseTenancyPolicyGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseTenancyPolicyKind)
+)
+
+// ReleaseSet type metadata.
+var (
+	ReleaseSetKind             = reflect.TypeOf(ReleaseSet{}).Name()
+	ReleaseSetGroupKind        = schema.GroupKind{Group: Group, Kind: ReleaseSetKind}.String()
+	ReleaseSetKindAPIVersion   = ReleaseSetKind + "." + SchemeGroupVersion.String()
+	ReleaseSetGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseSetKind)
+)
+
 // addKnownTypes adds the list of known types to the given scheme.
 func addKnownTypes(s *runtime.Scheme) error {
 	s.AddKnownTypes(SchemeGroupVersion,
 		&Release{}, &ReleaseList{},
+		&ReleasePolicy{}, &ReleasePolicyList{},
+		&ReleaseTenancyPolicy{}, &ReleaseTenancyPolicyList{},
+		&ReleaseSet{}, &ReleaseSetList{},
 	)
 	metav1.AddToGroupVersion(s, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,281 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// A ProviderConfigSelector selects ProviderConfigs or ClusterProviderConfigs
+// by their labels.
+type ProviderConfigSelector struct {
+	// Kind of the selected provider configs. ProviderConfigs are selected
+	// from the namespace of the ReleaseSet.
+	// +kubebuilder:validation:Enum=ProviderConfig;ClusterProviderConfig
+	// +kubebuilder:default=ClusterProviderConfig
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Selector matches the labels of the provider configs. All provider
+	// configs of the kind are selected if empty.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ReleaseTemplateMetadata is the metadata of the Releases generated by a
+// ReleaseSet.
+type ReleaseTemplateMetadata struct {
+	// Labels added to every generated Release.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to every generated Release.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ReleaseTemplateSpec is the spec of the Releases generated by a ReleaseSet.
+// The provider config reference is set per selected provider config.
+type ReleaseTemplateSpec struct {
+	ForProvider ReleaseParameters `json:"forProvider"`
+	// RollbackRetriesLimit is max number of attempts to retry Helm deployment by rolling back the release.
+	// +optional
+	RollbackRetriesLimit *int32 `json:"rollbackLimit,omitempty"`
+	// ManagementPolicies of the generated Releases.
+	// +optional
+	ManagementPolicies xpv2.ManagementPolicies `json:"managementPolicies,omitempty"`
+}
+
+// A ReleaseTemplate describes the Releases generated by a ReleaseSet.
+type ReleaseTemplate struct {
+	// +optional
+	Metadata ReleaseTemplateMetadata `json:"metadata,omitempty"`
+	Spec     ReleaseTemplateSpec     `json:"spec"`
+}
+
+// A ReleaseSetOverride overrides the values of the Release generated for a
+// provider config.
+type ReleaseSetOverride struct {
+	// ProviderConfigName is the name of the selected provider config the
+	// override applies to.
+	ProviderConfigName string `json:"providerConfigName"`
+	// Values are merged into the values of the template. ValuesFrom and Set
+	// are appended to the ones of the template, taking precedence over them.
+	ValuesSpec `json:",inline"`
+}
+
+// A RolloutWave is a group of provider configs rolled out to together.
+type RolloutWave struct {
+	// Name of the wave.
+	Name string `json:"name"`
+	// Selector matches the labels of the provider configs in the wave. A
+	// provider config is part of the first wave that selects it.
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// A ReleaseSetRollout controls how changes of a ReleaseSet roll out to the
+// generated Releases.
+type ReleaseSetRollout struct {
+	// MaxUnavailable is the maximum number of generated Releases that may be
+	// updating or unavailable at the same time, as an absolute number or a
+	// percentage of the selected provider configs. Releases are updated in
+	// the order of the names of their provider configs.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// Waves split the selected provider configs into groups that are rolled
+	// out to one after another. A wave starts once every Release of the
+	// previous waves is up to date, available, passed its tests if the
+	// template runs them, and soaked. Provider configs not selected by any
+	// wave form a final wave. All provider configs are rolled out to at once
+	// if empty.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Waves []RolloutWave `json:"waves,omitempty"`
+	// SoakDuration is how long the Releases of a wave must stay available
+	// before the next wave starts.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+	// ProgressDeadline is how long an updated Release may take to become
+	// available after its wave started before it counts as failed.
+	// +kubebuilder:default="10m"
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+	// FailureThreshold is the number or percentage of the selected provider
+	// configs whose Releases may fail before the rollout halts. A Release
+	// fails if its Helm release is in the failed state, if its tests fail,
+	// or if it does not become available within the progress deadline. The
+	// rollout never halts if not set.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	FailureThreshold *intstr.IntOrString `json:"failureThreshold,omitempty"`
+	// RollbackOnHalt rolls the Releases already updated by a halted rollout
+	// back to their previous spec.
+	// +optional
+	RollbackOnHalt bool `json:"rollbackOnHalt,omitempty"`
+}
+
+// A ReleaseSetSpec defines a chart deployed to every selected provider
+// config.
+type ReleaseSetSpec struct {
+	// ProviderConfigSelector selects the provider configs, one per cluster,
+	// to deploy a Release to.
+	ProviderConfigSelector ProviderConfigSelector `json:"providerConfigSelector"`
+	// Template of the generated Releases.
+	Template ReleaseTemplate `json:"template"`
+	// Overrides of the values per provider config.
+	// +listType=map
+	// +listMapKey=providerConfigName
+	// +optional
+	Overrides []ReleaseSetOverride `json:"overrides,omitempty"`
+	// Rollout controls how changes roll out to the generated Releases.
+	// +optional
+	Rollout ReleaseSetRollout `json:"rollout,omitempty"`
+}
+
+// A ReleaseSetMemberStatus is the observed state of the Release generated
+// for a provider config.
+type ReleaseSetMemberStatus struct {
+	// ProviderConfig is the name of the provider config.
+	ProviderConfig string `json:"providerConfig"`
+	// Release is the name of the generated Release.
+	Release string `json:"release"`
+	// Version is the deployed chart version.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Revision is the deployed revision of the Helm release.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// UpToDate is true if the Release matches the current ReleaseSet.
+	UpToDate bool `json:"upToDate"`
+	// Available is true if the Release is available at its current
+	// generation.
+	Available bool `json:"available"`
+	// Wave is the name of the rollout wave of the provider config.
+	// +optional
+	Wave string `json:"wave,omitempty"`
+	// Failed is true if the Release failed during the current rollout.
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+}
+
+// A RolloutPhase is the phase of the rollout of a ReleaseSet.
+type RolloutPhase string
+
+// Rollout phases.
+const (
+	// RolloutProgressing means the Releases of the current wave are being
+	// updated.
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutSoaking means the Releases of the current wave are available,
+	// and the rollout waits for the soak duration before starting the next
+	// wave.
+	RolloutSoaking RolloutPhase = "Soaking"
+	// RolloutHalted means too many Releases failed, and no more Releases are
+	// updated until the ReleaseSet changes.
+	RolloutHalted RolloutPhase = "Halted"
+	// RolloutCompleted means every Release is up to date and available.
+	RolloutCompleted RolloutPhase = "Completed"
+)
+
+// A ReleaseSetRolloutStatus is the state of the rollout of the current
+// template and overrides of a ReleaseSet.
+type ReleaseSetRolloutStatus struct {
+	// Revision identifies the template and overrides being rolled out.
+	Revision string `json:"revision"`
+	// Phase of the rollout.
+	Phase RolloutPhase `json:"phase"`
+	// Wave is the index of the current wave.
+	Wave int32 `json:"wave"`
+	// WaveName is the name of the current wave.
+	// +optional
+	WaveName string `json:"waveName,omitempty"`
+	// WaveStartTime is when the current wave started.
+	// +optional
+	WaveStartTime *metav1.Time `json:"waveStartTime,omitempty"`
+	// SoakStartTime is when every Release of the current wave became
+	// available.
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Failed lists the provider configs whose Releases failed.
+	// +optional
+	Failed []string `json:"failed,omitempty"`
+	// RolledBack is true if the Releases updated by the halted rollout were
+	// rolled back.
+	// +optional
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// Message describes the state of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ReleaseSetStatus represents the observed state of a ReleaseSet.
+type ReleaseSetStatus struct {
+	xpv2.ConditionedStatus `json:",inline"`
+	// Releases reports the generated Releases, ordered by provider config.
+	// +optional
+	Releases []ReleaseSetMemberStatus `json:"releases,omitempty"`
+	// Total is the number of selected provider configs.
+	// +optional
+	Total int32 `json:"total,omitempty"`
+	// UpToDate is the number of Releases that match the current ReleaseSet.
+	// +optional
+	UpToDate int32 `json:"upToDate,omitempty"`
+	// Available is the number of Releases that are up to date and available.
+	// +optional
+	Available int32 `json:"available,omitempty"`
+	// Rollout is the state of the rollout of the current template and
+	// overrides.
+	// +optional
+	Rollout *ReleaseSetRolloutStatus `json:"rollout,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ReleaseSet deploys a chart to every cluster selected by label through
+// its ProviderConfigs or ClusterProviderConfigs. It generates one Release per
+// selected provider config and rolls changes out to them in waves, halting
+// when too many of them fail.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CHART",type="string",JSONPath=".spec.template.spec.forProvider.chart.name"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.template.spec.forProvider.chart.version"
+// +kubebuilder:printcolumn:name="TOTAL",type="integer",JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="UP-TO-DATE",type="integer",JSONPath=".status.upToDate"
+// +kubebuilder:printcolumn:name="AVAILABLE",type="integer",JSONPath=".status.available"
+// +kubebuilder:printcolumn:name="ROLLOUT",type="string",JSONPath=".status.rollout.phase"
+// +kubebuilder:printcolumn:name="WAVE",type="string",JSONPath=".status.rollout.waveName"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,helm}
+type ReleaseSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReleaseSetSpec   `json:"spec"`
+	Status ReleaseSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleaseSetList contains a list of ReleaseSet
+type ReleaseSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReleaseSet `json:"items"`
+}
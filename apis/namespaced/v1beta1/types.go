@@ -17,12 +17,47 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kconfig "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/config"
 	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
 )
 
+// A ProviderConfigSpec defines the desired state of a ProviderConfig.
+type ProviderConfigSpec struct {
+	kconfig.ProviderConfigSpec `json:",inline"`
+
+	// Limits of the installs, upgrades and requests of Releases against the
+	// target cluster. Provider configs connecting to the same API server with
+	// the same CA share their target cluster, which is limited by the
+	// strictest of their limits. Defaults to the limits the provider is
+	// started with.
+	// +optional
+	Limits *TargetLimits `json:"limits,omitempty"`
+}
+
+// TargetLimits limit the installs, upgrades and requests of Releases against
+// their target cluster.
+type TargetLimits struct {
+	// MaxConcurrentOperations is the maximum number of installs and upgrades
+	// running against the target cluster at the same time. Unlimited if 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentOperations *int `json:"maxConcurrentOperations,omitempty"`
+
+	// QPS is the maximum rate of requests per second to the target cluster,
+	// e.g. 20 or 0.5. Unlimited if 0.
+	// +optional
+	QPS *resource.Quantity `json:"qps,omitempty"`
+
+	// Burst is the maximum burst of requests to the target cluster. Defaults
+	// to the QPS if 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst *int `json:"burst,omitempty"`
+}
+
 // A ProviderConfigStatus defines the status of a Provider.
 type ProviderConfigStatus struct {
 	xpv2.ProviderConfigStatus `json:",inline"`
@@ -40,8 +75,8 @@ type ProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   kconfig.ProviderConfigSpec `json:"spec"`
-	Status ProviderConfigStatus       `json:"status,omitempty"`
+	Spec   ProviderConfigSpec   `json:"spec"`
+	Status ProviderConfigStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -91,8 +126,8 @@ type ClusterProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   kconfig.ProviderConfigSpec `json:"spec"`
-	Status ProviderConfigStatus       `json:"status,omitempty"`
+	Spec   ProviderConfigSpec   `json:"spec"`
+	Status ProviderConfigStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -65,6 +65,9 @@ import (
 	"github.com/crossplane-contrib/provider-helm/internal/bootcheck"
 	clustercontroller "github.com/crossplane-contrib/provider-helm/pkg/controller/cluster"
 	namespacedcontroller "github.com/crossplane-contrib/provider-helm/pkg/controller/namespaced"
+	helmmetrics "github.com/crossplane-contrib/provider-helm/pkg/metrics"
+	"github.com/crossplane-contrib/provider-helm/pkg/throttle"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 	"github.com/crossplane-contrib/provider-helm/pkg/version"
 )
 
@@ -92,6 +95,12 @@ func main() {
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
 		enableChangeLogs         = app.Flag("enable-changelogs", "Enable support for capturing change logs during reconciliation.").Default("false").Envar("ENABLE_CHANGE_LOGS").Bool()
 		changelogsSocketPath     = app.Flag("changelogs-socket-path", "Path for changelogs socket (if enabled)").Default("/var/run/changelogs/changelogs.sock").Envar("CHANGELOGS_SOCKET_PATH").String()
+		maxConcurrentOperations  = app.Flag("max-concurrent-operations", "The default maximum number of installs and upgrades running against a single target cluster at the same time. Unlimited if 0. Overridden by spec.limits.maxConcurrentOperations of a ProviderConfig.").Default("0").Int()
+		targetQPS                = app.Flag("target-qps", "The default maximum rate per second of requests sent to a single target cluster. Unlimited if 0. Overridden by spec.limits.qps of a ProviderConfig.").Default("0").Float32()
+		targetBurst              = app.Flag("target-burst", "The default maximum burst of requests sent to a single target cluster. Defaults to the QPS if 0. Overridden by spec.limits.burst of a ProviderConfig.").Default("0").Int()
+		otlpEndpoint             = app.Flag("otlp-endpoint", "The host and port of an OTLP gRPC collector traces of reconciles and Helm operations are exported to. Tracing is disabled if empty.").Default("").Envar("OTLP_ENDPOINT").String()
+		otlpInsecure             = app.Flag("otlp-insecure", "Export traces to the OTLP collector without TLS.").Default("false").Envar("OTLP_INSECURE").Bool()
+		traceSampleRatio         = app.Flag("trace-sample-ratio", "The ratio of reconciles traced, between 0 and 1.").Default("1").Float64()
 		enableSecretCache        = app.Flag("enable-secret-cache", "Enable caching of Secret objects. When true, Secrets are served from the informer cache instead of direct API calls. This reduces API server load but increases memory usage.").Default("true").Envar("ENABLE_SECRET_CACHE").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -107,6 +116,14 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    *otlpEndpoint,
+		Insecure:    *otlpInsecure,
+		SampleRatio: *traceSampleRatio,
+		Version:     version.Version,
+	})
+	kingpin.FatalIfError(err, "Cannot set up tracing")
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -164,6 +181,7 @@ func main() {
 
 	metrics.Registry.MustRegister(mm)
 	metrics.Registry.MustRegister(sm)
+	helmmetrics.Register(metrics.Registry)
 
 	mo := controller.MetricOptions{
 		PollStateMetricInterval: *pollStateMetricInterval,
@@ -171,6 +189,12 @@ func main() {
 		MRStateMetrics:          sm,
 	}
 
+	throttle.Targets.SetDefaults(throttle.Limits{
+		MaxConcurrentOperations: *maxConcurrentOperations,
+		QPS:                     *targetQPS,
+		Burst:                   *targetBurst,
+	})
+
 	ctx := context.Background()
 	clusterOpts := controller.Options{
 		Logger:                  log,
@@ -231,7 +255,16 @@ func main() {
 	// Setup health probes
 	kingpin.FatalIfError(setupHealthProbes(mgr), "Cannot setup health probes")
 
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// Flush the spans of the last reconciles before exiting.
+	sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(sctx); err != nil {
+		log.Info("Cannot flush traces", "error", err)
+	}
+
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 }
 
 // UseISO8601 sets the logger to use ISO8601 timestamp format
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/chart/loader/archive"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+)
+
+// AnnotationContentDigest is the annotation of charts from ConfigMaps,
+// Secrets or inline that records the digest of their content. It is recorded
+// in the releases deployed from the charts.
+const AnnotationContentDigest = "helm.crossplane.io/content-digest"
+
+const (
+	errChartSourceConflict      = "spec.forProvider.chart.fromConfigMap, fromSecret and inline cannot be combined with each other or with the other chart sources"
+	errNoChartArchive           = "no chart archive resolver configured"
+	errFailedToGetChartArchive  = "failed to get chart archive"
+	errFailedToLoadChartArchive = "failed to load chart archive"
+	errFailedToLoadInlineChart  = "failed to load inline chart"
+)
+
+// ChartArchive resolves the packaged chart of a Release that is not pulled
+// from a repository, e.g. from a ConfigMap.
+type ChartArchive func() ([]byte, error)
+
+// ArchiveDigest returns the digest of a packaged chart.
+func ArchiveDigest(b []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
+
+// InlineDigest returns the digest of the files of an inline chart.
+func InlineDigest(files map[string]string) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		// Lengths delimit names and contents, so that no two sets of files
+		// have the same digest.
+		_, _ = fmt.Fprintf(h, "%d:%s%d:%s", len(name), name, len(files[name]), files[name])
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// loadArchiveChart loads the packaged chart resolved by the client.
+func (hc *client) loadArchiveChart() (*chart.Chart, error) {
+	if hc.chartArchive == nil {
+		return nil, errors.New(errNoChartArchive)
+	}
+	b, err := hc.chartArchive()
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetChartArchive)
+	}
+	chrt, err := loader.LoadArchive(bytes.NewReader(b))
+	if err != nil {
+		return nil, withFailure(FailureInvalidChart, errors.Wrap(err, errFailedToLoadChartArchive))
+	}
+	annotateDigest(chrt, ArchiveDigest(b))
+	return chrt, nil
+}
+
+// loadInlineChart loads a chart from its files.
+func loadInlineChart(files map[string]string) (*chart.Chart, error) {
+	buffered := make([]*archive.BufferedFile, 0, len(files))
+	for name, content := range files {
+		buffered = append(buffered, &archive.BufferedFile{Name: name, Data: []byte(content)})
+	}
+	sort.Slice(buffered, func(i, j int) bool { return buffered[i].Name < buffered[j].Name })
+	chrt, err := loader.LoadFiles(buffered)
+	if err != nil {
+		return nil, withFailure(FailureInvalidChart, errors.Wrap(err, errFailedToLoadInlineChart))
+	}
+	annotateDigest(chrt, InlineDigest(files))
+	return chrt, nil
+}
+
+func annotateDigest(chrt *chart.Chart, digest string) {
+	if chrt.Metadata.Annotations == nil {
+		chrt.Metadata.Annotations = map[string]string{}
+	}
+	chrt.Metadata.Annotations[AnnotationContentDigest] = digest
+}
@@ -0,0 +1,130 @@
+package helm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+
+	clusterv1beta1 "github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+func TestPullAndLoadChartFromContent(t *testing.T) {
+	p, err := chartutil.Save(testChart("packaged", "1.0.0"), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	packaged, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inline := map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: inline\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: inline\n",
+	}
+
+	type want struct {
+		name    string
+		digest  string
+		failure Failure
+		err     bool
+	}
+	cases := map[string]struct {
+		reason  string
+		chart   clusterv1beta1.ChartSpec
+		archive ChartArchive
+		want    want
+	}{
+		"Archive": {
+			reason:  "A packaged chart should be loaded from the resolved archive.",
+			chart:   clusterv1beta1.ChartSpec{FromConfigMap: &clusterv1beta1.DataKeySelector{}},
+			archive: func() ([]byte, error) { return packaged, nil },
+			want:    want{name: "packaged", digest: ArchiveDigest(packaged)},
+		},
+		"ArchiveNotResolved": {
+			reason:  "An error resolving the archive should be returned.",
+			chart:   clusterv1beta1.ChartSpec{FromSecret: &clusterv1beta1.DataKeySelector{}},
+			archive: func() ([]byte, error) { return nil, errors.New("boom") },
+			want:    want{err: true},
+		},
+		"ArchiveInvalid": {
+			reason:  "An archive that is not a packaged chart should fail as an invalid chart.",
+			chart:   clusterv1beta1.ChartSpec{FromSecret: &clusterv1beta1.DataKeySelector{}},
+			archive: func() ([]byte, error) { return []byte("not a chart"), nil },
+			want:    want{failure: FailureInvalidChart, err: true},
+		},
+		"Inline": {
+			reason: "A chart should be loaded from its inline files.",
+			chart:  clusterv1beta1.ChartSpec{Inline: inline},
+			want:   want{name: "inline", digest: InlineDigest(inline)},
+		},
+		"InlineInvalid": {
+			reason: "Inline files with no Chart.yaml should fail as an invalid chart.",
+			chart:  clusterv1beta1.ChartSpec{Inline: map[string]string{"values.yaml": ""}},
+			want:   want{failure: FailureInvalidChart, err: true},
+		},
+		"Conflict": {
+			reason: "A chart given by its content cannot also be pulled from a repository.",
+			chart:  clusterv1beta1.ChartSpec{Inline: inline, Repository: "https://charts.example.com"},
+			want:   want{failure: FailureInvalidChart, err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hc := &client{log: &mockLogger{}, chartArchive: tc.archive}
+			cr := &clusterv1beta1.Release{Spec: clusterv1beta1.ReleaseSpec{ForProvider: clusterv1beta1.ReleaseParameters{Chart: tc.chart}}}
+
+			chrt, err := hc.PullAndLoadChart(cr, &RepoCreds{})
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nPullAndLoadChart(...): unexpected error: %v", tc.reason, err)
+			}
+			if got := ClassifyFailure(err); got != tc.want.failure {
+				t.Errorf("\n%s\nClassifyFailure(...): want %q, got %q: %v", tc.reason, tc.want.failure, got, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.name, chrt.Name()); diff != "" {
+				t.Errorf("\n%s\nPullAndLoadChart(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, chrt.Metadata.Annotations[AnnotationContentDigest]); diff != "" {
+				t.Errorf("\n%s\nPullAndLoadChart(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInlineDigest(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      map[string]string
+		b      map[string]string
+		same   bool
+	}{
+		"Same": {
+			reason: "The same files should have the same digest.",
+			a:      map[string]string{"Chart.yaml": "a", "values.yaml": "b"},
+			b:      map[string]string{"values.yaml": "b", "Chart.yaml": "a"},
+			same:   true,
+		},
+		"ContentChanged": {
+			reason: "A change to the content of a file should change the digest.",
+			a:      map[string]string{"Chart.yaml": "a"},
+			b:      map[string]string{"Chart.yaml": "b"},
+		},
+		"Boundaries": {
+			reason: "Files whose names and contents concatenate to the same string should have different digests.",
+			a:      map[string]string{"ab": "c"},
+			b:      map[string]string{"a": "bc"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := InlineDigest(tc.a) == InlineDigest(tc.b); got != tc.same {
+				t.Errorf("\n%s\nInlineDigest(a) == InlineDigest(b): want %t, got %t", tc.reason, tc.same, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,17 @@
 package helm
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	ktype "sigs.k8s.io/kustomize/api/types"
+)
 
 // Args stores common options that can be passed to a Helm client on initialization
 type Args struct {
@@ -12,6 +23,9 @@ type Args struct {
 	Timeout time.Duration
 	// SkipCRDs skips CRDs creation during Helm release install or upgrade.
 	SkipCRDs bool
+	// CRDPolicy determines how the CRDs in the crds/ directory of a chart are
+	// managed. Defaults to Create.
+	CRDPolicy string
 	// InsecureSkipTLSVerify skips tls certificate checks for the chart download
 	InsecureSkipTLSVerify bool
 	// PlainHTTP uses HTTP connections for the chart download
@@ -25,4 +39,81 @@ type Args struct {
 	// field conflicts ("become sole manager") on install, upgrade, and
 	// rollback.
 	SSAForceConflicts bool
+	// Images overrides container images in the rendered manifests.
+	Images []ktype.Image
+	// Validators validate the rendered manifests before they are applied.
+	Validators []ManifestValidator
+	// Redaction keeps secret values out of the Helm release storage and logs.
+	Redaction *Redaction
+	// EncryptionKey encrypts the releases stored by Helm, if set.
+	EncryptionKey []byte
+	// StorageDriver Helm stores release state with. Defaults to Secret.
+	StorageDriver string
+	// SQLConnectionString is the connection string of the SQL storage driver.
+	SQLConnectionString string
+	// ControlPlaneSecrets store release state for the ControlPlane storage
+	// driver.
+	ControlPlaneSecrets corev1.SecretInterface
+	// StorageScope identifies the target cluster to the ControlPlane and SQL
+	// storage drivers, which keep the release state of each target cluster
+	// apart.
+	StorageScope string
+	// KeepKinds lists the kinds of resources kept on uninstall.
+	KeepKinds []string
+	// KeepHistory keeps the release history on uninstall.
+	KeepHistory bool
+	// DeletionPropagation is the policy used to delete the dependents of
+	// uninstalled resources, i.e. Background, Foreground or Orphan.
+	DeletionPropagation string
+	// Connection to the target cluster shared with other Helm clients. A
+	// new one is built for the client if nil.
+	Connection *Connection
+	// ClientKey identifies the Release the client is built for. Clients
+	// built for a key are cached on their Connection, and must not be used
+	// concurrently.
+	ClientKey string
+	// Trace is the span the spans of the operations of the client are
+	// children of, if any.
+	Trace trace.Span
+	// DependencyCredentials resolves the credentials of the repositories of
+	// chart dependencies that are not vendored. Dependencies are pulled
+	// anonymously if nil, unless they are in the repository of the chart.
+	DependencyCredentials DependencyCredentials
+	// ChartArchive resolves the packaged chart of a Release from a ConfigMap
+	// or a Secret.
+	ChartArchive ChartArchive
+}
+
+// clientConfig returns a hash of the arguments a client is built with. A
+// cached client is only reused while they stay the same. The others are
+// applied each time the client is used.
+func (a *Args) clientConfig() (string, error) {
+	b, err := json.Marshal(struct {
+		Namespace             string
+		Wait                  bool
+		Timeout               time.Duration
+		SkipCRDs              bool
+		CRDPolicy             string
+		InsecureSkipTLSVerify bool
+		PlainHTTP             bool
+		TakeOwnership         bool
+		MaxHistory            int
+		SSAForceConflicts     bool
+		Redaction             *Redaction
+		EncryptionKey         []byte
+		StorageDriver         string
+		SQLConnectionString   string
+		StorageScope          string
+		KeepHistory           bool
+		DeletionPropagation   string
+	}{
+		a.Namespace, a.Wait, a.Timeout, a.SkipCRDs, a.CRDPolicy, a.InsecureSkipTLSVerify, a.PlainHTTP, a.TakeOwnership,
+		a.MaxHistory, a.SSAForceConflicts, a.Redaction, a.EncryptionKey, a.StorageDriver, a.SQLConnectionString,
+		a.StorageScope, a.KeepHistory, a.DeletionPropagation,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, errHashClientConfig)
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
 }
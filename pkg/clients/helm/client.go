@@ -17,33 +17,63 @@ limitations under the License.
 package helm
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"helm.sh/helm/v4/pkg/action"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	"helm.sh/helm/v4/pkg/cli"
 	"helm.sh/helm/v4/pkg/kube"
+	"helm.sh/helm/v4/pkg/postrenderer"
 	"helm.sh/helm/v4/pkg/registry"
 	release "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	ktype "sigs.k8s.io/kustomize/api/types"
 
 	clusterv1beta1 "github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
 	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/metrics"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 const (
-	helmDriverSecret  = "secret"
-	chartContentCache = "/tmp/content-cache"
+	helmDriverSecret    = "secret"
+	helmDriverConfigMap = "configmap"
+	chartContentCache   = "/tmp/content-cache"
+)
+
+// Helm operations, as recorded in metrics.
+const (
+	operationInstall   = "install"
+	operationUpgrade   = "upgrade"
+	operationRollback  = "rollback"
+	operationUninstall = "uninstall"
+	operationTest      = "test"
+)
+
+// Spans of the client, as recorded in traces.
+const (
+	spanPullAndLoadChart = "PullAndLoadChart"
+	spanRender           = "KustomizationRender"
+	spanInstall          = "Install"
+	spanUpgrade          = "Upgrade"
+	spanRollback         = "Rollback"
+	spanUninstall        = "Uninstall"
+	spanTest             = "Test"
 )
 
 // chartCache is the directory where pulled chart tarballs are stored. It is
@@ -75,7 +105,12 @@ type Client interface {
 	Install(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
 	Upgrade(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
 	Rollback(release string) error
-	Uninstall(release string) error
+	// Uninstall uninstalls a release and returns the resources that were
+	// kept, as kind/name.
+	Uninstall(release string) ([]string, error)
+	// Test runs the test hooks of a release. Their outcome is stored with
+	// the release, which is returned even if a test failed.
+	Test(release string) (*release.Release, error)
 	PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*chart.Chart, error)
 }
 
@@ -87,13 +122,30 @@ type client struct {
 	upgradeClient   *action.Upgrade
 	rollbackClient  *action.Rollback
 	uninstallClient *action.Uninstall
+	testClient      *action.ReleaseTesting
 	loginClient     *action.RegistryLogin
+	releases        *storage.Storage
+	images          []ktype.Image
+	validators      []ManifestValidator
+	keepKinds       []string
+	crds            *crdApplier
+	config          *action.Configuration
+	discovery       *discoveryCache
+	redaction       *Redaction
+	trace           trace.Span
+	target          string
+	timeout         time.Duration
+
+	dependencyCredentials DependencyCredentials
+	chartArchive          ChartArchive
 }
 
 // ArgsApplier defines helm client arguments helper
 type ArgsApplier func(*Args)
 
-// NewClient returns a new Helm Client with provided config
+// NewClient returns a new Helm Client with provided config. A client built
+// for a key on a Connection is cached on it, and reused for the key as long as
+// it is configured the same.
 func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsApplier) (Client, error) {
 
 	args := &Args{}
@@ -101,23 +153,74 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 		apply(args)
 	}
 
-	rg := newRESTClientGetter(restConfig, args.Namespace)
+	if args.Connection == nil || args.ClientKey == "" {
+		return newClient(log, restConfig, args)
+	}
+	cfg, err := args.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	hc, err := args.Connection.client(args.ClientKey, cfg, func() (*client, error) {
+		return newClient(log, restConfig, args)
+	})
+	if err != nil {
+		return nil, err
+	}
+	hc.use(args)
+	return hc, nil
+}
+
+// use makes the client use the arguments that may change from one use of
+// the client to the next without building it again.
+func (hc *client) use(args *Args) {
+	hc.images = args.Images
+	hc.validators = args.Validators
+	hc.keepKinds = args.KeepKinds
+	hc.trace = args.Trace
+	hc.dependencyCredentials = args.DependencyCredentials
+	hc.chartArchive = args.ChartArchive
+}
+
+func newClient(log logging.Logger, restConfig *rest.Config, args *Args) (*client, error) {
+	t, err := args.Connection.target(restConfig, args.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	rg := t.getter
 
 	actionConfig := new(action.Configuration)
 	// Helm v4 discards its internal logs (including kstatus wait diagnostics)
 	// unless a handler is set, and Init copies the handler into the kube
 	// client and storage driver, so this must run before Init.
-	actionConfig.SetLogger(slogHandler{log: log})
-	// Always store helm state in the same cluster/namespace where chart is deployed
-	if err := actionConfig.Init(rg, args.Namespace, helmDriverSecret); err != nil {
+	actionConfig.SetLogger(slogHandler{log: log, redaction: args.Redaction})
+	// Helm state is stored in the same cluster/namespace where chart is
+	// deployed, unless a storage driver keeping it elsewhere is configured.
+	hd, err := helmDriver(args.StorageDriver)
+	if err != nil {
+		return nil, err
+	}
+	if err := actionConfig.Init(rg, args.Namespace, hd); err != nil {
 		return nil, errors.Wrap(err, errFailedToInitActionConfig)
 	}
-
-	rc, err := registry.NewClient()
+	sd, err := storageDriver(args, actionConfig.Logger().Handler())
 	if err != nil {
-		return nil, errors.Wrap(err, errFailedToCreateRegistryClient)
+		return nil, err
+	}
+	if sd != nil {
+		actionConfig.Releases = storage.Init(sd)
+	}
+	if len(args.EncryptionKey) > 0 {
+		d, err := newEncryptingDriver(actionConfig.Releases.Driver, args.EncryptionKey)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToInitActionConfig)
+		}
+		actionConfig.Releases.Driver = d
+	}
+	if args.Redaction != nil {
+		actionConfig.Releases.Driver = redactingDriver{Driver: actionConfig.Releases.Driver, values: args.Redaction.Values}
 	}
-	actionConfig.RegistryClient = rc
+
+	actionConfig.RegistryClient = t.registry
 
 	pc := action.NewPull(action.WithConfig(actionConfig))
 
@@ -158,11 +261,21 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 		waitStrategy = kube.StatusWatcherStrategy
 	}
 
+	skipCRDs := args.SkipCRDs || args.CRDPolicy == CRDPolicySkip
+	var crds *crdApplier
+	if replacesCRDs(args.CRDPolicy) && !skipCRDs {
+		d, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToCreateCRDClient)
+		}
+		crds = newCRDApplier(d, args.CRDPolicy)
+	}
+
 	ic := action.NewInstall(actionConfig)
 	ic.Namespace = args.Namespace
 	ic.WaitStrategy = waitStrategy
 	ic.Timeout = args.Timeout
-	ic.SkipCRDs = args.SkipCRDs
+	ic.SkipCRDs = skipCRDs
 	ic.InsecureSkipTLSVerify = args.InsecureSkipTLSVerify
 	ic.PlainHTTP = args.PlainHTTP
 	ic.TakeOwnership = args.TakeOwnership
@@ -171,7 +284,7 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 	uc := action.NewUpgrade(actionConfig)
 	uc.WaitStrategy = waitStrategy
 	uc.Timeout = args.Timeout
-	uc.SkipCRDs = args.SkipCRDs
+	uc.SkipCRDs = skipCRDs
 	uc.InsecureSkipTLSVerify = args.InsecureSkipTLSVerify
 	uc.PlainHTTP = args.PlainHTTP
 	uc.TakeOwnership = args.TakeOwnership
@@ -181,12 +294,18 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 	uic := action.NewUninstall(actionConfig)
 	uic.WaitStrategy = waitStrategy
 	uic.Timeout = args.Timeout
+	uic.KeepHistory = args.KeepHistory
+	uic.DeletionPropagation = strings.ToLower(args.DeletionPropagation)
 
 	rb := action.NewRollback(actionConfig)
 	rb.WaitStrategy = waitStrategy
 	rb.Timeout = args.Timeout
 	rb.ForceConflicts = args.SSAForceConflicts
 
+	tc := action.NewReleaseTesting(actionConfig)
+	tc.Namespace = args.Namespace
+	tc.Timeout = args.Timeout
+
 	lc := action.NewRegistryLogin(actionConfig)
 
 	return &client{
@@ -197,7 +316,22 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 		upgradeClient:   uc,
 		rollbackClient:  rb,
 		uninstallClient: uic,
+		testClient:      tc,
 		loginClient:     lc,
+		releases:        actionConfig.Releases,
+		images:          args.Images,
+		validators:      args.Validators,
+		keepKinds:       args.KeepKinds,
+		crds:            crds,
+		config:          actionConfig,
+		discovery:       rg.discovery,
+		redaction:       args.Redaction,
+		trace:           args.Trace,
+		target:          restConfig.Host,
+		timeout:         args.Timeout,
+
+		dependencyCredentials: args.DependencyCredentials,
+		chartArchive:          args.ChartArchive,
 	}, nil
 }
 
@@ -223,7 +357,12 @@ func getChartFileName(dir string) (string, error) {
 }
 
 // pullChartToCache pulls a chart into the cache and returns its absolute path.
-func (hc *client) pullChartToCache(chartUrl, chartName, chartVersion, chartRepo, chartDigest string, creds *RepoCreds) (string, error) {
+func (hc *client) pullChartToCache(chartUrl, chartName, chartVersion, chartRepo, chartDigest string, creds *RepoCreds) (chartFilePath string, err error) {
+	var size int64
+	defer func(start time.Time) {
+		metrics.ObserveChartPull(chartRepository(chartUrl, chartRepo), start, size, err)
+	}(time.Now())
+
 	tmpDir, err := os.MkdirTemp(chartCache, "")
 	if err != nil {
 		return "", err
@@ -242,15 +381,34 @@ func (hc *client) pullChartToCache(chartUrl, chartName, chartVersion, chartRepo,
 	if err != nil {
 		return "", err
 	}
-	chartFilePath := filepath.Join(chartCache, pulledName)
+	if fi, err := os.Stat(filepath.Join(tmpDir, pulledName)); err == nil {
+		size = fi.Size()
+	}
+	chartFilePath = filepath.Join(chartCache, pulledName)
 	if err := os.Rename(filepath.Join(tmpDir, pulledName), chartFilePath); err != nil {
 		return "", err
 	}
 	return chartFilePath, nil
 }
 
+// chartRepository returns the repository a chart is pulled from, without
+// the chart name and version of chart URLs.
+func chartRepository(chartUrl, chartRepo string) string {
+	if chartRepo != "" || chartUrl == "" {
+		return chartRepo
+	}
+	u, err := url.Parse(chartUrl)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + path.Dir(u.Path)
+}
+
 func (hc *client) pullChart(chartUrl, chartName, chartVersion, chartRepo, chartDigest string, creds *RepoCreds, chartDir string) error {
 	pc := hc.pullClient
+	// The pull client is shared by the pulls of a chart and its dependencies.
+	pc.RepoURL = ""
+	pc.Version = ""
 
 	chartRef := chartUrl
 	if chartUrl == "" {
@@ -271,7 +429,7 @@ func (hc *client) pullChart(chartUrl, chartName, chartVersion, chartRepo, chartD
 
 		effectiveDigest, err := resolveEffectiveDigest(urlDigest, chartDigest)
 		if err != nil {
-			return err
+			return withFailure(FailureInvalidChart, err)
 		}
 		// Append digest if present (per Helm PR #12690)
 		if effectiveDigest != "" {
@@ -286,14 +444,14 @@ func (hc *client) pullChart(chartUrl, chartName, chartVersion, chartRepo, chartD
 	if creds.Username != "" && creds.Password != "" {
 		err := hc.login(chartUrl, chartRepo, creds, pc.InsecureSkipTLSVerify)
 		if err != nil {
-			return err
+			return pullFailure(err)
 		}
 	}
 
 	o, err := pc.Run(chartRef)
 	hc.log.Debug(o)
 	if err != nil {
-		return errors.Wrap(err, errFailedToPullChart)
+		return pullFailure(errors.Wrap(err, errFailedToPullChart))
 	}
 	return nil
 }
@@ -323,6 +481,7 @@ func (hc *client) login(chartUrl, chartRepo string, creds *RepoCreds, insecure b
 func (hc *client) ensureChartCached(chartFilePath, chartUrl, chartName, chartVersion, chartRepo, chartDigest string, creds *RepoCreds) (string, error) {
 	if chartFilePath == "" {
 		hc.log.Debug("no cache path for chart", "URL", chartUrl, "name", chartName, "version", chartVersion, "repo", chartRepo, "digest", chartDigest)
+		metrics.ObserveChartCacheLookup(metrics.CacheMiss)
 		return hc.pullChartToCache(chartUrl, chartName, chartVersion, chartRepo, chartDigest, creds)
 	}
 	cachedPath := filepath.Join(chartCache, filepath.Base(chartFilePath))
@@ -330,6 +489,7 @@ func (hc *client) ensureChartCached(chartFilePath, chartUrl, chartName, chartVer
 	switch {
 	case os.IsNotExist(err):
 		hc.log.Debug("cache miss for chart", "cachedPath", cachedPath, "URL", chartUrl, "name", chartName, "version", chartVersion, "repo", chartRepo, "digest", chartDigest)
+		metrics.ObserveChartCacheLookup(metrics.CacheMiss)
 		return hc.pullChartToCache(chartUrl, chartName, chartVersion, chartRepo, chartDigest, creds)
 	case err != nil:
 		return "", errors.Wrap(err, errFailedToCheckIfLocalChartExists)
@@ -338,6 +498,7 @@ func (hc *client) ensureChartCached(chartFilePath, chartUrl, chartName, chartVer
 	}
 
 	hc.log.Debug("cache hit for chart", "cachedPath", cachedPath, "URL", chartUrl, "name", chartName, "version", chartVersion, "repo", chartRepo, "digest", chartDigest)
+	metrics.ObserveChartCacheLookup(metrics.CacheHit)
 	return cachedPath, nil
 }
 
@@ -351,9 +512,11 @@ func resolveEffectiveDigest(urlDigest, specDigest string) (string, error) {
 	return specDigest, nil
 }
 
-func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*chart.Chart, error) { //nolint:gocyclo
+func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (chrt *chart.Chart, err error) { //nolint:gocyclo
 	var chartFilePath, chartUrl, chartName, chartVersion, chartDigest, chartRepo string
-	var err error
+	var gitSrc *GitSource
+	var inline map[string]string
+	var pulled, fromArchive bool
 
 	switch r := mg.(type) {
 	case *clusterv1beta1.Release:
@@ -362,21 +525,70 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 		chartName = r.Spec.ForProvider.Chart.Name
 		chartRepo = r.Spec.ForProvider.Chart.Repository
 		chartDigest = r.Spec.ForProvider.Chart.Digest
+		if g := r.Spec.ForProvider.Chart.Git; g != nil {
+			gitSrc = &GitSource{URL: g.URL, Ref: g.Ref, Commit: g.Commit, Path: g.Path}
+		}
+		inline = r.Spec.ForProvider.Chart.Inline
+		fromArchive = r.Spec.ForProvider.Chart.FromConfigMap != nil || r.Spec.ForProvider.Chart.FromSecret != nil
 	case *namespacedv1beta1.Release:
 		chartUrl = r.Spec.ForProvider.Chart.URL
 		chartVersion = r.Spec.ForProvider.Chart.Version
 		chartName = r.Spec.ForProvider.Chart.Name
 		chartRepo = r.Spec.ForProvider.Chart.Repository
 		chartDigest = r.Spec.ForProvider.Chart.Digest
+		if g := r.Spec.ForProvider.Chart.Git; g != nil {
+			gitSrc = &GitSource{URL: g.URL, Ref: g.Ref, Commit: g.Commit, Path: g.Path}
+		}
+		inline = r.Spec.ForProvider.Chart.Inline
+		fromArchive = r.Spec.ForProvider.Chart.FromConfigMap != nil || r.Spec.ForProvider.Chart.FromSecret != nil
 	default:
 		return nil, errors.New("This object must be *clusterv1beta1.Release or *namespacedv1beta1.Release")
 	}
 
+	_, span := hc.startSpan(spanPullAndLoadChart,
+		tracing.AttrChart.String(chartName),
+		tracing.AttrVersion.String(chartVersion),
+		tracing.AttrRepository.String(chartRepository(chartUrl, chartRepo)))
+	defer func() { tracing.End(span, err) }()
+
+	if fromArchive || len(inline) > 0 {
+		if chartUrl != "" || chartRepo != "" || chartDigest != "" || gitSrc != nil || (fromArchive && len(inline) > 0) {
+			return nil, withFailure(FailureInvalidChart, errors.New(errChartSourceConflict))
+		}
+		if fromArchive {
+			chrt, err = hc.loadArchiveChart()
+		} else {
+			chrt, err = loadInlineChart(inline)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := hc.buildDependencies(chrt, "", creds); err != nil {
+			return nil, err
+		}
+		return chrt, nil
+	}
+
+	if gitSrc != nil {
+		if chartUrl != "" || chartRepo != "" || chartDigest != "" {
+			return nil, withFailure(FailureInvalidChart, errors.New(errGitChartConflict))
+		}
+		span.SetAttributes(tracing.AttrRepository.String(gitSrc.URL))
+		chart, err := hc.loadGitChart(*gitSrc, creds)
+		if err != nil {
+			return nil, err
+		}
+		if err := hc.buildDependencies(chart, "", creds); err != nil {
+			return nil, err
+		}
+		return chart, nil
+	}
+
 	// Validate: Digest only works with OCI registries
 	if chartDigest != "" {
 		isOCI := registry.IsOCI(chartUrl) || registry.IsOCI(chartRepo)
 		if !isOCI {
-			return nil, errors.New(errDigestNotSupportedForNonOCI)
+			return nil, withFailure(FailureInvalidChart, errors.New(errDigestNotSupportedForNonOCI))
 		}
 	}
 
@@ -387,6 +599,7 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 		if err != nil {
 			return nil, err
 		}
+		pulled = true
 	case registry.IsOCI(chartUrl):
 		u, v, urlDigest, err := resolveOCIChartVersionAndDigest(chartUrl)
 		if err != nil {
@@ -396,7 +609,7 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 		// validate
 		effectiveDigest, err := resolveEffectiveDigest(urlDigest, chartDigest)
 		if err != nil {
-			return nil, err
+			return nil, withFailure(FailureInvalidChart, err)
 		}
 
 		switch {
@@ -411,6 +624,7 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 			if err != nil {
 				return nil, err
 			}
+			pulled = true
 		default:
 			chartFilePath = resolveChartFilePath(path.Base(u.Path), v)
 		}
@@ -425,9 +639,9 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 		// No URL: resolve from spec Repository + Name + Version + (optionally Digest)
 		switch {
 		case chartName == "":
-			return nil, errors.New(errNoChartName)
+			return nil, withFailure(FailureInvalidChart, errors.New(errNoChartName))
 		case chartRepo == "":
-			return nil, errors.New(errNoChartRepository)
+			return nil, withFailure(FailureInvalidChart, errors.New(errNoChartRepository))
 		case chartDigest != "":
 			chartFilePath = resolveCachedChartPathWithDigest(chartName, chartDigest)
 		default:
@@ -435,9 +649,12 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 		}
 	}
 
-	chartFilePath, err = hc.ensureChartCached(chartFilePath, chartUrl, chartName, chartVersion, chartRepo, chartDigest, creds)
-	if err != nil {
-		return nil, err
+	// Charts pulled in their latest version are never looked up in the cache.
+	if !pulled {
+		chartFilePath, err = hc.ensureChartCached(chartFilePath, chartUrl, chartName, chartVersion, chartRepo, chartDigest, creds)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Load chart from cache using safe path construction
@@ -446,9 +663,33 @@ func (hc *client) PullAndLoadChart(mg resource.Managed, creds *RepoCreds) (*char
 	if err != nil {
 		return nil, errors.Wrap(err, errFailedToLoadChart)
 	}
+	if err := hc.buildDependencies(chart, chartRepository(chartUrl, chartRepo), creds); err != nil {
+		return nil, err
+	}
 	return chart, nil
 }
 
+// postRenderer returns the post-renderer for an install or upgrade, or nil if
+// the rendered manifests need neither to be modified nor validated.
+func (hc *client) postRenderer(ctx context.Context, patches []ktype.Patch) postrenderer.PostRenderer {
+	var pr postrenderer.PostRenderer
+	if len(patches) > 0 || len(hc.images) > 0 {
+		pr = &KustomizationRender{
+			patches: patches,
+			images:  hc.images,
+			logger:  hc.log,
+			ctx:     ctx,
+		}
+	}
+	if len(hc.validators) > 0 {
+		pr = &validatingRender{
+			next:       pr,
+			validators: hc.validators,
+		}
+	}
+	return pr
+}
+
 func (hc *client) GetLastRelease(name string) (*release.Release, error) {
 	r, err := hc.getClient.Run(name)
 	if err != nil {
@@ -461,17 +702,41 @@ func (hc *client) GetLastRelease(name string) (*release.Release, error) {
 	return rel, nil
 }
 
-func (hc *client) Install(name string, chrt *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+func (hc *client) Install(name string, chrt *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (rel *release.Release, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(operationInstall, chrt.Name(), start, err) }(time.Now())
+	ctx, span := hc.startSpan(spanInstall, chartAttributes(name, chrt)...)
+	defer func() { tracing.End(span, hc.redaction.MaskError(err)) }()
+
 	hc.installClient.ReleaseName = name
 
-	if len(patches) > 0 {
-		hc.installClient.PostRenderer = &KustomizationRender{
-			patches: patches,
-			logger:  hc.log,
+	// The client may be reused, so the post renderer of a previous install
+	// must not be left in place.
+	hc.installClient.PostRenderer = hc.postRenderer(ctx, patches)
+
+	if hc.crds != nil || !hc.installClient.SkipCRDs {
+		if err := hc.validateCRDs(chrt); err != nil {
+			return nil, err
+		}
+	}
+	if hc.crds != nil {
+		if err := hc.applyCRDs(ctx, chrt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Helm discovers the target cluster again after it installed the CRDs
+	// of a chart, so that the chart can use them.
+	installsCRDs := !hc.installClient.SkipCRDs && len(chrt.CRDObjects()) > 0
+	if !installsCRDs {
+		if err := hc.useCachedCapabilities(); err != nil {
+			return nil, err
 		}
 	}
 
 	r, err := hc.installClient.Run(chrt, vals)
+	if installsCRDs || isNoMatch(err) {
+		hc.invalidateDiscovery()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -482,18 +747,34 @@ func (hc *client) Install(name string, chrt *chart.Chart, vals map[string]interf
 	return rel, nil
 }
 
-func (hc *client) Upgrade(name string, chrt *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+func (hc *client) Upgrade(name string, chrt *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (rel *release.Release, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(operationUpgrade, chrt.Name(), start, err) }(time.Now())
+	ctx, span := hc.startSpan(spanUpgrade, chartAttributes(name, chrt)...)
+	defer func() { tracing.End(span, hc.redaction.MaskError(err)) }()
+
 	// Reset values so that source of truth for desired state is always the CR itself
 	hc.upgradeClient.ResetValues = true
 
-	if len(patches) > 0 {
-		hc.upgradeClient.PostRenderer = &KustomizationRender{
-			patches: patches,
-			logger:  hc.log,
+	hc.upgradeClient.PostRenderer = hc.postRenderer(ctx, patches)
+
+	// Helm never upgrades the CRDs in the crds/ directory of a chart.
+	if hc.crds != nil {
+		if err := hc.validateCRDs(chrt); err != nil {
+			return nil, err
+		}
+		if err := hc.applyCRDs(ctx, chrt); err != nil {
+			return nil, err
 		}
 	}
 
+	if err := hc.useCachedCapabilities(); err != nil {
+		return nil, err
+	}
+
 	r, err := hc.upgradeClient.Run(name, chrt, vals)
+	if isNoMatch(err) {
+		hc.invalidateDiscovery()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -505,12 +786,129 @@ func (hc *client) Upgrade(name string, chrt *chart.Chart, vals map[string]interf
 }
 
 func (hc *client) Rollback(name string) error {
-	return hc.rollbackClient.Run(name)
+	start, chart := time.Now(), hc.releaseChart(name)
+	_, span := hc.startSpan(spanRollback, tracing.AttrRelease.String(name), tracing.AttrChart.String(chart))
+	err := hc.rollbackClient.Run(name)
+	if isNoMatch(err) {
+		hc.invalidateDiscovery()
+	}
+	metrics.ObserveOperation(operationRollback, chart, start, err)
+	tracing.End(span, hc.redaction.MaskError(err))
+	return err
 }
 
-func (hc *client) Uninstall(name string) error {
-	_, err := hc.uninstallClient.Run(name)
-	return err
+// applyCRDs applies the CRDs of a chart. Like the Helm actions, applying
+// them is bounded by the timeout of the client, as the context of the client
+// is not bound to a reconcile.
+func (hc *client) applyCRDs(ctx context.Context, chrt *chart.Chart) error {
+	if hc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hc.timeout)
+		defer cancel()
+	}
+	if err := hc.crds.Apply(ctx, chrt); err != nil {
+		return err
+	}
+	hc.invalidateDiscovery()
+	return nil
+}
+
+// useCachedCapabilities makes Helm render charts with the cached capabilities
+// of the target cluster, rather than discovering it again.
+func (hc *client) useCachedCapabilities() error {
+	if hc.discovery == nil {
+		return nil
+	}
+	caps, err := hc.discovery.Capabilities()
+	if err != nil {
+		return err
+	}
+	hc.config.Capabilities = caps.Copy()
+	return nil
+}
+
+// invalidateDiscovery makes the next Helm client discover the target cluster
+// again, e.g. because a kind it did not know about was installed since.
+func (hc *client) invalidateDiscovery() {
+	if hc.discovery != nil {
+		hc.discovery.Invalidate()
+	}
+}
+
+func (hc *client) Uninstall(name string) ([]string, error) {
+	start, chart := time.Now(), hc.releaseChart(name)
+	_, span := hc.startSpan(spanUninstall, tracing.AttrRelease.String(name), tracing.AttrChart.String(chart))
+	kept, err := hc.keptResources(name)
+	if err != nil {
+		metrics.ObserveOperation(operationUninstall, chart, start, err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	_, err = hc.uninstallClient.Run(name)
+	if isNoMatch(err) {
+		hc.invalidateDiscovery()
+	}
+	metrics.ObserveOperation(operationUninstall, chart, start, err)
+	tracing.End(span, hc.redaction.MaskError(err))
+	return kept, err
+}
+
+func (hc *client) Test(name string) (rel *release.Release, err error) {
+	start, chart := time.Now(), hc.releaseChart(name)
+	_, span := hc.startSpan(spanTest, tracing.AttrRelease.String(name), tracing.AttrChart.String(chart))
+	defer func() {
+		metrics.ObserveOperation(operationTest, chart, start, err)
+		tracing.End(span, hc.redaction.MaskError(err))
+	}()
+	r, shutdown, err := hc.testClient.Run(name)
+	// Shutting down deletes the test hooks as their deletion policies say.
+	if serr := shutdown(); err == nil {
+		err = serr
+	}
+	if r == nil {
+		return nil, err
+	}
+	rel, ok := r.(*release.Release)
+	if !ok {
+		return nil, errors.Errorf("unexpected release type %T", r)
+	}
+	return rel, err
+}
+
+// startSpan starts a span of an operation of the client on its target
+// cluster.
+func (hc *client) startSpan(name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx := context.Background()
+	if hc.trace != nil {
+		ctx = trace.ContextWithSpan(ctx, hc.trace)
+	}
+	return tracing.Start(ctx, name, append(attrs, tracing.AttrTarget.String(hc.target))...)
+}
+
+// chartAttributes returns the span attributes of a release of a chart.
+func chartAttributes(name string, chrt *chart.Chart) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{tracing.AttrRelease.String(name)}
+	if chrt != nil && chrt.Metadata != nil {
+		attrs = append(attrs, tracing.AttrChart.String(chrt.Metadata.Name), tracing.AttrVersion.String(chrt.Metadata.Version))
+	}
+	return attrs
+}
+
+// releaseChart returns the name of the chart of the last revision of a
+// release, or an empty string if it is unknown.
+func (hc *client) releaseChart(name string) string {
+	if hc.releases == nil {
+		return ""
+	}
+	r, err := hc.releases.Last(name)
+	if err != nil {
+		return ""
+	}
+	rel, ok := r.(*release.Release)
+	if !ok || rel == nil || rel.Chart == nil {
+		return ""
+	}
+	return rel.Chart.Name()
 }
 
 // resolveOCIChartVersionAndDigest extracts version and digest from OCI chart URL.
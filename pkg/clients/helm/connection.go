@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	kconfig "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/config"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"helm.sh/helm/v4/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errGetCredentialsSecret = "cannot get credentials secret of provider config"
+	errHashProviderConfig   = "cannot hash provider config"
+	errHashClientConfig     = "cannot hash helm client config"
+)
+
+// idleAfter is how long an unused Connection, or Helm client of a Connection,
+// is kept, e.g. after its provider config or Release was deleted.
+const idleAfter = time.Hour
+
+// A Connection to a target cluster, shared by all Helm clients built for
+// the Releases of a provider config.
+type Connection struct {
+	// Kube is a client of the target cluster.
+	Kube kclient.Client
+	// RESTConfig is the config Helm clients of the target cluster use.
+	RESTConfig *rest.Config
+
+	discovery *discoveryCache
+
+	mu      sync.Mutex
+	targets map[string]*target
+	clients map[string]*clientEntry
+	now     func() time.Time
+}
+
+// NewConnection returns a Connection to the target cluster of the supplied
+// client and config.
+func NewConnection(kube kclient.Client, rc *rest.Config) *Connection {
+	return &Connection{
+		Kube:       kube,
+		RESTConfig: rc,
+		discovery:  newDiscoveryCache(rc),
+		targets:    map[string]*target{},
+		clients:    map[string]*clientEntry{},
+		now:        time.Now,
+	}
+}
+
+type clientEntry struct {
+	config string
+	client *client
+	used   time.Time
+}
+
+// client returns the Helm client cached for the supplied key if it was built
+// with the supplied config, and otherwise builds and caches a new one. Clients
+// that were not used for a while are dropped.
+func (c *Connection) client(key, config string, build func() (*client, error)) (*client, error) {
+	c.mu.Lock()
+	now := c.now()
+	for k, e := range c.clients {
+		if now.Sub(e.used) > idleAfter {
+			delete(c.clients, k)
+		}
+	}
+	e, ok := c.clients[key]
+	if ok && e.config == config {
+		e.used = now
+		c.mu.Unlock()
+		return e.client, nil
+	}
+	c.mu.Unlock()
+
+	// Building a client takes the lock to get its target. Clients of a key
+	// are not built concurrently, as they are not used concurrently.
+	hc, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients[key] = &clientEntry{config: config, client: hc, used: now}
+	return hc, nil
+}
+
+// A target holds the clients Helm uses to manage the releases of a namespace
+// of a target cluster.
+type target struct {
+	getter   *restClientGetter
+	registry *registry.Client
+}
+
+func newTarget(rc *rest.Config, namespace string, dc *discoveryCache) (*target, error) {
+	r, err := registry.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateRegistryClient)
+	}
+	return &target{getter: newRESTClientGetter(rc, namespace, dc), registry: r}, nil
+}
+
+// target returns the shared target of the namespace, or a new one if the
+// Connection is nil.
+func (c *Connection) target(rc *rest.Config, namespace string) (*target, error) {
+	if c == nil {
+		return newTarget(rc, namespace, newDiscoveryCache(rc))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.targets[namespace]; ok {
+		return t, nil
+	}
+	t, err := newTarget(c.RESTConfig, namespace, c.discovery)
+	if err != nil {
+		return nil, err
+	}
+	c.targets[namespace] = t
+	return t, nil
+}
+
+type connectionEntry struct {
+	mu      sync.Mutex
+	version string
+	conn    *Connection
+	used    time.Time
+}
+
+// A ConnectionCache caches a Connection per provider config.
+type ConnectionCache struct {
+	mu      sync.Mutex
+	entries map[string]*connectionEntry
+	now     func() time.Time
+}
+
+// NewConnectionCache returns an empty ConnectionCache.
+func NewConnectionCache() *ConnectionCache {
+	return &ConnectionCache{entries: map[string]*connectionEntry{}, now: time.Now}
+}
+
+// Get returns the Connection cached for the supplied key at the supplied
+// version. It builds and caches a new one if there is none, or if the cached
+// one is of a different version. Concurrent callers wait for a Connection
+// being built rather than building their own. Connections that were not used
+// for a while are dropped. A nil cache builds a new Connection on every call.
+func (c *ConnectionCache) Get(key, version string, build func() (*Connection, error)) (*Connection, error) {
+	if c == nil {
+		return build()
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	for k, e := range c.entries {
+		if k != key && now.Sub(e.used) > idleAfter {
+			delete(c.entries, k)
+		}
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &connectionEntry{}
+		c.entries[key] = e
+	}
+	e.used = now
+	c.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn != nil && e.version == version {
+		return e.conn, nil
+	}
+	conn, err := build()
+	if err != nil {
+		return nil, err
+	}
+	e.conn, e.version = conn, version
+	return conn, nil
+}
+
+// ProviderConfigVersion returns a version of the supplied provider config
+// that changes whenever its spec or one of its credentials secrets changes.
+func ProviderConfigVersion(ctx context.Context, crClient kclient.Client, pc kclient.Object, spec kconfig.ProviderConfigSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, errHashProviderConfig)
+	}
+	h := sha256.New()
+	h.Write([]byte(pc.GetUID()))
+	h.Write(b)
+
+	refs := []xpv2.CommonCredentialSelectors{spec.Credentials.CommonCredentialSelectors}
+	if spec.Identity != nil {
+		refs = append(refs, spec.Identity.CommonCredentialSelectors)
+	}
+	for _, ref := range refs {
+		if ref.SecretRef == nil {
+			continue
+		}
+		s := &corev1.Secret{}
+		if err := crClient.Get(ctx, types.NamespacedName{Namespace: ref.SecretRef.Namespace, Name: ref.SecretRef.Name}, s); err != nil {
+			return "", errors.Wrap(err, errGetCredentialsSecret)
+		}
+		h.Write([]byte(s.GetUID()))
+		h.Write([]byte(s.GetResourceVersion()))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,178 @@
+package helm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kconfig "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/config"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
+)
+
+func TestConnectionCacheGet(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	c := NewConnectionCache()
+	builds := 0
+	build := func() (*Connection, error) {
+		builds++
+		return NewConnection(nil, &rest.Config{}), nil
+	}
+
+	a, _ := c.Get("pc", "1", build)
+	b, _ := c.Get("pc", "1", build)
+	if a != b || builds != 1 {
+		t.Errorf("Get(...): want the cached connection, got %d builds", builds)
+	}
+
+	if _, err := c.Get("pc", "2", func() (*Connection, error) { return nil, errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("Get(...): want error %v, got %v", errBoom, err)
+	}
+
+	d, _ := c.Get("pc", "2", build)
+	if d == a || builds != 2 {
+		t.Errorf("Get(...): want a new connection for a new version, got %d builds", builds)
+	}
+
+	// Connections of other provider configs unused for a while are dropped.
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.Get("other", "1", build)
+	now = now.Add(idleAfter + time.Second)
+	c.Get("pc", "2", build)
+	if _, ok := c.entries["other"]; ok {
+		t.Errorf("Get(...): want idle connections dropped")
+	}
+
+	var none *ConnectionCache
+	none.Get("pc", "2", build)
+	none.Get("pc", "2", build)
+	if builds != 5 {
+		t.Errorf("Get(...): want a nil cache to build every connection, got %d builds", builds)
+	}
+}
+
+func TestConnectionClient(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	c := NewConnection(nil, &rest.Config{})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	builds := 0
+	build := func() (*client, error) {
+		builds++
+		return &client{}, nil
+	}
+
+	a, _ := c.client("release", "1", build)
+	b, _ := c.client("release", "1", build)
+	if a != b || builds != 1 {
+		t.Errorf("client(...): want the cached client, got %d builds", builds)
+	}
+
+	if _, err := c.client("release", "2", func() (*client, error) { return nil, errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("client(...): want error %v, got %v", errBoom, err)
+	}
+
+	d, _ := c.client("release", "2", build)
+	if d == a || builds != 2 {
+		t.Errorf("client(...): want a new client for a new config, got %d builds", builds)
+	}
+
+	// Clients of deleted Releases are dropped once unused for a while.
+	now = now.Add(idleAfter + time.Second)
+	c.client("other", "1", build)
+	if _, ok := c.clients["release"]; ok {
+		t.Errorf("client(...): want idle clients dropped")
+	}
+}
+
+func TestArgsClientConfig(t *testing.T) {
+	config := func(a *Args) string {
+		c, err := a.clientConfig()
+		if err != nil {
+			t.Fatalf("clientConfig(): %v", err)
+		}
+		return c
+	}
+
+	a := config(&Args{Namespace: "default", Timeout: time.Minute})
+	if b := config(&Args{Namespace: "default", Timeout: time.Minute, Images: []ktype.Image{{Name: "nginx"}}, KeepKinds: []string{"Secret"}}); a != b {
+		t.Errorf("clientConfig(): want the same config if only arguments applied on use differ")
+	}
+	if b := config(&Args{Namespace: "default", Timeout: 2 * time.Minute}); a == b {
+		t.Errorf("clientConfig(): want a new config if the timeout differs")
+	}
+	if b := config(&Args{Namespace: "default", Timeout: time.Minute, Redaction: &Redaction{Secrets: []string{"s3cr3t"}}}); a == b {
+		t.Errorf("clientConfig(): want a new config if the redaction differs")
+	}
+}
+
+func TestConnectionTarget(t *testing.T) {
+	c := NewConnection(nil, &rest.Config{})
+	a, err := c.target(nil, "default")
+	if err != nil {
+		t.Fatalf("target(...): %v", err)
+	}
+	if b, _ := c.target(nil, "default"); a != b {
+		t.Errorf("target(...): want the same target for a namespace")
+	}
+	if b, _ := c.target(nil, "other"); a == b {
+		t.Errorf("target(...): want different targets for different namespaces")
+	}
+}
+
+func TestProviderConfigVersion(t *testing.T) {
+	errBoom := errors.New("boom")
+	pc := &namespacedv1beta1.ClusterProviderConfig{}
+	pc.SetUID("pc-uid")
+	withSecret := kconfig.ProviderConfigSpec{
+		Credentials: kconfig.ProviderCredentials{
+			Source: xpv2.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv2.CommonCredentialSelectors{
+				SecretRef: &xpv2.SecretKeySelector{
+					SecretReference: xpv2.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+					Key:             "kubeconfig",
+				},
+			},
+		},
+	}
+	secret := func(rv string) kclient.Client {
+		return &test.MockClient{MockGet: func(_ context.Context, _ kclient.ObjectKey, obj kclient.Object) error {
+			obj.(*corev1.Secret).SetResourceVersion(rv)
+			return nil
+		}}
+	}
+	version := func(kube kclient.Client, spec kconfig.ProviderConfigSpec) string {
+		v, err := ProviderConfigVersion(context.Background(), kube, pc, spec)
+		if err != nil {
+			t.Fatalf("ProviderConfigVersion(...): %v", err)
+		}
+		return v
+	}
+
+	v1 := version(secret("1"), withSecret)
+	if diff := cmp.Diff(v1, version(secret("1"), withSecret)); diff != "" {
+		t.Errorf("ProviderConfigVersion(...): want a stable version: %s", diff)
+	}
+	if v1 == version(secret("2"), withSecret) {
+		t.Errorf("ProviderConfigVersion(...): want a new version when the secret changes")
+	}
+	if v1 == version(nil, kconfig.ProviderConfigSpec{Credentials: kconfig.ProviderCredentials{Source: xpv2.CredentialsSourceInjectedIdentity}}) {
+		t.Errorf("ProviderConfigVersion(...): want a new version when the spec changes")
+	}
+
+	_, err := ProviderConfigVersion(context.Background(), &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, pc, withSecret)
+	if diff := cmp.Diff(errors.Wrap(errBoom, errGetCredentialsSecret), err, test.EquateErrors()); diff != "" {
+		t.Errorf("ProviderConfigVersion(...): -want error, +got error: %s", diff)
+	}
+}
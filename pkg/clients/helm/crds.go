@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+)
+
+// CRD policies.
+const (
+	CRDPolicySkip                  = "Skip"
+	CRDPolicyCreate                = "Create"
+	CRDPolicyCreateReplace         = "CreateReplace"
+	CRDPolicyCreateReplaceNoDelete = "CreateReplaceNoDelete"
+)
+
+const (
+	crdFieldManager = "provider-helm"
+	crdKind         = "CustomResourceDefinition"
+)
+
+const (
+	errFailedToCreateCRDClient     = "failed to create CRD client"
+	errFailedToGetCRDTmpl          = "failed to get CRD %q"
+	errFailedToApplyCRDTmpl        = "failed to apply CRD %q"
+	errStoredVersionsRemovedTmpl   = "CRD %q of the chart removes stored versions [%s], migrate them before upgrading or use the CreateReplaceNoDelete CRD policy"
+	errUnexpectedCRDObjectKindTmpl = "unexpected object of kind %q in CRD file %q"
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// replacesCRDs returns true if the supplied CRD policy updates CRDs that
+// already exist.
+func replacesCRDs(policy string) bool {
+	return policy == CRDPolicyCreateReplace || policy == CRDPolicyCreateReplaceNoDelete
+}
+
+// crdApplier applies the CRDs in the crds/ directory of a chart with
+// server-side apply, which Helm only creates on install.
+type crdApplier struct {
+	crds dynamic.ResourceInterface
+	// keepVersions keeps versions of existing CRDs the chart no longer
+	// ships instead of refusing to remove stored versions.
+	keepVersions bool
+}
+
+func newCRDApplier(d dynamic.Interface, policy string) *crdApplier {
+	return &crdApplier{
+		crds:         d.Resource(crdResource),
+		keepVersions: policy == CRDPolicyCreateReplaceNoDelete,
+	}
+}
+
+// Apply applies the CRDs of the supplied chart and its dependencies.
+func (a *crdApplier) Apply(ctx context.Context, chrt *chart.Chart) error {
+	for _, c := range chrt.CRDObjects() {
+		for _, o := range parseManifests(string(c.File.Data)) {
+			if o.GetKind() != crdKind {
+				return errors.Errorf(errUnexpectedCRDObjectKindTmpl, o.GetKind(), c.Filename)
+			}
+			if err := a.apply(ctx, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *crdApplier) apply(ctx context.Context, crd *unstructured.Unstructured) error {
+	live, err := a.crds.Get(ctx, crd.GetName(), metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, errFailedToGetCRDTmpl, crd.GetName())
+	}
+	if err == nil {
+		if err := a.reconcileVersions(crd, live); err != nil {
+			return err
+		}
+	}
+
+	_, err = a.crds.Apply(ctx, crd.GetName(), crd, metav1.ApplyOptions{FieldManager: crdFieldManager, Force: true})
+	return errors.Wrapf(err, errFailedToApplyCRDTmpl, crd.GetName())
+}
+
+// reconcileVersions makes sure the supplied CRD does not remove versions
+// objects are still stored in. The versions of the existing CRD missing from
+// the supplied CRD are either kept, or refused.
+func (a *crdApplier) reconcileVersions(crd, live *unstructured.Unstructured) error {
+	desired, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	names := sets.New[string]()
+	for _, v := range desired {
+		if m, ok := v.(map[string]interface{}); ok {
+			names.Insert(versionName(m))
+		}
+	}
+
+	if a.keepVersions {
+		existing, _, _ := unstructured.NestedSlice(live.Object, "spec", "versions")
+		for _, v := range existing {
+			m, ok := v.(map[string]interface{})
+			if !ok || names.Has(versionName(m)) {
+				continue
+			}
+			// Only one version may be the storage version, which the chart
+			// decides.
+			m["storage"] = false
+			desired = append(desired, m)
+		}
+		return unstructured.SetNestedSlice(crd.Object, desired, "spec", "versions")
+	}
+
+	stored, _, _ := unstructured.NestedStringSlice(live.Object, "status", "storedVersions")
+	if removed := sets.New(stored...).Difference(names); removed.Len() > 0 {
+		return errors.Errorf(errStoredVersionsRemovedTmpl, crd.GetName(), strings.Join(sets.List(removed), ", "))
+	}
+	return nil
+}
+
+func versionName(v map[string]interface{}) string {
+	n, _ := v["name"].(string)
+	return n
+}
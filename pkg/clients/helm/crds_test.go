@@ -0,0 +1,135 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/chart/common"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	ktesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+const testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`
+
+func liveCRD(versions []interface{}, stored ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "widgets.example.org"},
+		"spec":       map[string]interface{}{"versions": versions},
+	}}
+	s := make([]interface{}, 0, len(stored))
+	for _, v := range stored {
+		s = append(s, v)
+	}
+	_ = unstructured.SetNestedSlice(u.Object, s, "status", "storedVersions")
+	return u
+}
+
+func TestCRDApplierApply(t *testing.T) {
+	v1alpha1 := map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true}
+
+	type want struct {
+		versions []string
+		err      error
+	}
+	cases := map[string]struct {
+		policy string
+		live   []runtime.Object
+		want   want
+	}{
+		"Create": {
+			policy: CRDPolicyCreateReplace,
+			want: want{
+				versions: []string{"v1"},
+			},
+		},
+		"StoredVersionRemoved": {
+			policy: CRDPolicyCreateReplace,
+			live:   []runtime.Object{liveCRD([]interface{}{v1alpha1}, "v1alpha1")},
+			want: want{
+				err: errors.Errorf(errStoredVersionsRemovedTmpl, "widgets.example.org", "v1alpha1"),
+			},
+		},
+		"UnstoredVersionRemoved": {
+			policy: CRDPolicyCreateReplace,
+			live:   []runtime.Object{liveCRD([]interface{}{v1alpha1}, "v1")},
+			want: want{
+				versions: []string{"v1"},
+			},
+		},
+		"KeepRemovedVersion": {
+			policy: CRDPolicyCreateReplaceNoDelete,
+			live:   []runtime.Object{liveCRD([]interface{}{v1alpha1}, "v1alpha1")},
+			want: want{
+				versions: []string{"v1", "v1alpha1"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}, tc.live...)
+
+			var applied []string
+			d.PrependReactor("patch", "customresourcedefinitions", func(action ktesting.Action) (bool, runtime.Object, error) {
+				u := &unstructured.Unstructured{}
+				if err := yaml.Unmarshal(action.(ktesting.PatchAction).GetPatch(), &u.Object); err != nil {
+					return true, nil, err
+				}
+				versions, _, _ := unstructured.NestedSlice(u.Object, "spec", "versions")
+				for _, v := range versions {
+					applied = append(applied, versionName(v.(map[string]interface{})))
+				}
+				if storage := storageVersions(versions); storage != 1 {
+					t.Errorf("Apply(...): want 1 storage version, got %d", storage)
+				}
+				return true, u, nil
+			})
+
+			chrt := &chart.Chart{
+				Metadata: &chart.Metadata{Name: "widgets"},
+				Files:    []*common.File{{Name: "crds/widgets.yaml", Data: []byte(testCRD)}},
+			}
+			err := newCRDApplier(d, tc.policy).Apply(context.Background(), chrt)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Apply(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.versions, applied); diff != "" {
+				t.Errorf("Apply(...): -want applied versions, +got applied versions: %s", diff)
+			}
+		})
+	}
+}
+
+func storageVersions(versions []interface{}) int {
+	n := 0
+	for _, v := range versions {
+		if s, _ := v.(map[string]interface{})["storage"].(bool); s {
+			n++
+		}
+	}
+	return n
+}
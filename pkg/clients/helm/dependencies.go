@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+const (
+	errFailedToResolveDependencyTmpl = "failed to resolve dependency %q of chart %q"
+	errFailedToGetDependencyCreds    = "failed to get credentials of dependency repository"
+	errUnsupportedDependencyRepoTmpl = "dependency %q is not vendored in charts/ and its repository %q cannot be pulled from"
+	errFailedToLoadDependency        = "failed to load dependency"
+)
+
+// DependencyCredentials resolves the credentials of the repository of a chart
+// dependency.
+type DependencyCredentials func(repository string) (*RepoCreds, error)
+
+// buildDependencies pulls the dependencies a chart declares but does not
+// vendor in its charts/ directory, like helm dependency build. Versions are
+// taken from Chart.lock if the chart has one. Dependencies in the repository
+// of the chart are pulled with its credentials, those in other repositories
+// with the credentials resolved for them.
+func (hc *client) buildDependencies(chrt *chart.Chart, chartRepo string, creds *RepoCreds) error {
+	if chrt.Metadata == nil {
+		return nil
+	}
+	vendored := make(map[string]bool, len(chrt.Dependencies()))
+	for _, d := range chrt.Dependencies() {
+		vendored[d.Name()] = true
+	}
+	pulled := make(map[string]bool)
+	for _, dep := range chrt.Metadata.Dependencies {
+		if dep == nil || vendored[dep.Name] || pulled[dep.Name+"@"+dep.Repository] {
+			continue
+		}
+		sub, err := hc.pullDependency(dep, lockedVersion(chrt.Lock, dep), chartRepo, creds)
+		if err != nil {
+			return errors.Wrapf(err, errFailedToResolveDependencyTmpl, dep.Name, chrt.Name())
+		}
+		chrt.AddDependency(sub)
+		pulled[dep.Name+"@"+dep.Repository] = true
+	}
+	return nil
+}
+
+// pullDependency pulls and loads the supplied dependency of a chart from the
+// supplied repository, along with its own unvendored dependencies.
+// Dependencies of an exact version are looked up in the chart cache first.
+func (hc *client) pullDependency(dep *chart.Dependency, version, chartRepo string, creds *RepoCreds) (*chart.Chart, error) {
+	repo := strings.TrimSuffix(dep.Repository, "/")
+	if !registry.IsOCI(repo) && !strings.HasPrefix(repo, "https://") && !strings.HasPrefix(repo, "http://") {
+		// file:// and @alias repositories only resolve against the local
+		// filesystem and repositories.yaml of a Helm CLI.
+		return nil, withFailure(FailureInvalidChart, errors.Errorf(errUnsupportedDependencyRepoTmpl, dep.Name, dep.Repository))
+	}
+
+	depCreds := creds
+	if !sameHost(repo, chartRepo) {
+		depCreds = &RepoCreds{}
+		if hc.dependencyCredentials != nil {
+			c, err := hc.dependencyCredentials(repo)
+			if err != nil {
+				return nil, errors.Wrap(err, errFailedToGetDependencyCreds)
+			}
+			depCreds = c
+		}
+	}
+
+	var chartFilePath string
+	var err error
+	if _, verr := semver.StrictNewVersion(version); verr == nil {
+		chartFilePath, err = hc.ensureChartCached(resolveChartFilePath(dep.Name, version), "", dep.Name, version, repo, "", depCreds)
+	} else {
+		// Version ranges are resolved against the repository on every pull,
+		// like charts pulled in their latest version.
+		chartFilePath, err = hc.pullChartToCache("", dep.Name, version, repo, "", depCreds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := loader.Load(safePath(chartCache, chartFilePath))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToLoadDependency)
+	}
+	if err := hc.buildDependencies(sub, repo, depCreds); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// lockedVersion returns the version of the supplied dependency in the
+// supplied Chart.lock, or the version range of the dependency if it is not
+// locked.
+func lockedVersion(lock *chart.Lock, dep *chart.Dependency) string {
+	if lock == nil {
+		return dep.Version
+	}
+	for _, l := range lock.Dependencies {
+		if l != nil && l.Name == dep.Name && strings.TrimSuffix(l.Repository, "/") == strings.TrimSuffix(dep.Repository, "/") {
+			return l.Version
+		}
+	}
+	return dep.Version
+}
+
+// sameHost returns true if the supplied repositories are served by the same
+// host, and thus take the same credentials.
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host)
+}
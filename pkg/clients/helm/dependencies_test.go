@@ -0,0 +1,200 @@
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/action"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+)
+
+func testChart(name, version string, deps ...*chart.Dependency) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion:   chart.APIVersionV2,
+			Name:         name,
+			Version:      version,
+			Dependencies: deps,
+		},
+	}
+}
+
+// testRepository serves an HTTP chart repository with the supplied charts,
+// counting the requests made to it.
+func testRepository(t *testing.T, charts ...*chart.Chart) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, c := range charts {
+		if _, err := chartutil.Save(c, dir); err != nil {
+			t.Fatalf("chartutil.Save(...): %v", err)
+		}
+	}
+	requests := &atomic.Int32{}
+	files := http.FileServer(http.Dir(dir))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	idx, err := repo.IndexDirectory(dir, srv.URL)
+	if err != nil {
+		t.Fatalf("repo.IndexDirectory(...): %v", err)
+	}
+	if err := idx.WriteFile(filepath.Join(dir, "index.yaml"), 0600); err != nil {
+		t.Fatalf("idx.WriteFile(...): %v", err)
+	}
+	return srv, requests
+}
+
+func TestBuildDependencies(t *testing.T) {
+	srv, requests := testRepository(t, testChart("sub", "1.0.0"), testChart("sub", "1.1.0"))
+	other, _ := testRepository(t, testChart("other", "2.0.0"))
+
+	type want struct {
+		deps     map[string]string
+		requests int32
+		creds    []string
+		failure  Failure
+		err      bool
+	}
+	cases := map[string]struct {
+		reason string
+		chart  func() *chart.Chart
+		cached []*chart.Chart
+		want   want
+	}{
+		"Vendored": {
+			reason: "Dependencies vendored in charts/ should not be pulled.",
+			chart: func() *chart.Chart {
+				c := testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "1.0.0", Repository: srv.URL})
+				c.AddDependency(testChart("sub", "0.1.0"))
+				return c
+			},
+			want: want{deps: map[string]string{"sub": "0.1.0"}},
+		},
+		"ExactVersion": {
+			reason: "A dependency of an exact version should be pulled from its repository.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "1.0.0", Repository: srv.URL})
+			},
+			want: want{deps: map[string]string{"sub": "1.0.0"}, requests: 2},
+		},
+		"Cached": {
+			reason: "A dependency of an exact version should be loaded from the chart cache if it is cached.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "1.0.0", Repository: srv.URL})
+			},
+			cached: []*chart.Chart{testChart("sub", "1.0.0")},
+			want:   want{deps: map[string]string{"sub": "1.0.0"}},
+		},
+		"VersionRange": {
+			reason: "A version range should be resolved against the repository of the dependency.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "^1.0.0", Repository: srv.URL})
+			},
+			cached: []*chart.Chart{testChart("sub", "1.0.0")},
+			want:   want{deps: map[string]string{"sub": "1.1.0"}, requests: 2},
+		},
+		"Locked": {
+			reason: "The version of a dependency in Chart.lock should be used over its version range.",
+			chart: func() *chart.Chart {
+				c := testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "^1.0.0", Repository: srv.URL})
+				c.Lock = &chart.Lock{Dependencies: []*chart.Dependency{{Name: "sub", Version: "1.0.0", Repository: srv.URL + "/"}}}
+				return c
+			},
+			cached: []*chart.Chart{testChart("sub", "1.0.0")},
+			want:   want{deps: map[string]string{"sub": "1.0.0"}},
+		},
+		"OtherRepository": {
+			reason: "Credentials should be resolved for dependencies in another repository than the chart.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0",
+					&chart.Dependency{Name: "sub", Version: "1.0.0", Repository: srv.URL},
+					&chart.Dependency{Name: "other", Version: "2.0.0", Repository: other.URL})
+			},
+			cached: []*chart.Chart{testChart("sub", "1.0.0"), testChart("other", "2.0.0")},
+			want:   want{deps: map[string]string{"sub": "1.0.0", "other": "2.0.0"}, creds: []string{other.URL}},
+		},
+		"FileRepository": {
+			reason: "A dependency in a file:// repository that is not vendored cannot be resolved.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0", &chart.Dependency{Name: "sub", Version: "1.0.0", Repository: "file://../sub"})
+			},
+			want: want{failure: FailureInvalidChart, err: true},
+		},
+		"NotFound": {
+			reason: "A dependency missing from its repository should fail to resolve.",
+			chart: func() *chart.Chart {
+				return testChart("parent", "1.0.0", &chart.Dependency{Name: "missing", Version: "1.0.0", Repository: srv.URL})
+			},
+			want: want{failure: FailureChartNotFound, err: true, requests: 1},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmp := t.TempDir()
+			origCache := chartCache
+			chartCache = filepath.Join(tmp, "charts")
+			defer func() { chartCache = origCache }()
+			if err := os.Mkdir(chartCache, 0750); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range tc.cached {
+				if _, err := chartutil.Save(c, chartCache); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pc := action.NewPull(action.WithConfig(&action.Configuration{}))
+			pc.Settings = &cli.EnvSettings{
+				ContentCache:     filepath.Join(tmp, "content"),
+				RepositoryCache:  filepath.Join(tmp, "repository"),
+				RepositoryConfig: filepath.Join(tmp, "repositories.yaml"),
+			}
+			var creds []string
+			hc := &client{
+				log:        &mockLogger{},
+				pullClient: pc,
+				dependencyCredentials: func(repository string) (*RepoCreds, error) {
+					creds = append(creds, repository)
+					return &RepoCreds{}, nil
+				},
+			}
+
+			requests.Store(0)
+			c := tc.chart()
+			err := hc.buildDependencies(c, srv.URL, &RepoCreds{})
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nbuildDependencies(...): unexpected error: %v", tc.reason, err)
+			}
+			if got := ClassifyFailure(err); got != tc.want.failure {
+				t.Errorf("\n%s\nClassifyFailure(...): want %q, got %q: %v", tc.reason, tc.want.failure, got, err)
+			}
+			got := map[string]string{}
+			for _, d := range c.Dependencies() {
+				got[d.Name()] = d.Metadata.Version
+			}
+			if tc.want.deps == nil {
+				tc.want.deps = map[string]string{}
+			}
+			if diff := cmp.Diff(tc.want.deps, got); diff != "" {
+				t.Errorf("\n%s\nbuildDependencies(...): -want dependencies, +got dependencies:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, requests.Load()); diff != "" {
+				t.Errorf("\n%s\nbuildDependencies(...): -want requests, +got requests:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, creds); diff != "" {
+				t.Errorf("\n%s\nbuildDependencies(...): -want credentials, +got credentials:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/chart/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// discoveryTTL is how long the discovered APIs of a target cluster are
+// cached.
+const discoveryTTL = 10 * time.Minute
+
+const (
+	errGetServerVersion = "cannot get server version of the target cluster"
+	errGetAPIVersions   = "cannot get API versions of the target cluster"
+)
+
+// A discoveryCache caches the APIs, OpenAPI schemas and Helm capabilities of
+// a target cluster for a TTL.
+type discoveryCache struct {
+	config *rest.Config
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu           sync.Mutex
+	client       discovery.CachedDiscoveryInterface
+	mapper       meta.RESTMapper
+	capabilities *common.Capabilities
+	expires      time.Time
+}
+
+func newDiscoveryCache(config *rest.Config) *discoveryCache {
+	return &discoveryCache{config: config, ttl: discoveryTTL, now: time.Now}
+}
+
+// init builds the discovery client and REST mapper if necessary, and
+// invalidates them once they expired. It must be called with the lock held.
+func (d *discoveryCache) init() {
+	if d.client == nil {
+		config := rest.CopyConfig(d.config)
+		// The more groups you have, the more discovery requests you need to make.
+		// given 25 groups (our groups + a few custom conf) with one-ish version each, discovery needs to make 50 requests
+		// double it just so we don't end up here again for a while.  This config is only used for discovery.
+		// Align value with https://github.com/kubernetes/kubernetes/pull/109141
+		config.Burst = 300
+
+		dc, _ := discovery.NewDiscoveryClientForConfig(config)
+		d.client = memory.NewMemCacheClient(dc)
+		d.mapper = restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(d.client), d.client, nil)
+		d.expires = d.now().Add(d.ttl)
+		return
+	}
+	if d.now().After(d.expires) {
+		d.invalidate()
+	}
+}
+
+func (d *discoveryCache) invalidate() {
+	if d.client == nil {
+		return
+	}
+	d.client.Invalidate()
+	if r, ok := d.mapper.(meta.ResettableRESTMapper); ok {
+		r.Reset()
+	}
+	d.capabilities = nil
+	d.expires = d.now().Add(d.ttl)
+}
+
+// Invalidate the cache, so that the APIs of the target cluster are
+// discovered again.
+func (d *discoveryCache) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.invalidate()
+}
+
+// Client returns the cached discovery client.
+func (d *discoveryCache) Client() discovery.CachedDiscoveryInterface {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.init()
+	return d.client
+}
+
+// RESTMapper returns the cached REST mapper.
+func (d *discoveryCache) RESTMapper() meta.RESTMapper {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.init()
+	return d.mapper
+}
+
+// Capabilities returns the Helm capabilities of the target cluster. Unlike
+// Helm, it does not discover the target cluster again to build them.
+func (d *discoveryCache) Capabilities() (*common.Capabilities, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.init()
+	if d.capabilities != nil {
+		return d.capabilities, nil
+	}
+	v, err := d.client.ServerVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetServerVersion)
+	}
+	apiVersions, err := action.GetVersionSet(d.client)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetAPIVersions)
+	}
+	d.capabilities = &common.Capabilities{
+		APIVersions: apiVersions,
+		KubeVersion: common.KubeVersion{
+			Version: v.GitVersion,
+			Major:   v.Major,
+			Minor:   v.Minor,
+		},
+		HelmVersion: common.DefaultCapabilities.HelmVersion,
+	}
+	return d.capabilities, nil
+}
+
+// isNoMatch returns true if the supplied error indicates that a kind is not
+// served by the target cluster, as far as its cached APIs tell.
+func isNoMatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	return meta.IsNoMatchError(err) || strings.Contains(err.Error(), "no matches for kind")
+}
@@ -0,0 +1,96 @@
+package helm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestDiscoveryCacheCapabilities(t *testing.T) {
+	fake := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true}},
+	}}}}
+	now := time.Now()
+	client := memory.NewMemCacheClient(fake)
+	d := &discoveryCache{
+		ttl:     time.Minute,
+		now:     func() time.Time { return now },
+		client:  client,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(client),
+		expires: now.Add(time.Minute),
+	}
+	discoveries := func() int {
+		n := 0
+		for _, a := range fake.Actions() {
+			if a.GetResource().Resource == "version" {
+				n++
+			}
+		}
+		return n
+	}
+
+	if _, err := d.Capabilities(); err != nil {
+		t.Fatalf("Capabilities(): %v", err)
+	}
+	if _, err := d.Capabilities(); err != nil {
+		t.Fatalf("Capabilities(): %v", err)
+	}
+	if diff := cmp.Diff(1, discoveries()); diff != "" {
+		t.Errorf("Capabilities(): want cached capabilities, -want discoveries, +got discoveries: %s", diff)
+	}
+
+	d.Invalidate()
+	if _, err := d.Capabilities(); err != nil {
+		t.Fatalf("Capabilities(): %v", err)
+	}
+	if diff := cmp.Diff(2, discoveries()); diff != "" {
+		t.Errorf("Capabilities(): want discovery after invalidation, -want discoveries, +got discoveries: %s", diff)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := d.Capabilities(); err != nil {
+		t.Fatalf("Capabilities(): %v", err)
+	}
+	if diff := cmp.Diff(3, discoveries()); diff != "" {
+		t.Errorf("Capabilities(): want discovery after the TTL, -want discoveries, +got discoveries: %s", diff)
+	}
+}
+
+func TestIsNoMatch(t *testing.T) {
+	noMatch := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.org", Kind: "Widget"}, SearchedVersions: []string{"v1"}}
+
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil": {},
+		"NoMatch": {
+			err:  noMatch,
+			want: true,
+		},
+		"WrappedNoMatch": {
+			err:  errors.Wrap(errors.New(noMatch.Error()), "unable to build kubernetes objects from release manifest"),
+			want: true,
+		},
+		"OtherError": {
+			err: errors.New("boom"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, isNoMatch(tc.err)); diff != "" {
+				t.Errorf("isNoMatch(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/release"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+const (
+	// encryptionKeyLength is the length of an AES-256 key.
+	encryptionKeyLength = 32
+	// encryptedPrefix marks fields encrypted by the encryptingDriver.
+	encryptedPrefix = "aes-256-gcm:"
+	// encryptedConfigKey holds the encrypted values of a release.
+	encryptedConfigKey = "$encrypted"
+)
+
+const (
+	errInvalidEncryptionKeyLengthTmpl = "encryption key must be %d bytes long, got %d"
+	errFailedToCreateCipher           = "failed to create cipher"
+	errFailedToEncryptRelease         = "failed to encrypt release"
+	errFailedToDecryptRelease         = "failed to decrypt release"
+	errMalformedCiphertext            = "malformed ciphertext"
+)
+
+// encryptingDriver is a Helm storage driver that encrypts the values,
+// manifest, hooks and notes of releases before they are stored. Releases that
+// were stored unencrypted are returned as is.
+type encryptingDriver struct {
+	driver.Driver
+	aead cipher.AEAD
+}
+
+func newEncryptingDriver(d driver.Driver, key []byte) (*encryptingDriver, error) {
+	if len(key) != encryptionKeyLength {
+		return nil, errors.Errorf(errInvalidEncryptionKeyLengthTmpl, encryptionKeyLength, len(key))
+	}
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateCipher)
+	}
+	aead, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateCipher)
+	}
+	return &encryptingDriver{Driver: d, aead: aead}, nil
+}
+
+func (d *encryptingDriver) Create(key string, rls release.Releaser) error {
+	e, err := d.encrypt(rls)
+	if err != nil {
+		return errors.Wrap(err, errFailedToEncryptRelease)
+	}
+	return d.Driver.Create(key, e)
+}
+
+func (d *encryptingDriver) Update(key string, rls release.Releaser) error {
+	e, err := d.encrypt(rls)
+	if err != nil {
+		return errors.Wrap(err, errFailedToEncryptRelease)
+	}
+	return d.Driver.Update(key, e)
+}
+
+func (d *encryptingDriver) Delete(key string) (release.Releaser, error) {
+	rls, err := d.Driver.Delete(key)
+	if err != nil {
+		return rls, err
+	}
+	return d.decrypt(rls)
+}
+
+func (d *encryptingDriver) Get(key string) (release.Releaser, error) {
+	rls, err := d.Driver.Get(key)
+	if err != nil {
+		return rls, err
+	}
+	return d.decrypt(rls)
+}
+
+func (d *encryptingDriver) List(filter func(release.Releaser) bool) ([]release.Releaser, error) {
+	var derr error
+	ls, err := d.Driver.List(func(rls release.Releaser) bool {
+		r, err := d.decrypt(rls)
+		if err != nil {
+			derr = err
+			return false
+		}
+		return filter(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if derr != nil {
+		return nil, derr
+	}
+	return d.decryptAll(ls)
+}
+
+func (d *encryptingDriver) Query(labels map[string]string) ([]release.Releaser, error) {
+	ls, err := d.Driver.Query(labels)
+	if err != nil {
+		return nil, err
+	}
+	return d.decryptAll(ls)
+}
+
+func (d *encryptingDriver) decryptAll(ls []release.Releaser) ([]release.Releaser, error) {
+	out := make([]release.Releaser, 0, len(ls))
+	for _, rls := range ls {
+		r, err := d.decrypt(rls)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// encrypt returns an encrypted copy of the supplied release, leaving the
+// release Helm keeps working with untouched.
+func (d *encryptingDriver) encrypt(rls release.Releaser) (release.Releaser, error) {
+	r, ok := rls.(*rspb.Release)
+	if !ok || r == nil {
+		return rls, nil
+	}
+	c := *r
+
+	cfg, err := json.Marshal(r.Config)
+	if err != nil {
+		return nil, err
+	}
+	c.Config = map[string]any{encryptedConfigKey: d.seal(string(cfg))}
+	c.Manifest = d.seal(r.Manifest)
+
+	if r.Info != nil {
+		i := *r.Info
+		i.Notes = d.seal(r.Info.Notes)
+		c.Info = &i
+	}
+
+	c.Hooks = nil
+	for _, h := range r.Hooks {
+		if h == nil {
+			c.Hooks = append(c.Hooks, h)
+			continue
+		}
+		hc := *h
+		hc.Manifest = d.seal(h.Manifest)
+		c.Hooks = append(c.Hooks, &hc)
+	}
+
+	return &c, nil
+}
+
+// decrypt returns a decrypted copy of the supplied release, leaving the
+// release kept by the underlying driver untouched.
+func (d *encryptingDriver) decrypt(rls release.Releaser) (release.Releaser, error) { //nolint:gocyclo // easier to follow as a unit
+	r, ok := rls.(*rspb.Release)
+	if !ok || r == nil {
+		return rls, nil
+	}
+	c := *r
+
+	var err error
+	if c.Manifest, err = d.open(r.Manifest); err != nil {
+		return nil, errors.Wrap(err, errFailedToDecryptRelease)
+	}
+
+	if r.Info != nil {
+		i := *r.Info
+		if i.Notes, err = d.open(r.Info.Notes); err != nil {
+			return nil, errors.Wrap(err, errFailedToDecryptRelease)
+		}
+		c.Info = &i
+	}
+
+	c.Hooks = nil
+	for _, h := range r.Hooks {
+		if h == nil {
+			c.Hooks = append(c.Hooks, h)
+			continue
+		}
+		hc := *h
+		if hc.Manifest, err = d.open(h.Manifest); err != nil {
+			return nil, errors.Wrap(err, errFailedToDecryptRelease)
+		}
+		c.Hooks = append(c.Hooks, &hc)
+	}
+
+	sealed, ok := r.Config[encryptedConfigKey].(string)
+	if !ok || len(r.Config) != 1 {
+		return &c, nil
+	}
+	cfg, err := d.open(sealed)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToDecryptRelease)
+	}
+	c.Config = nil
+	if err := json.Unmarshal([]byte(cfg), &c.Config); err != nil {
+		return nil, errors.Wrap(err, errFailedToDecryptRelease)
+	}
+	return &c, nil
+}
+
+func (d *encryptingDriver) seal(plain string) string {
+	if plain == "" {
+		return ""
+	}
+	nonce := make([]byte, d.aead.NonceSize())
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(nonce)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(d.aead.Seal(nonce, nonce, []byte(plain), nil))
+}
+
+func (d *encryptingDriver) open(sealed string) (string, error) {
+	if !strings.HasPrefix(sealed, encryptedPrefix) {
+		return sealed, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, encryptedPrefix))
+	if err != nil {
+		return "", errors.Wrap(err, errMalformedCiphertext)
+	}
+	if len(b) < d.aead.NonceSize() {
+		return "", errors.New(errMalformedCiphertext)
+	}
+	plain, err := d.aead.Open(nil, b[:d.aead.NonceSize()], b[d.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,113 @@
+package helm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/release/common"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+func TestNewEncryptingDriver(t *testing.T) {
+	cases := map[string]struct {
+		key  []byte
+		want error
+	}{
+		"ValidKey": {
+			key: bytes.Repeat([]byte("k"), 32),
+		},
+		"ShortKey": {
+			key:  []byte("short"),
+			want: errors.Errorf(errInvalidEncryptionKeyLengthTmpl, 32, 5),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newEncryptingDriver(driver.NewMemory(), tc.key)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("newEncryptingDriver(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func TestEncryptingDriver(t *testing.T) {
+	mem := driver.NewMemory()
+	d, err := newEncryptingDriver(mem, bytes.Repeat([]byte("k"), 32))
+	if err != nil {
+		t.Fatalf("newEncryptingDriver(...): %v", err)
+	}
+
+	plain := &rspb.Release{
+		Name:      "plain",
+		Namespace: "default",
+		Version:   1,
+		Info:      &rspb.Info{Status: common.StatusDeployed, Notes: "notes"},
+		Config:    map[string]any{"password": "s3cr3t"},
+		Manifest:  "kind: Secret\nstringData:\n  password: s3cr3t\n",
+	}
+	// Releases stored before encryption was enabled remain readable.
+	if err := mem.Create("plain.v1", plain); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	rel := &rspb.Release{
+		Name:      "test",
+		Namespace: "default",
+		Version:   1,
+		Info:      &rspb.Info{Status: common.StatusDeployed, Notes: "password is s3cr3t"},
+		Config:    map[string]any{"password": "s3cr3t"},
+		Manifest:  "kind: Secret\nstringData:\n  password: s3cr3t\n",
+		Hooks:     []*rspb.Hook{{Name: "job", Manifest: "kind: Job\n# s3cr3t\n"}},
+	}
+	want := *rel
+	if err := d.Create("test.v1", rel); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if diff := cmp.Diff(&want, rel); diff != "" {
+		t.Errorf("Create(...): -want unchanged release, +got release: %s", diff)
+	}
+
+	stored, err := mem.Get("test.v1")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	s := stored.(*rspb.Release)
+	for _, field := range []string{s.Manifest, s.Info.Notes, s.Hooks[0].Manifest, s.Config[encryptedConfigKey].(string)} {
+		if !strings.HasPrefix(field, encryptedPrefix) || strings.Contains(field, "s3cr3t") {
+			t.Errorf("Create(...): stored field is not encrypted: %q", field)
+		}
+	}
+
+	got, err := d.Get("test.v1")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	if diff := cmp.Diff(&want, got); diff != "" {
+		t.Errorf("Get(...): -want decrypted release, +got release: %s", diff)
+	}
+
+	all, err := d.List(func(r release.Releaser) bool {
+		return strings.Contains(r.(*rspb.Release).Manifest, "s3cr3t")
+	})
+	if err != nil {
+		t.Fatalf("List(...): %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("List(...): want 2 releases, got %d", len(all))
+	}
+
+	other, err := newEncryptingDriver(mem, bytes.Repeat([]byte("o"), 32))
+	if err != nil {
+		t.Fatalf("newEncryptingDriver(...): %v", err)
+	}
+	if _, err := other.Get("test.v1"); err == nil {
+		t.Errorf("Get(...): want error decrypting with a different key, got none")
+	}
+}
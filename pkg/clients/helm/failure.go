@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// A Failure classifies why pulling a chart or a Helm operation failed.
+type Failure string
+
+// Failures of pulling a chart or of a Helm operation.
+const (
+	// FailureUnknown is a failure of no known class.
+	FailureUnknown Failure = ""
+	// FailureRender is a failure to render the manifests of a chart, e.g.
+	// because of a template error.
+	FailureRender Failure = "RenderFailed"
+	// FailureSchemaInvalid is a failure of values to match the schema of a
+	// chart.
+	FailureSchemaInvalid Failure = "SchemaInvalid"
+	// FailureTimeout is a failure to finish an operation, e.g. to wait for
+	// the resources of a release to become ready, in time.
+	FailureTimeout Failure = "Timeout"
+	// FailureConflict is a failure to apply resources owned by something
+	// else, or a release that another operation is in progress on.
+	FailureConflict Failure = "Conflict"
+	// FailureAuth is a failure to authenticate to, or a lack of permission
+	// on, a chart repository.
+	FailureAuth Failure = "AuthFailed"
+	// FailureForbidden is a failure to authenticate to, or a lack of
+	// permission on, the target cluster. Unlike FailureAuth it is transient,
+	// as the permissions of the provider may be granted without changing the
+	// Release.
+	FailureForbidden Failure = "Forbidden"
+	// FailureChartNotFound is a failure to find a chart or a version of it
+	// in its repository.
+	FailureChartNotFound Failure = "ChartNotFound"
+	// FailureInvalidChart is a failure to resolve the chart of a Release
+	// from its spec, e.g. because its digests conflict.
+	FailureInvalidChart Failure = "InvalidChart"
+)
+
+// Terminal returns true if failures of the class are permanent, i.e. if
+// retrying the failed operation as is would fail again. They are resolved by
+// changing the spec or the sources of a Release, or its chart repository.
+func (f Failure) Terminal() bool {
+	switch f { //nolint:exhaustive // the rest are transient
+	case FailureRender, FailureSchemaInvalid, FailureAuth, FailureChartNotFound, FailureInvalidChart:
+		return true
+	}
+	return false
+}
+
+// IsTerminal returns true if an error returned by pulling a chart or by a
+// Helm operation is a permanent failure.
+func IsTerminal(err error) bool {
+	return ClassifyFailure(err).Terminal()
+}
+
+// A failureError is an error of a known class of failure.
+type failureError struct {
+	error
+	failure Failure
+}
+
+func (e failureError) Unwrap() error {
+	return e.error
+}
+
+// withFailure marks an error as a failure of the supplied class.
+func withFailure(f Failure, err error) error {
+	return failureError{error: err, failure: f}
+}
+
+var (
+	renderErrors = []string{
+		"template: ",
+		"parse error",
+		"yaml parse error",
+		"error converting yaml to json",
+		"execution error at",
+		"error while running post render",
+		"error while parsing post rendered output",
+		"chart requires kubeversion",
+		"unable to build kubernetes objects from release manifest",
+	}
+	timeoutErrors = []string{
+		"context deadline exceeded",
+		"timed out waiting",
+	}
+	conflictErrors = []string{
+		"conflict",
+		"another operation (install/upgrade/rollback) is in progress",
+		"cannot re-use a name that is still in use",
+		"invalid ownership metadata",
+	}
+	authErrors = []string{
+		"unauthorized",
+		"forbidden",
+		"authentication required",
+		"denied",
+	}
+	notFoundErrors = []string{
+		"not found",
+		"manifest unknown",
+		"404",
+	}
+)
+
+// ClassifyFailure returns the class of an error returned by pulling a chart
+// or by a Helm operation. Helm reports most failures as plain errors, so they
+// are mostly classified by their message.
+func ClassifyFailure(err error) Failure { //nolint:gocyclo // a flat list of checks
+	if err == nil {
+		return FailureUnknown
+	}
+	if fe := (failureError{}); errors.As(err, &fe) {
+		return fe.failure
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || kerrors.IsTimeout(err) || containsAny(msg, timeoutErrors):
+		return FailureTimeout
+	case containsAny(msg, []string{errFailedToPullChart, errFailedToLogin}) && isAuthError(err):
+		return FailureAuth
+	case isAuthError(err):
+		// Failures to pull a chart are marked as such when they happen, so
+		// this is a failure of the target cluster.
+		return FailureForbidden
+	case errors.As(err, &repo.ChartNotFoundError{}):
+		return FailureChartNotFound
+	case strings.Contains(msg, errFailedToPullChart) && containsAny(msg, notFoundErrors):
+		return FailureChartNotFound
+	case kerrors.IsConflict(err) || kerrors.IsAlreadyExists(err) || containsAny(msg, conflictErrors):
+		return FailureConflict
+	case isNoMatch(err):
+		// A kind not known to the target cluster may yet be installed.
+		return FailureUnknown
+	case strings.Contains(msg, "values don't meet the specifications"):
+		return FailureSchemaInvalid
+	case containsAny(msg, renderErrors):
+		return FailureRender
+	}
+	return FailureUnknown
+}
+
+// pullFailure marks a failure to pull a chart that its repository rejected
+// the credentials of as a failure to authenticate to the repository.
+func pullFailure(err error) error {
+	if err == nil || !isAuthError(err) {
+		return err
+	}
+	if fe := (failureError{}); errors.As(err, &fe) {
+		return err
+	}
+	return withFailure(FailureAuth, err)
+}
+
+func isAuthError(err error) bool {
+	return kerrors.IsUnauthorized(err) || kerrors.IsForbidden(err) || containsAny(strings.ToLower(err.Error()), authErrors)
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Failure
+	}{
+		"Nil": {
+			want: FailureUnknown,
+		},
+		"Unknown": {
+			err:  errors.New("boom"),
+			want: FailureUnknown,
+		},
+		"TemplateError": {
+			err:  errors.New(`template: wordpress/templates/deployment.yaml:12:4: executing "wordpress/templates/deployment.yaml" at <.Values.image>: nil pointer evaluating interface {}.tag`),
+			want: FailureRender,
+		},
+		"SchemaError": {
+			err:  errors.New("values don't meet the specifications of the schema(s) in the following chart(s):\nwordpress:\n- replicaCount: Invalid type"),
+			want: FailureSchemaInvalid,
+		},
+		"DigestMismatch": {
+			err:  errors.Wrap(withFailure(FailureInvalidChart, errors.Errorf(errDigestMismatchTmpl, "sha256:a", "sha256:b")), "failed to install release"),
+			want: FailureInvalidChart,
+		},
+		"DeadlineExceeded": {
+			err:  errors.Wrap(context.DeadlineExceeded, "failed to install release"),
+			want: FailureTimeout,
+		},
+		"NotReady": {
+			err:  errors.New("resource Deployment/default/wordpress not ready. status: InProgress, message: \ncontext deadline exceeded"),
+			want: FailureTimeout,
+		},
+		"Conflict": {
+			err:  kerrors.NewConflict(schema.GroupResource{Resource: "deployments"}, "wordpress", errors.New("boom")),
+			want: FailureConflict,
+		},
+		"Pending": {
+			err:  errors.New("another operation (install/upgrade/rollback) is in progress"),
+			want: FailureConflict,
+		},
+		"Forbidden": {
+			err:  kerrors.NewForbidden(schema.GroupResource{Resource: "deployments"}, "wordpress", errors.New("boom")),
+			want: FailureForbidden,
+		},
+		"ClusterUnauthorized": {
+			err:  errors.New("failed to create resource: Unauthorized"),
+			want: FailureForbidden,
+		},
+		"RegistryUnauthorized": {
+			err:  pullFailure(errors.Wrap(errors.New("GET https://registry.example.org/v2/charts/wordpress/tags/list: unauthorized: authentication required"), errFailedToPullChart)),
+			want: FailureAuth,
+		},
+		"RegistryLoginDenied": {
+			err:  errors.Wrap(pullFailure(errors.Wrap(errors.New("login attempt to https://registry.example.org/v2/ failed with status: 401 Unauthorized"), errFailedToLogin)), "failed to load chart"),
+			want: FailureAuth,
+		},
+		"ChartNotFound": {
+			err:  errors.Wrap(repo.ChartNotFoundError{Chart: "wordpress", RepoURL: "https://charts.example.org"}, errFailedToPullChart),
+			want: FailureChartNotFound,
+		},
+		"OCITagNotFound": {
+			err:  errors.Wrap(errors.New("registry.example.org/charts/wordpress:99.0.0: not found"), errFailedToPullChart),
+			want: FailureChartNotFound,
+		},
+		"NoMatch": {
+			err:  errors.Wrap(&meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.org", Kind: "Widget"}}, "unable to build kubernetes objects from release manifest"),
+			want: FailureUnknown,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ClassifyFailure(tc.err)); diff != "" {
+				t.Errorf("ClassifyFailure(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Unknown": {
+			err: errors.New("boom"),
+		},
+		"Timeout": {
+			err: context.DeadlineExceeded,
+		},
+		"Conflict": {
+			err: errors.New("another operation (install/upgrade/rollback) is in progress"),
+		},
+		"ChartNotFound": {
+			err:  repo.ChartNotFoundError{Chart: "wordpress", RepoURL: "https://charts.example.org"},
+			want: true,
+		},
+		"RenderFailed": {
+			err:  errors.New("template: wordpress/templates/deployment.yaml:12:4: unexpected EOF"),
+			want: true,
+		},
+		"ClusterForbidden": {
+			err: kerrors.NewForbidden(schema.GroupResource{Resource: "deployments"}, "wordpress", errors.New("boom")),
+		},
+		"RegistryUnauthorized": {
+			err:  pullFailure(errors.Wrap(errors.New("unauthorized: authentication required"), errFailedToPullChart)),
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, IsTerminal(tc.err)); diff != "" {
+				t.Errorf("IsTerminal(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
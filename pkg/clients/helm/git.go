@@ -0,0 +1,358 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"helm.sh/helm/v4/pkg/chart/loader/archive"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/ignore"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/metrics"
+)
+
+// Annotations of charts loaded from Git. They are recorded in the releases
+// deployed from the charts.
+const (
+	AnnotationGitCommit = "helm.crossplane.io/git-commit"
+	AnnotationGitRef    = "helm.crossplane.io/git-ref"
+)
+
+const (
+	errGitChartConflict        = "spec.forProvider.chart.git cannot be combined with repository, url or digest"
+	errFailedToListGitRefs     = "failed to list references of git repository"
+	errGitRefNotFoundTmpl      = "reference %q not found in git repository"
+	errFailedToCloneGitRepo    = "failed to clone git repository"
+	errFailedToReadGitCommit   = "failed to read git commit"
+	errGitChartNotFoundTmpl    = "chart not found at path %q of git commit %s"
+	errFailedToReadGitChart    = "failed to read chart from git"
+	errFailedToParseHelmIgnore = "failed to parse .helmignore"
+	errFailedToPackageGitChart = "failed to package chart from git"
+	errFailedToCacheGitChart   = "failed to cache chart from git"
+	gitHead                    = "HEAD"
+	gitRefsHeads               = "refs/heads/"
+	gitRefsTags                = "refs/tags/"
+	gitPeeledSuffix            = "^{}"
+)
+
+// A GitSource is a chart in a Git repository.
+type GitSource struct {
+	// URL of the repository.
+	URL string
+	// Ref is the branch or tag the chart is checked out at, HEAD if empty.
+	Ref string
+	// Commit the chart is checked out at. Takes precedence over Ref.
+	Commit string
+	// Path of the chart in the repository.
+	Path string
+}
+
+// loadGitChart loads a chart from Git. The chart is packaged in memory from
+// the tree of the resolved commit, and cached by commit.
+func (hc *client) loadGitChart(src GitSource, creds *RepoCreds) (*chart.Chart, error) {
+	auth := gitAuth(creds)
+	commit, ref := src.Commit, plumbing.ReferenceName("")
+	if commit == "" {
+		c, r, err := resolveGitRef(src, auth, hc.pullClient.InsecureSkipTLSVerify)
+		if err != nil {
+			return nil, err
+		}
+		commit, ref = c, r
+	}
+
+	cachedPath := resolveGitChartFilePath(commit, src.Path)
+	_, err := os.Stat(cachedPath)
+	switch {
+	case err == nil:
+		hc.log.Debug("cache hit for chart", "cachedPath", cachedPath, "git", src.URL, "commit", commit, "path", src.Path)
+		metrics.ObserveChartCacheLookup(metrics.CacheHit)
+	case os.IsNotExist(err):
+		hc.log.Debug("cache miss for chart", "cachedPath", cachedPath, "git", src.URL, "commit", commit, "path", src.Path)
+		metrics.ObserveChartCacheLookup(metrics.CacheMiss)
+		if err := hc.pullGitChartToCache(src, commit, ref, auth, cachedPath); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Wrap(err, errFailedToCheckIfLocalChartExists)
+	}
+
+	chrt, err := loader.Load(cachedPath)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToLoadChart)
+	}
+	if chrt.Metadata.Annotations == nil {
+		chrt.Metadata.Annotations = map[string]string{}
+	}
+	chrt.Metadata.Annotations[AnnotationGitCommit] = commit
+	if src.Commit == "" {
+		chrt.Metadata.Annotations[AnnotationGitRef] = src.Ref
+	}
+	return chrt, nil
+}
+
+// ResolveGitCommit resolves the ref of a chart in Git to the commit it
+// currently points to, without cloning the repository. Releases that follow a
+// branch or HEAD compare it with the commit they were deployed from.
+func ResolveGitCommit(src GitSource, creds *RepoCreds, insecureSkipTLSVerify bool) (string, error) {
+	commit, _, err := resolveGitRef(src, gitAuth(creds), insecureSkipTLSVerify)
+	return commit, err
+}
+
+// resolveGitRef resolves the ref of a chart in Git to the commit it points
+// to and to its full name, without cloning the repository.
+func resolveGitRef(src GitSource, auth transport.AuthMethod, insecureSkipTLSVerify bool) (string, plumbing.ReferenceName, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{src.URL}})
+	refs, err := remote.List(&git.ListOptions{
+		Auth:            auth,
+		InsecureSkipTLS: insecureSkipTLSVerify,
+		PeelingOption:   git.AppendPeeled,
+	})
+	if err != nil {
+		return "", "", gitFailure(errors.Wrap(err, errFailedToListGitRefs))
+	}
+
+	byName := make(map[string]*plumbing.Reference, len(refs))
+	for _, r := range refs {
+		byName[r.Name().String()] = r
+	}
+	resolve := func(name string) (string, bool) {
+		// Annotated tags point to tag objects rather than to commits. The
+		// commits are advertised under the peeled name.
+		if r, ok := byName[name+gitPeeledSuffix]; ok {
+			return r.Hash().String(), true
+		}
+		r, ok := byName[name]
+		if !ok {
+			return "", false
+		}
+		if r.Type() == plumbing.SymbolicReference {
+			if t, ok := byName[r.Target().String()]; ok {
+				return t.Hash().String(), true
+			}
+			return "", false
+		}
+		return r.Hash().String(), true
+	}
+
+	candidates := []string{gitHead}
+	if src.Ref != "" {
+		candidates = []string{src.Ref, gitRefsHeads + src.Ref, gitRefsTags + src.Ref}
+	}
+	for _, name := range candidates {
+		if commit, ok := resolve(name); ok {
+			return commit, plumbing.ReferenceName(name), nil
+		}
+	}
+	ref := src.Ref
+	if ref == "" {
+		ref = gitHead
+	}
+	return "", "", withFailure(FailureChartNotFound, errors.Errorf(errGitRefNotFoundTmpl, ref))
+}
+
+// pullGitChartToCache clones the supplied commit of a Git repository in
+// memory and caches the chart at the path of the source in it. Only the
+// commit is fetched if it is the one the supplied ref points to.
+func (hc *client) pullGitChartToCache(src GitSource, commit string, ref plumbing.ReferenceName, auth transport.AuthMethod, cachedPath string) (err error) {
+	var size int64
+	defer func(start time.Time) {
+		metrics.ObserveChartPull(src.URL, start, size, err)
+	}(time.Now())
+
+	opts := &git.CloneOptions{
+		URL:             src.URL,
+		Auth:            auth,
+		InsecureSkipTLS: hc.pullClient.InsecureSkipTLSVerify,
+		Tags:            git.NoTags,
+	}
+	if ref != "" {
+		opts.ReferenceName = ref
+		opts.SingleBranch = true
+		opts.Depth = 1
+	}
+	repo, err := git.Clone(memory.NewStorage(), nil, opts)
+	if err != nil {
+		return gitFailure(errors.Wrap(err, errFailedToCloneGitRepo))
+	}
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	switch {
+	case err != nil && ref != "":
+		// The ref moved since it was resolved. The next pull resolves it
+		// anew.
+		return errors.Wrap(err, errFailedToReadGitCommit)
+	case err != nil:
+		return withFailure(FailureChartNotFound, errors.Wrap(err, errFailedToReadGitCommit))
+	}
+	chrt, err := chartFromGitCommit(c, src.Path)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(chartCache, "")
+	if err != nil {
+		return errors.Wrap(err, errFailedToCacheGitChart)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			hc.log.WithValues("tmpDir", tmpDir).Info("failed to remove temporary directory")
+		}
+	}()
+	saved, err := chartutil.Save(chrt, tmpDir)
+	if err != nil {
+		return errors.Wrap(err, errFailedToCacheGitChart)
+	}
+	if fi, err := os.Stat(saved); err == nil {
+		size = fi.Size()
+	}
+	return errors.Wrap(os.Rename(saved, cachedPath), errFailedToCacheGitChart)
+}
+
+// chartFromGitCommit loads the chart at the supplied path of the tree of a
+// Git commit, skipping the files its .helmignore matches like helm package.
+func chartFromGitCommit(c *object.Commit, chartPath string) (*chart.Chart, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToReadGitCommit)
+	}
+	if p := strings.Trim(path.Clean("/"+chartPath), "/"); p != "" {
+		tree, err = tree.Tree(p)
+		if err != nil {
+			return nil, withFailure(FailureChartNotFound, errors.Wrapf(err, errGitChartNotFoundTmpl, chartPath, c.Hash))
+		}
+	}
+
+	rules := ignore.Empty()
+	if f, err := tree.File(ignore.HelmIgnore); err == nil {
+		r, err := f.Reader()
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToReadGitChart)
+		}
+		rules, err = ignore.Parse(r)
+		_ = r.Close()
+		if err != nil {
+			return nil, withFailure(FailureInvalidChart, errors.Wrap(err, errFailedToParseHelmIgnore))
+		}
+	}
+	rules.AddDefaults()
+
+	var files []*archive.BufferedFile
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode == filemode.Symlink || ignoredByHelm(rules, f.Name, f.Size) {
+			return nil
+		}
+		data, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		files = append(files, &archive.BufferedFile{Name: f.Name, Data: []byte(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToReadGitChart)
+	}
+	if len(files) == 0 {
+		return nil, withFailure(FailureChartNotFound, errors.Errorf(errGitChartNotFoundTmpl, chartPath, c.Hash))
+	}
+
+	chrt, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, withFailure(FailureInvalidChart, errors.Wrap(err, errFailedToPackageGitChart))
+	}
+	return chrt, nil
+}
+
+// ignoredByHelm returns true if the supplied rules match a file of a chart,
+// or any of the directories it is in.
+func ignoredByHelm(rules *ignore.Rules, name string, size int64) bool {
+	dirs := strings.Split(name, "/")
+	for i := 1; i < len(dirs); i++ {
+		dir := strings.Join(dirs[:i], "/")
+		if rules.Ignore(dir, gitFileInfo{name: dir, dir: true}) {
+			return true
+		}
+	}
+	return rules.Ignore(name, gitFileInfo{name: name, size: size})
+}
+
+// A gitFileInfo is the fs.FileInfo of a file in a Git tree, as matched by
+// the rules of a .helmignore.
+type gitFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi gitFileInfo) Name() string { return path.Base(fi.name) }
+func (fi gitFileInfo) Size() int64  { return fi.size }
+func (fi gitFileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
+func (fi gitFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi gitFileInfo) IsDir() bool        { return fi.dir }
+func (fi gitFileInfo) Sys() any           { return nil }
+
+// gitAuth returns the HTTP basic authentication of the supplied credentials,
+// or nil if there are none.
+func gitAuth(creds *RepoCreds) transport.AuthMethod {
+	if creds == nil || (creds.Username == "" && creds.Password == "") {
+		return nil
+	}
+	return &githttp.BasicAuth{Username: creds.Username, Password: creds.Password}
+}
+
+// gitFailure classifies the failures of Git operations that are permanent.
+func gitFailure(err error) error {
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed):
+		return withFailure(FailureAuth, err)
+	case errors.Is(err, transport.ErrRepositoryNotFound), errors.Is(err, transport.ErrEmptyRemoteRepository),
+		errors.Is(err, plumbing.ErrReferenceNotFound):
+		return withFailure(FailureChartNotFound, err)
+	}
+	return err
+}
+
+// resolveGitChartFilePath returns the location in the cache of the chart at
+// the supplied path of a Git commit.
+func resolveGitChartFilePath(commit, chartPath string) string {
+	filename := fmt.Sprintf("git@%s.tgz", commit)
+	if p := strings.Trim(path.Clean("/"+chartPath), "/"); p != "" {
+		filename = fmt.Sprintf("git@%s-%s.tgz", commit, strings.ReplaceAll(p, "/", "_"))
+	}
+	return filepath.Join(chartCache, filepath.Base(filename))
+}
@@ -0,0 +1,239 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/action"
+)
+
+// testGitRepository creates a bare Git repository with a chart at
+// charts/test, at version 0.1.0 in a commit tagged v1 and at version 0.2.0
+// in the next commit on the default branch. It returns the path of the
+// repository and the two commits.
+func testGitRepository(t *testing.T) (string, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	r, err := git.PlainInit(work, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+
+	commit := func(version string) string {
+		files := map[string]string{
+			"charts/test/Chart.yaml":          "apiVersion: v2\nname: test\nversion: " + version + "\n",
+			"charts/test/templates/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+			"charts/test/.helmignore":         "ignored/\n",
+			"charts/test/ignored/ignored.txt": "ignored",
+			"README.md":                       "charts",
+		}
+		for name, content := range files {
+			p := filepath.Join(work, name)
+			if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := wt.AddGlob("."); err != nil {
+			t.Fatal(err)
+		}
+		h, err := wt.Commit("Release "+version, &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h.String()
+	}
+
+	first := commit("0.1.0")
+	if _, err := r.CreateTag("v1", plumbing.NewHash(first), &git.CreateTagOptions{Tagger: sig, Message: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	second := commit("0.2.0")
+
+	bare := filepath.Join(dir, "charts.git")
+	if _, err := git.PlainClone(bare, true, &git.CloneOptions{URL: work}); err != nil {
+		t.Fatal(err)
+	}
+	return bare, first, second
+}
+
+func TestLoadGitChart(t *testing.T) {
+	repo, first, second := testGitRepository(t)
+
+	type want struct {
+		version     string
+		annotations map[string]string
+		files       []string
+		failure     Failure
+		err         bool
+	}
+	cases := map[string]struct {
+		reason string
+		cached GitSource
+		src    GitSource
+		want   want
+	}{
+		"DefaultBranch": {
+			reason: "A chart should be loaded from the default branch if no ref is set.",
+			src:    GitSource{URL: repo, Path: "charts/test"},
+			want: want{
+				version:     "0.2.0",
+				annotations: map[string]string{AnnotationGitCommit: second, AnnotationGitRef: ""},
+				files:       []string{".helmignore"},
+			},
+		},
+		"Tag": {
+			reason: "A chart should be loaded from the commit an annotated tag points to.",
+			src:    GitSource{URL: repo, Ref: "v1", Path: "charts/test"},
+			want: want{
+				version:     "0.1.0",
+				annotations: map[string]string{AnnotationGitCommit: first, AnnotationGitRef: "v1"},
+				files:       []string{".helmignore"},
+			},
+		},
+		"Commit": {
+			reason: "A chart should be loaded from the commit it is pinned to.",
+			src:    GitSource{URL: repo, Commit: first, Ref: "master", Path: "/charts/test/"},
+			want: want{
+				version:     "0.1.0",
+				annotations: map[string]string{AnnotationGitCommit: first},
+				files:       []string{".helmignore"},
+			},
+		},
+		"Cached": {
+			reason: "A chart pinned to a commit should be loaded from the chart cache if it is cached.",
+			cached: GitSource{URL: repo, Commit: first, Path: "charts/test"},
+			src:    GitSource{URL: filepath.Join(repo, "missing"), Commit: first, Path: "charts/test"},
+			want: want{
+				version:     "0.1.0",
+				annotations: map[string]string{AnnotationGitCommit: first},
+				files:       []string{".helmignore"},
+			},
+		},
+		"RefNotFound": {
+			reason: "A ref missing from the repository should fail as the chart is not found.",
+			src:    GitSource{URL: repo, Ref: "missing", Path: "charts/test"},
+			want:   want{failure: FailureChartNotFound, err: true},
+		},
+		"PathNotFound": {
+			reason: "A path missing from the commit should fail as the chart is not found.",
+			src:    GitSource{URL: repo, Path: "charts/missing"},
+			want:   want{failure: FailureChartNotFound, err: true},
+		},
+		"NotAChart": {
+			reason: "A path with no Chart.yaml should fail as an invalid chart.",
+			src:    GitSource{URL: repo},
+			want:   want{failure: FailureInvalidChart, err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			origCache := chartCache
+			chartCache = t.TempDir()
+			defer func() { chartCache = origCache }()
+
+			hc := &client{log: &mockLogger{}, pullClient: action.NewPull()}
+			if tc.cached.URL != "" {
+				if _, err := hc.loadGitChart(tc.cached, &RepoCreds{}); err != nil {
+					t.Fatalf("\n%s\nloadGitChart(...): %v", tc.reason, err)
+				}
+			}
+
+			chrt, err := hc.loadGitChart(tc.src, &RepoCreds{})
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nloadGitChart(...): unexpected error: %v", tc.reason, err)
+			}
+			if got := ClassifyFailure(err); got != tc.want.failure {
+				t.Errorf("\n%s\nClassifyFailure(...): want %q, got %q: %v", tc.reason, tc.want.failure, got, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.version, chrt.Metadata.Version); diff != "" {
+				t.Errorf("\n%s\nloadGitChart(...): -want version, +got version:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.annotations, chrt.Metadata.Annotations); diff != "" {
+				t.Errorf("\n%s\nloadGitChart(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+			files := []string{}
+			for _, f := range chrt.Files {
+				files = append(files, f.Name)
+			}
+			if diff := cmp.Diff(tc.want.files, files); diff != "" {
+				t.Errorf("\n%s\nloadGitChart(...): -want files, +got files:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// advanceGitBranch commits to the default branch of the supplied bare
+// repository and returns the new commit.
+func advanceGitBranch(t *testing.T, repo string) string {
+	t.Helper()
+	work := filepath.Join(t.TempDir(), "work")
+	r, err := git.PlainClone(work, false, &git.CloneOptions{URL: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("charts, advanced"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1, 0)}
+	h, err := wt.Commit("Advance", &git.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Push(&git.PushOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return h.String()
+}
+
+func TestResolveGitCommit(t *testing.T) {
+	repo, first, second := testGitRepository(t)
+
+	resolve := func(src GitSource) string {
+		t.Helper()
+		commit, err := ResolveGitCommit(src, &RepoCreds{}, false)
+		if err != nil {
+			t.Fatalf("ResolveGitCommit(...): %v", err)
+		}
+		return commit
+	}
+
+	if diff := cmp.Diff(first, resolve(GitSource{URL: repo, Ref: "v1"})); diff != "" {
+		t.Errorf("ResolveGitCommit(...): a tag should resolve to the commit it points to: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(second, resolve(GitSource{URL: repo})); diff != "" {
+		t.Errorf("ResolveGitCommit(...): HEAD should resolve to the last commit: -want, +got:\n%s", diff)
+	}
+
+	third := advanceGitBranch(t, repo)
+	if diff := cmp.Diff(third, resolve(GitSource{URL: repo})); diff != "" {
+		t.Errorf("ResolveGitCommit(...): HEAD should resolve to the commit the branch was advanced to: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(third, resolve(GitSource{URL: repo, Ref: "master"})); diff != "" {
+		t.Errorf("ResolveGitCommit(...): a branch should resolve to the commit it was advanced to: -want, +got:\n%s", diff)
+	}
+}
@@ -29,78 +29,97 @@ import (
 
 	clusterv1beta1 "github.com/crossplane-contrib/provider-helm/apis/cluster/v1beta1"
 	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 const (
 	errProviderConfigNotSet = "provider config is not set"
 	errGetProviderConfig    = "cannot get provider config"
 	errFailedToTrackUsage   = "cannot track provider config usage"
+
+	spanResolveProviderConfig = "ResolveProviderConfig"
 )
 
 func ResolveProviderConfig(ctx context.Context, crClient kclient.Client, lt resource.LegacyTracker, mt resource.ModernTracker, mg resource.Managed) (*kconfig.ProviderConfigSpec, error) {
+	spec, _, err := ResolveProviderConfigObject(ctx, crClient, lt, mt, mg)
+	return spec, err
+}
+
+// ResolveProviderConfigObject returns the spec of the provider config of the
+// supplied managed resource, along with the provider config itself.
+func ResolveProviderConfigObject(ctx context.Context, crClient kclient.Client, lt resource.LegacyTracker, mt resource.ModernTracker, mg resource.Managed) (spec *kconfig.ProviderConfigSpec, pc kclient.Object, err error) {
+	ctx, span := tracing.Start(ctx, spanResolveProviderConfig)
+	defer func() {
+		if pc != nil {
+			span.SetAttributes(tracing.AttrProviderConfig.String(pc.GetName()))
+		}
+		tracing.End(span, err)
+	}()
+
 	switch m := mg.(type) {
 	case resource.LegacyManaged:
 		return resolveProviderConfigLegacy(ctx, crClient, m, lt)
 	case resource.ModernManaged:
 		return resolveProviderConfigModern(ctx, crClient, m, mt)
 	default:
-		return nil, errors.New("resource is not a managed")
+		return nil, nil, errors.New("resource is not a managed")
 	}
 }
 
-func resolveProviderConfigLegacy(ctx context.Context, client kclient.Client, mg resource.LegacyManaged, lt resource.LegacyTracker) (*kconfig.ProviderConfigSpec, error) {
+func resolveProviderConfigLegacy(ctx context.Context, client kclient.Client, mg resource.LegacyManaged, lt resource.LegacyTracker) (*kconfig.ProviderConfigSpec, kclient.Object, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errProviderConfigNotSet)
+		return nil, nil, errors.New(errProviderConfigNotSet)
 	}
 	pc := &clusterv1beta1.ProviderConfig{}
 	if err := client.Get(ctx, types.NamespacedName{Name: configRef.Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
 	if err := lt.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errFailedToTrackUsage)
+		return nil, nil, errors.Wrap(err, errFailedToTrackUsage)
 	}
 
-	return legacyToModernProviderConfigSpec(pc)
+	spec, err := legacyToModernProviderConfigSpec(pc)
+	return spec, pc, err
 }
 
-func resolveProviderConfigModern(ctx context.Context, crClient kclient.Client, mg resource.ModernManaged, mt resource.ModernTracker) (*kconfig.ProviderConfigSpec, error) {
+func resolveProviderConfigModern(ctx context.Context, crClient kclient.Client, mg resource.ModernManaged, mt resource.ModernTracker) (*kconfig.ProviderConfigSpec, kclient.Object, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errProviderConfigNotSet)
+		return nil, nil, errors.New(errProviderConfigNotSet)
 	}
 
 	pcRuntimeObj, err := crClient.Scheme().New(namespacedv1beta1.SchemeGroupVersion.WithKind(configRef.Kind))
 	if err != nil {
-		return nil, errors.Wrapf(err, "referenced provider config kind %q is invalid for %s/%s", configRef.Kind, mg.GetNamespace(), mg.GetName())
+		return nil, nil, errors.Wrapf(err, "referenced provider config kind %q is invalid for %s/%s", configRef.Kind, mg.GetNamespace(), mg.GetName())
 	}
 	pcObj, ok := pcRuntimeObj.(resource.ProviderConfig)
 	if !ok {
-		return nil, errors.Errorf("referenced provider config kind %q is not a provider config type %s/%s", configRef.Kind, mg.GetNamespace(), mg.GetName())
+		return nil, nil, errors.Errorf("referenced provider config kind %q is not a provider config type %s/%s", configRef.Kind, mg.GetNamespace(), mg.GetName())
 	}
 
 	// Namespace will be ignored if the PC is a cluster-scoped type
 	if err := crClient.Get(ctx, types.NamespacedName{Name: configRef.Name, Namespace: mg.GetNamespace()}, pcObj); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
 	var pcSpec kconfig.ProviderConfigSpec
 	switch pc := pcObj.(type) {
 	case *namespacedv1beta1.ProviderConfig:
 		enrichLocalSecretRefs(pc, mg)
-		pcSpec = pc.Spec
+		pcSpec = pc.Spec.ProviderConfigSpec
 	case *namespacedv1beta1.ClusterProviderConfig:
-		pcSpec = pc.Spec
+		pcSpec = pc.Spec.ProviderConfigSpec
 	default:
 		// TODO(erhan)
-		return nil, errors.New("unknown")
+		return nil, nil, errors.New("unknown")
 	}
 
 	if err := mt.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errFailedToTrackUsage)
+		return nil, nil, errors.Wrap(err, errFailedToTrackUsage)
 	}
-	return &pcSpec, nil
+	return &pcSpec, pcObj, nil
 }
 
 func legacyToModernProviderConfigSpec(pc *clusterv1beta1.ProviderConfig) (*kconfig.ProviderConfigSpec, error) {
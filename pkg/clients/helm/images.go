@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// containerFields are the pod spec fields holding containers with an image.
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// ImagesFromManifest returns the sorted, de-duplicated container images
+// referenced by the pod specs and pod templates in a rendered manifest.
+// Documents that cannot be parsed are skipped, as Helm has already validated
+// the manifest it stored.
+func ImagesFromManifest(manifest string) []string {
+	images := sets.New[string]()
+	for _, o := range parseManifests(manifest) {
+		collectImages(o.Object, images)
+	}
+	if images.Len() == 0 {
+		return nil
+	}
+	out := images.UnsortedList()
+	sort.Strings(out)
+	return out
+}
+
+// collectImages walks an arbitrary object looking for container lists, so
+// that pods nested in any workload kind (including custom resources) are found.
+func collectImages(v interface{}, images sets.Set[string]) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, f := range containerFields {
+			cs, ok := t[f].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, c := range cs {
+				if cm, ok := c.(map[string]interface{}); ok {
+					if img, ok := cm["image"].(string); ok && img != "" {
+						images.Insert(img)
+					}
+				}
+			}
+		}
+		for _, e := range t {
+			collectImages(e, images)
+		}
+	case []interface{}:
+		for _, e := range t {
+			collectImages(e, images)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImagesFromManifest(t *testing.T) {
+	cases := map[string]struct {
+		manifest string
+		want     []string
+	}{
+		"Empty": {
+			manifest: "",
+			want:     nil,
+		},
+		"NoWorkloads": {
+			manifest: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			want:     nil,
+		},
+		"PodTemplatesAndCronJobs": {
+			manifest: testDeployment + `---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: init
+            image: busybox:1.36
+          containers:
+          - name: backup
+            image: nginx:1.14.2
+`,
+			want: []string{"busybox:1.36", "nginx:1.14.2"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ImagesFromManifest(tc.manifest)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ImagesFromManifest(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package helm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -10,6 +11,8 @@ import (
 	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 const (
@@ -21,11 +24,22 @@ const (
 // KustomizationRender Implements helm PostRenderer interface
 type KustomizationRender struct {
 	patches []types.Patch
+	images  []types.Image
 	logger  logging.Logger
+	// ctx carries the span of the operation the manifests are rendered for.
+	ctx context.Context
 }
 
-// Run runs a set of Kustomize patches against yaml input and returns the patched content.
+// Run runs a set of Kustomize patches and image overrides against yaml input
+// and returns the patched content.
 func (kr KustomizationRender) Run(renderedManifests *bytes.Buffer) (modifiedManifests *bytes.Buffer, err error) {
+	ctx := kr.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracing.Start(ctx, spanRender)
+	defer func() { tracing.End(span, err) }()
+
 	d, err := os.MkdirTemp("", helmTempDirNamePattern)
 	if err != nil {
 		return nil, err
@@ -41,6 +55,7 @@ func (kr KustomizationRender) Run(renderedManifests *bytes.Buffer) (modifiedMani
 	k := types.Kustomization{
 		Resources: []string{helmOutputFileName},
 		Patches:   kr.patches,
+		Images:    kr.images,
 	}
 
 	kdata, err := json.Marshal(k)
@@ -45,6 +45,7 @@ func TestKustomize(t *testing.T) {
 		name    string
 		base    string
 		patches []types.Patch
+		images  []types.Image
 		want    want
 	}{
 		{
@@ -66,6 +67,20 @@ func TestKustomize(t *testing.T) {
 				result: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: nginx-deployment\nspec:\n  selector:\n    matchLabels:\n      app: nginx\n      env: dev\n  template:\n    metadata:\n      labels:\n        app: nginx\n        env: dev\n    spec:\n      containers:\n      - image: nginx:1.14.2\n        name: nginx\n        ports:\n        - containerPort: 80\n      nodeSelector:\n        aws.az: us-west-2a\n        node.size: really-big\n",
 			},
 		},
+		{
+			name: "ImageOverride",
+			base: testDeployment,
+			images: []types.Image{
+				{
+					Name:    "nginx",
+					NewName: "mirror.example.com/nginx",
+					Digest:  "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+			},
+			want: want{
+				result: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: nginx-deployment\nspec:\n  selector:\n    matchLabels:\n      app: nginx\n      env: dev\n  template:\n    metadata:\n      labels:\n        app: nginx\n        env: dev\n    spec:\n      containers:\n      - image: mirror.example.com/nginx@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef\n        name: nginx\n        ports:\n        - containerPort: 80\n",
+			},
+		},
 		{
 			name: "InvalidPatch",
 			base: testDeployment,
@@ -88,6 +103,7 @@ func TestKustomize(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			k := KustomizationRender{
 				patches: tt.patches,
+				images:  tt.images,
 			}
 
 			buf := bytes.NewBuffer([]byte(tt.base))
@@ -25,9 +25,10 @@ import (
 
 // slogHandler forwards helm v4's slog-based internal logging to the provider
 // logger. Helm discards these logs unless a handler is set on the
-// action.Configuration.
+// action.Configuration. Secrets of the optional redaction are masked.
 type slogHandler struct {
-	log logging.Logger
+	log       logging.Logger
+	redaction *Redaction
 }
 
 var _ slog.Handler = slogHandler{}
@@ -42,16 +43,17 @@ func (h slogHandler) Enabled(context.Context, slog.Level) bool { return true }
 func (h slogHandler) Handle(_ context.Context, r slog.Record) error {
 	keysAndValues := make([]any, 0, 2*r.NumAttrs()+2)
 	r.Attrs(func(a slog.Attr) bool {
-		keysAndValues = append(keysAndValues, a.Key, a.Value.Any())
+		keysAndValues = append(keysAndValues, a.Key, h.mask(a.Value))
 		return true
 	})
+	msg := h.redaction.Mask(r.Message)
 	switch {
 	case r.Level < slog.LevelInfo:
-		h.log.Debug(r.Message, keysAndValues...)
+		h.log.Debug(msg, keysAndValues...)
 	case r.Level > slog.LevelInfo:
-		h.log.Info(r.Message, append(keysAndValues, "level", r.Level.String())...)
+		h.log.Info(msg, append(keysAndValues, "level", r.Level.String())...)
 	default:
-		h.log.Info(r.Message, keysAndValues...)
+		h.log.Info(msg, keysAndValues...)
 	}
 	return nil
 }
@@ -59,9 +61,23 @@ func (h slogHandler) Handle(_ context.Context, r slog.Record) error {
 func (h slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	keysAndValues := make([]any, 0, 2*len(attrs))
 	for _, a := range attrs {
-		keysAndValues = append(keysAndValues, a.Key, a.Value.Any())
+		keysAndValues = append(keysAndValues, a.Key, h.mask(a.Value))
 	}
-	return slogHandler{log: h.log.WithValues(keysAndValues...)}
+	return slogHandler{log: h.log.WithValues(keysAndValues...), redaction: h.redaction}
+}
+
+// mask masks secrets in string and error values.
+func (h slogHandler) mask(v slog.Value) any {
+	if h.redaction == nil {
+		return v.Any()
+	}
+	switch a := v.Any().(type) {
+	case string:
+		return h.redaction.Mask(a)
+	case error:
+		return h.redaction.Mask(a.Error())
+	}
+	return v.Any()
 }
 
 // WithGroup flattens groups, as logging.Logger has no grouping concept.
@@ -740,3 +740,31 @@ func TestDigestCacheRoundTrip(t *testing.T) {
 		t.Errorf("ensureChartCached() = %q, want cache hit at %q", gotPath, cachePath)
 	}
 }
+
+func TestChartRepository(t *testing.T) {
+	cases := map[string]struct {
+		chartURL  string
+		chartRepo string
+		want      string
+	}{
+		"Repository": {
+			chartRepo: "https://charts.bitnami.com/bitnami",
+			want:      "https://charts.bitnami.com/bitnami",
+		},
+		"HTTPURL": {
+			chartURL: "https://charts.bitnami.com/bitnami/wordpress-15.2.5.tgz",
+			want:     "https://charts.bitnami.com/bitnami",
+		},
+		"OCIURL": {
+			chartURL: "oci://registry-1.docker.io/bitnamicharts/wordpress:15.2.5",
+			want:     "oci://registry-1.docker.io/bitnamicharts",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, chartRepository(tc.chartURL, tc.chartRepo)); diff != "" {
+				t.Errorf("chartRepository(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/release"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+const (
+	// secretMask replaces secrets in log output and errors.
+	secretMask = "REDACTED"
+	// minMaskedSecretLength is the minimum length of a secret to be masked.
+	// Shorter values, like booleans and small numbers, would mask unrelated
+	// output.
+	minMaskedSecretLength = 4
+)
+
+// Redaction describes values that must neither be stored by Helm nor logged
+// in clear text.
+type Redaction struct {
+	// Values are stored in place of the values a release was installed or
+	// upgraded with.
+	Values map[string]any
+	// Secrets are masked in log output and errors.
+	Secrets []string
+}
+
+// Mask replaces all secrets in the supplied string.
+func (r *Redaction) Mask(s string) string {
+	if r == nil {
+		return s
+	}
+	secrets := make([]string, 0, len(r.Secrets))
+	for _, sec := range r.Secrets {
+		if len(sec) >= minMaskedSecretLength {
+			secrets = append(secrets, sec)
+		}
+	}
+	// Replace longer secrets first, so that secrets containing others are
+	// masked as a whole.
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, sec := range secrets {
+		s = strings.ReplaceAll(s, sec, secretMask)
+	}
+	return s
+}
+
+// MaskError returns an error with all secrets in the message of the supplied
+// error masked. The error is returned as is if it contains no secrets.
+func (r *Redaction) MaskError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if m := r.Mask(err.Error()); m != err.Error() {
+		return errors.New(m)
+	}
+	return err
+}
+
+// redactingDriver is a Helm storage driver that stores releases with their
+// values replaced by redacted values.
+type redactingDriver struct {
+	driver.Driver
+	values map[string]any
+}
+
+func (d redactingDriver) Create(key string, rls release.Releaser) error {
+	return d.Driver.Create(key, d.redact(rls))
+}
+
+func (d redactingDriver) Update(key string, rls release.Releaser) error {
+	return d.Driver.Update(key, d.redact(rls))
+}
+
+// redact returns a copy of the supplied release with redacted values, leaving
+// the release Helm keeps working with untouched.
+func (d redactingDriver) redact(rls release.Releaser) release.Releaser {
+	r, ok := rls.(*rspb.Release)
+	if !ok || r == nil {
+		return rls
+	}
+	c := *r
+	c.Config = d.values
+	return &c
+}
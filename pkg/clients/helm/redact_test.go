@@ -0,0 +1,71 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/release/common"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+func TestRedactionMaskError(t *testing.T) {
+	r := &Redaction{Secrets: []string{"s3cr3t", "s3cr3t-long", "1"}}
+
+	cases := map[string]struct {
+		r    *Redaction
+		err  error
+		want error
+	}{
+		"Nil": {
+			r:    r,
+			err:  nil,
+			want: nil,
+		},
+		"NoRedaction": {
+			err:  errors.New("password is s3cr3t"),
+			want: errors.New("password is s3cr3t"),
+		},
+		"NoSecrets": {
+			r:    r,
+			err:  errors.Wrap(errors.New("boom"), "failed"),
+			want: errors.Wrap(errors.New("boom"), "failed"),
+		},
+		"LongestSecretFirst": {
+			r:    r,
+			err:  errors.New("passwords are s3cr3t-long and s3cr3t, 1 is too short to be masked"),
+			want: errors.New("passwords are REDACTED and REDACTED, 1 is too short to be masked"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.r.MaskError(tc.err)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("MaskError(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRedactingDriver(t *testing.T) {
+	redacted := map[string]any{"password": "REDACTED"}
+	d := redactingDriver{Driver: driver.NewMemory(), values: redacted}
+
+	rel := &rspb.Release{Name: "test", Version: 1, Info: &rspb.Info{Status: common.StatusDeployed}, Config: map[string]any{"password": "s3cr3t"}}
+	if err := d.Create("test.v1", rel); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"password": "s3cr3t"}, rel.Config); diff != "" {
+		t.Errorf("Create(...): -want unchanged release config, +got release config: %s", diff)
+	}
+
+	got, err := d.Get("test.v1")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	if diff := cmp.Diff(redacted, got.(*rspb.Release).Config); diff != "" {
+		t.Errorf("Get(...): -want stored config, +got stored config: %s", diff)
+	}
+}
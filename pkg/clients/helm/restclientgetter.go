@@ -19,21 +19,24 @@ package helm
 import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// A restClientGetter is shared by the Helm clients of a namespace of a target
+// cluster. It hands them the cached discovery of the target cluster, so that
+// they do not discover its APIs on every reconcile.
 type restClientGetter struct {
 	Namespace string
 	config    *rest.Config
+	discovery *discoveryCache
 }
 
-func newRESTClientGetter(config *rest.Config, namespace string) *restClientGetter {
+func newRESTClientGetter(config *rest.Config, namespace string, dc *discoveryCache) *restClientGetter {
 	return &restClientGetter{
 		Namespace: namespace,
 		config:    config,
+		discovery: dc,
 	}
 }
 
@@ -42,30 +45,11 @@ func (c *restClientGetter) ToRESTConfig() (*rest.Config, error) {
 }
 
 func (c *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
-	config, err := c.ToRESTConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	// The more groups you have, the more discovery requests you need to make.
-	// given 25 groups (our groups + a few custom conf) with one-ish version each, discovery needs to make 50 requests
-	// double it just so we don't end up here again for a while.  This config is only used for discovery.
-	// Align value with https://github.com/kubernetes/kubernetes/pull/109141
-	config.Burst = 300
-
-	discoveryClient, _ := discovery.NewDiscoveryClientForConfig(config)
-	return memory.NewMemCacheClient(discoveryClient), nil
+	return c.discovery.Client(), nil
 }
 
 func (c *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
-	discoveryClient, err := c.ToDiscoveryClient()
-	if err != nil {
-		return nil, err
-	}
-
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
-	expander := restmapper.NewShortcutExpander(mapper, discoveryClient, nil)
-	return expander, nil
+	return c.discovery.RESTMapper(), nil
 }
 
 func (c *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
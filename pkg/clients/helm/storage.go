@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/release"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+// Storage drivers Helm can store release state with.
+const (
+	StorageDriverSecret       = "Secret"
+	StorageDriverConfigMap    = "ConfigMap"
+	StorageDriverSQL          = "SQL"
+	StorageDriverControlPlane = "ControlPlane"
+)
+
+const (
+	errUnknownStorageDriverTmpl  = "unknown storage driver %q"
+	errFailedToCreateSQLDriver   = "failed to create SQL storage driver"
+	errControlPlaneSecretsNotSet = "control plane secrets are required by the ControlPlane storage driver"
+)
+
+// LabelStorageScope labels the release state stored by the ControlPlane and
+// SQL storage drivers with the scope of the target cluster it belongs to.
+const LabelStorageScope = "helm.crossplane.io/storage-scope"
+
+// helmKeyPrefix prefixes the keys Helm stores release state with.
+const helmKeyPrefix = "sh.helm.release"
+
+// helmDriver returns the name of the driver the action configuration is
+// initialized with. Drivers that store release state outside of the target
+// cluster replace it once initialized.
+func helmDriver(storageDriver string) (string, error) {
+	switch storageDriver {
+	case "", StorageDriverSecret, StorageDriverSQL, StorageDriverControlPlane:
+		return helmDriverSecret, nil
+	case StorageDriverConfigMap:
+		return helmDriverConfigMap, nil
+	}
+	return "", errors.Errorf(errUnknownStorageDriverTmpl, storageDriver)
+}
+
+// storageDriver returns the driver storing release state outside of the target
+// cluster, or nil if it is stored in the target cluster.
+func storageDriver(args *Args, log slog.Handler) (driver.Driver, error) {
+	switch args.StorageDriver {
+	case StorageDriverSQL:
+		d, err := sqlDrivers.get(args.SQLConnectionString, args.Namespace, log)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToCreateSQLDriver)
+		}
+		return scoped(d, args.StorageScope), nil
+	case StorageDriverControlPlane:
+		if args.ControlPlaneSecrets == nil {
+			return nil, errors.New(errControlPlaneSecretsNotSet)
+		}
+		d := driver.NewSecrets(args.ControlPlaneSecrets)
+		d.SetLogger(log)
+		return scoped(d, args.StorageScope), nil
+	}
+	return nil, nil
+}
+
+// scoped returns the supplied driver scoped to the supplied target cluster
+// identity, or the driver itself if there is none.
+func scoped(d driver.Driver, identity string) driver.Driver {
+	if identity == "" {
+		return d
+	}
+	return newScopedDriver(d, identity)
+}
+
+// sqlDrivers are the SQL storage drivers shared by all Helm clients. Each
+// holds a pool of database connections and migrates the database schema when
+// it is created, and Helm offers no way to close it, so a driver is created
+// once per connection string and namespace rather than once per client.
+var sqlDrivers = &sqlDriverCache{entries: map[string]*sqlDriverEntry{}}
+
+type sqlDriverEntry struct {
+	mu     sync.Mutex
+	driver *driver.SQL
+}
+
+type sqlDriverCache struct {
+	mu      sync.Mutex
+	entries map[string]*sqlDriverEntry
+}
+
+// get returns the SQL driver of the supplied connection string and namespace,
+// creating it if there is none yet. Concurrent callers wait for a driver being
+// created rather than creating their own. The driver logs with the supplied
+// handler of the client it was created for.
+func (c *sqlDriverCache) get(connectionString, namespace string, log slog.Handler) (*driver.SQL, error) {
+	// The connection string holds the database credentials, so only its hash
+	// is kept.
+	h := sha256.Sum256([]byte(connectionString))
+	key := hex.EncodeToString(h[:]) + "/" + namespace
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &sqlDriverEntry{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.driver != nil {
+		return e.driver, nil
+	}
+	d, err := driver.NewSQL(connectionString, namespace)
+	if err != nil {
+		return nil, err
+	}
+	d.SetLogger(log)
+	e.driver = d
+	return d, nil
+}
+
+// A scopedDriver keeps the release state of the target clusters sharing a
+// driver apart. Helm keys release state by the name of the release alone, so
+// releases of the same name on different target clusters would otherwise
+// overwrite each other's history. Their state is keyed by the scope, too, and
+// labelled with it so that they are only listed and queried within it.
+type scopedDriver struct {
+	driver.Driver
+	scope string
+}
+
+// newScopedDriver returns a driver scoped to the supplied target cluster
+// identity. The identity is hashed, so that it can be used in keys and labels
+// whatever its form.
+func newScopedDriver(d driver.Driver, identity string) scopedDriver {
+	h := sha256.Sum256([]byte(identity))
+	return scopedDriver{Driver: d, scope: hex.EncodeToString(h[:4])}
+}
+
+// key returns the supplied key of Helm with the scope in place of its
+// "release" part. Unlike a suffix, this keeps keys within the length the SQL
+// driver stores them with.
+func (d scopedDriver) key(key string) string {
+	if rest, ok := strings.CutPrefix(key, helmKeyPrefix); ok {
+		return "sh.helm." + d.scope + rest
+	}
+	return key + "." + d.scope
+}
+
+func (d scopedDriver) Get(key string) (release.Releaser, error) {
+	return d.Driver.Get(d.key(key))
+}
+
+func (d scopedDriver) Create(key string, rls release.Releaser) error {
+	return d.Driver.Create(d.key(key), d.label(rls))
+}
+
+func (d scopedDriver) Update(key string, rls release.Releaser) error {
+	return d.Driver.Update(d.key(key), d.label(rls))
+}
+
+func (d scopedDriver) Delete(key string) (release.Releaser, error) {
+	return d.Driver.Delete(d.key(key))
+}
+
+func (d scopedDriver) List(filter func(release.Releaser) bool) ([]release.Releaser, error) {
+	return d.Driver.List(func(rls release.Releaser) bool {
+		r, ok := rls.(*rspb.Release)
+		return ok && r.Labels[LabelStorageScope] == d.scope && filter(rls)
+	})
+}
+
+// Query returns the releases of the scope matching the supplied labels. The
+// scope is not queried for, as the SQL driver only queries by the labels Helm
+// sets itself.
+func (d scopedDriver) Query(labels map[string]string) ([]release.Releaser, error) {
+	rls, err := d.Driver.Query(labels)
+	if err != nil {
+		return nil, err
+	}
+	in := make([]release.Releaser, 0, len(rls))
+	for _, rl := range rls {
+		if r, ok := rl.(*rspb.Release); ok && r.Labels[LabelStorageScope] == d.scope {
+			in = append(in, rl)
+		}
+	}
+	if len(in) == 0 {
+		return nil, driver.ErrReleaseNotFound
+	}
+	return in, nil
+}
+
+// label returns a copy of the supplied release labelled with the scope,
+// leaving the release Helm keeps working with untouched.
+func (d scopedDriver) label(rls release.Releaser) release.Releaser {
+	r, ok := rls.(*rspb.Release)
+	if !ok || r == nil {
+		return rls
+	}
+	c := *r
+	c.Labels = maps.Clone(r.Labels)
+	if c.Labels == nil {
+		c.Labels = map[string]string{}
+	}
+	c.Labels[LabelStorageScope] = d.scope
+	return &c
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/kube"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	errFailedToGetReleaseToUninstall = "failed to get release to uninstall"
+	errFailedToKeepResources         = "failed to mark resources to keep on uninstall"
+	errFailedToParseManifest         = "failed to parse release manifest"
+)
+
+// keptResources returns the resources of a release that Helm keeps on
+// uninstall, as kind/name. Resources of the kinds to keep are annotated with
+// the Helm keep resource policy in the stored release first, as Helm reads
+// the resources to delete from the stored manifest.
+func (hc *client) keptResources(name string) ([]string, error) {
+	rls, err := hc.releases.Last(name)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetReleaseToUninstall)
+	}
+	rel, ok := rls.(*rspb.Release)
+	if !ok || rel == nil {
+		return nil, nil
+	}
+
+	manifest, kept, err := keepKinds(rel.Manifest, hc.keepKinds)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToKeepResources)
+	}
+	if manifest == rel.Manifest {
+		return kept, nil
+	}
+
+	c := *rel
+	c.Manifest = manifest
+	return kept, errors.Wrap(hc.releases.Update(&c), errFailedToKeepResources)
+}
+
+// keepKinds adds the Helm keep resource policy annotation to the resources of
+// the supplied kinds in a manifest. It returns the manifest, and all resources
+// annotated with the keep resource policy as kind/name. The manifest is
+// returned unchanged if no annotation was added.
+func keepKinds(manifest string, kinds []string) (string, []string, error) {
+	keep := sets.New(kinds...)
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	changed := false
+	out := make([]string, 0, len(keys))
+	var kept []string
+	for _, k := range keys {
+		doc := docs[k]
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &u.Object); err != nil {
+			return "", nil, errors.Wrap(err, errFailedToParseManifest)
+		}
+		if len(u.Object) == 0 {
+			out = append(out, doc)
+			continue
+		}
+
+		a := u.GetAnnotations()
+		if keep.Has(u.GetKind()) && a[kube.ResourcePolicyAnno] != kube.KeepPolicy {
+			if a == nil {
+				a = map[string]string{}
+			}
+			a[kube.ResourcePolicyAnno] = kube.KeepPolicy
+			u.SetAnnotations(a)
+			b, err := yaml.Marshal(u.Object)
+			if err != nil {
+				return "", nil, errors.Wrap(err, errFailedToParseManifest)
+			}
+			doc = string(b)
+			changed = true
+		}
+		// Helm compares the resource policy case-insensitively.
+		if strings.EqualFold(strings.TrimSpace(a[kube.ResourcePolicyAnno]), kube.KeepPolicy) {
+			kept = append(kept, u.GetKind()+"/"+u.GetName())
+		}
+		out = append(out, doc)
+	}
+
+	if !changed {
+		return manifest, kept, nil
+	}
+	return "---\n" + strings.Join(out, "\n---\n"), kept, nil
+}
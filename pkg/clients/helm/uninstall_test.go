@@ -0,0 +1,108 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/release/common"
+	rspb "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+const testKeepManifest = `---
+# Source: db/templates/pvc.yaml
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+---
+# Source: db/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  annotations:
+    helm.sh/resource-policy: Keep
+---
+# Source: db/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+`
+
+func TestKeepKinds(t *testing.T) {
+	type want struct {
+		manifest string
+		kept     []string
+		err      error
+	}
+	cases := map[string]struct {
+		kinds []string
+		want  want
+	}{
+		"AnnotatedOnly": {
+			want: want{
+				manifest: testKeepManifest,
+				kept:     []string{"Secret/credentials"},
+			},
+		},
+		"KeepKinds": {
+			kinds: []string{"PersistentVolumeClaim", "Secret"},
+			want: want{
+				manifest: "---\napiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  annotations:\n    helm.sh/resource-policy: keep\n  name: data\n" +
+					"\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  annotations:\n    helm.sh/resource-policy: keep\n  name: credentials\n" +
+					"\n---\n# Source: db/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: db\n",
+				kept: []string{"PersistentVolumeClaim/data", "Secret/credentials"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			manifest, kept, err := keepKinds(testKeepManifest, tc.kinds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("keepKinds(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.manifest, manifest); diff != "" {
+				t.Errorf("keepKinds(...): -want manifest, +got manifest: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.kept, kept); diff != "" {
+				t.Errorf("keepKinds(...): -want kept, +got kept: %s", diff)
+			}
+		})
+	}
+}
+
+func TestKeptResourcesUpdatesStoredRelease(t *testing.T) {
+	releases := storage.Init(driver.NewMemory())
+	rel := &rspb.Release{
+		Name:      "db",
+		Namespace: "default",
+		Version:   1,
+		Info:      &rspb.Info{Status: common.StatusDeployed},
+		Manifest:  testKeepManifest,
+	}
+	if err := releases.Create(rel); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	hc := &client{releases: releases, keepKinds: []string{"PersistentVolumeClaim"}}
+	kept, err := hc.keptResources("db")
+	if err != nil {
+		t.Fatalf("keptResources(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"PersistentVolumeClaim/data", "Secret/credentials"}, kept); diff != "" {
+		t.Errorf("keptResources(...): -want, +got: %s", diff)
+	}
+
+	stored, err := releases.Last("db")
+	if err != nil {
+		t.Fatalf("Last(...): %v", err)
+	}
+	_, kept, _ = keepKinds(stored.(*rspb.Release).Manifest, nil)
+	if diff := cmp.Diff([]string{"PersistentVolumeClaim/data", "Secret/credentials"}, kept); diff != "" {
+		t.Errorf("stored manifest: -want kept, +got kept: %s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"sort"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/postrenderer"
+	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// A ManifestValidator validates the rendered objects of a release before they
+// are applied. Returning an error blocks the install or upgrade.
+type ManifestValidator interface {
+	Validate(objects []*unstructured.Unstructured) error
+}
+
+// validatingRender is a Helm post-renderer that runs an optional post-render
+// step and validates its output.
+type validatingRender struct {
+	next       postrenderer.PostRenderer
+	validators []ManifestValidator
+}
+
+// Run runs the wrapped post-renderer, if any, and validates the result.
+func (vr validatingRender) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	out := renderedManifests
+	if vr.next != nil {
+		var err error
+		if out, err = vr.next.Run(renderedManifests); err != nil {
+			return nil, err
+		}
+	}
+
+	objs := parseManifests(out.String())
+	for _, v := range vr.validators {
+		if err := v.Validate(objs); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// validateCRDs validates the CRDs in the crds/ directory of a chart. Helm
+// installs them as they are rather than rendering them, so they never reach
+// the post-renderer. Violations fail like those of rendered objects.
+func (hc *client) validateCRDs(chrt *chart.Chart) error {
+	if len(hc.validators) == 0 {
+		return nil
+	}
+	var objs []*unstructured.Unstructured
+	for _, crd := range chrt.CRDObjects() {
+		objs = append(objs, parseManifests(string(crd.File.Data))...)
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+	for _, v := range hc.validators {
+		if err := v.Validate(objs); err != nil {
+			return withFailure(FailureRender, err)
+		}
+	}
+	return nil
+}
+
+// parseManifests returns the objects of a multi-document manifest in the order
+// they appear. Empty documents and documents that cannot be parsed are
+// skipped; Helm reports the latter itself.
+func parseManifests(manifest string) []*unstructured.Unstructured {
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	objs := make([]*unstructured.Unstructured, 0, len(keys))
+	for _, k := range keys {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(docs[k]), &obj); err != nil || len(obj) == 0 {
+			continue
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: obj})
+	}
+	return objs
+}
+
+// ObjectsFromManifest returns the objects of a rendered manifest in the order
+// they appear, skipping documents that cannot be parsed.
+func ObjectsFromManifest(manifest string) []*unstructured.Unstructured {
+	return parseManifests(manifest)
+}
@@ -0,0 +1,155 @@
+package helm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/chart/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+type validatorFn func(objects []*unstructured.Unstructured) error
+
+func (fn validatorFn) Validate(objects []*unstructured.Unstructured) error { return fn(objects) }
+
+func TestValidatingRender(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		result string
+		kinds  []string
+		err    error
+	}
+	cases := map[string]struct {
+		next      *KustomizationRender
+		validator func(kinds *[]string) validatorFn
+		want      want
+	}{
+		"PassThrough": {
+			validator: func(kinds *[]string) validatorFn {
+				return func(objects []*unstructured.Unstructured) error {
+					for _, o := range objects {
+						*kinds = append(*kinds, o.GetKind())
+					}
+					return nil
+				}
+			},
+			want: want{
+				result: testDeployment,
+				kinds:  []string{"Deployment"},
+			},
+		},
+		"ValidatesPostRenderedOutput": {
+			next: &KustomizationRender{
+				images: []types.Image{{Name: "nginx", NewTag: "1.25"}},
+			},
+			validator: func(kinds *[]string) validatorFn {
+				return func(objects []*unstructured.Unstructured) error {
+					c, _, _ := unstructured.NestedSlice(objects[0].Object, "spec", "template", "spec", "containers")
+					*kinds = append(*kinds, c[0].(map[string]interface{})["image"].(string))
+					return nil
+				}
+			},
+			want: want{
+				result: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: nginx-deployment\nspec:\n  selector:\n    matchLabels:\n      app: nginx\n      env: dev\n  template:\n    metadata:\n      labels:\n        app: nginx\n        env: dev\n    spec:\n      containers:\n      - image: nginx:1.25\n        name: nginx\n        ports:\n        - containerPort: 80\n",
+				kinds:  []string{"nginx:1.25"},
+			},
+		},
+		"ValidationFails": {
+			validator: func(_ *[]string) validatorFn {
+				return func(_ []*unstructured.Unstructured) error { return errBoom }
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var kinds []string
+			vr := validatingRender{validators: []ManifestValidator{tc.validator(&kinds)}}
+			if tc.next != nil {
+				vr.next = tc.next
+			}
+			out, gotErr := vr.Run(bytes.NewBufferString(testDeployment))
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("Run(...): -want error, +got error:\n%s", diff)
+			}
+			if gotErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.result, out.String()); diff != "" {
+				t.Errorf("Run(...): -want result, +got result:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.kinds, kinds); diff != "" {
+				t.Errorf("Run(...): -want validated, +got validated:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCRDs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		names   []string
+		failure Failure
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		files  []*common.File
+		fail   bool
+		want   want
+	}{
+		"NoCRDs": {
+			reason: "Validators should not be called for a chart without CRDs.",
+			files:  []*common.File{{Name: "templates/deployment.yaml", Data: []byte(testDeployment)}},
+		},
+		"Valid": {
+			reason: "The CRDs in the crds/ directory of a chart should be validated.",
+			files: []*common.File{
+				{Name: "crds/widgets.yaml", Data: []byte(testCRD)},
+				{Name: "templates/deployment.yaml", Data: []byte(testDeployment)},
+			},
+			want: want{names: []string{"widgets.example.org"}},
+		},
+		"Invalid": {
+			reason: "A CRD failing validation should fail like a rendered object failing it.",
+			files:  []*common.File{{Name: "crds/widgets.yaml", Data: []byte(testCRD)}},
+			fail:   true,
+			want:   want{names: []string{"widgets.example.org"}, failure: FailureRender, err: withFailure(FailureRender, errBoom)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			hc := &client{validators: []ManifestValidator{validatorFn(func(objects []*unstructured.Unstructured) error {
+				for _, o := range objects {
+					names = append(names, o.GetName())
+				}
+				if tc.fail {
+					return errBoom
+				}
+				return nil
+			})}}
+			chrt := testChart("crds", "1.0.0")
+			chrt.Files = tc.files
+
+			err := hc.validateCRDs(chrt)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateCRDs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got := ClassifyFailure(err); got != tc.want.failure {
+				t.Errorf("\n%s\nClassifyFailure(...): want %q, got %q", tc.reason, tc.want.failure, got)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nvalidateCRDs(...): -want validated, +got validated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
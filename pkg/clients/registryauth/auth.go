@@ -19,6 +19,7 @@ package registryauth
 import (
 	"context"
 	"io"
+	"net/url"
 	"strings"
 
 	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
@@ -34,6 +35,8 @@ import (
 	clusterv1beta1 "github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
 	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
 	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/metrics"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 var (
@@ -46,6 +49,8 @@ const (
 	keyRepoPassword      = "password"
 	errFailedToGetSecret = "failed to get registry secret"
 	errFailedToParseRef  = "failed to parse registry reference"
+
+	spanResolve = "ResolveRegistryCredentials"
 )
 
 func normalizeRegistryURL(registryURL string) string {
@@ -89,7 +94,20 @@ func NewResolver(kube client.Client) *Resolver {
 }
 
 // ResolveNamespaced resolves registry credentials for a namespaced Release
-func (r *Resolver) ResolveNamespaced(ctx context.Context, release *namespacedv1beta1.Release) (*helmClient.RepoCreds, error) {
+func (r *Resolver) ResolveNamespaced(ctx context.Context, release *namespacedv1beta1.Release) (creds *helmClient.RepoCreds, err error) {
+	ctx, span := tracing.Start(ctx, spanResolve,
+		tracing.AttrChart.String(release.Spec.ForProvider.Chart.Name),
+		tracing.AttrVersion.String(release.Spec.ForProvider.Chart.Version))
+	defer func() { tracing.End(span, err) }()
+
+	// Charts in Git are only authenticated to with credentials from a Secret
+	if g := release.Spec.ForProvider.Chart.Git; g != nil {
+		if g.SecretRef == nil {
+			return &helmClient.RepoCreds{}, nil
+		}
+		return r.resolveSecretCredentials(ctx, release.Namespace, g.SecretRef.Name)
+	}
+
 	registryURL := release.Spec.ForProvider.Chart.Repository
 	if registryURL == "" {
 		registryURL = release.Spec.ForProvider.Chart.URL
@@ -112,7 +130,23 @@ func (r *Resolver) ResolveNamespaced(ctx context.Context, release *namespacedv1b
 }
 
 // ResolveCluster resolves registry credentials for a cluster-scoped Release
-func (r *Resolver) ResolveCluster(ctx context.Context, release *clusterv1beta1.Release) (*helmClient.RepoCreds, error) {
+func (r *Resolver) ResolveCluster(ctx context.Context, release *clusterv1beta1.Release) (creds *helmClient.RepoCreds, err error) {
+	ctx, span := tracing.Start(ctx, spanResolve,
+		tracing.AttrChart.String(release.Spec.ForProvider.Chart.Name),
+		tracing.AttrVersion.String(release.Spec.ForProvider.Chart.Version))
+	defer func() { tracing.End(span, err) }()
+
+	// Charts in Git are only authenticated to with credentials from a Secret
+	if g := release.Spec.ForProvider.Chart.Git; g != nil {
+		if g.SecretRef == nil {
+			return &helmClient.RepoCreds{}, nil
+		}
+		if g.SecretRef.Namespace == "" {
+			return nil, errors.New("namespace required in git SecretRef for cluster-scoped Release")
+		}
+		return r.resolveSecretCredentials(ctx, g.SecretRef.Namespace, g.SecretRef.Name)
+	}
+
 	registryURL := release.Spec.ForProvider.Chart.Repository
 	if registryURL == "" {
 		registryURL = release.Spec.ForProvider.Chart.URL
@@ -138,6 +172,52 @@ func (r *Resolver) ResolveCluster(ctx context.Context, release *clusterv1beta1.R
 	return r.resolveKeychainAuth(ctx, fullRepoURL)
 }
 
+// ResolveRepository resolves registry credentials for a chart repository
+// that is not configured by a Release, e.g. that of a chart dependency.
+func (r *Resolver) ResolveRepository(ctx context.Context, repository string) (creds *helmClient.RepoCreds, err error) {
+	ctx, span := tracing.Start(ctx, spanResolve, tracing.AttrRepository.String(repository))
+	defer func() { tracing.End(span, err) }()
+
+	// Use default credential chain (AWS IRSA, Azure/GCP Workload Identity, etc.)
+	return r.resolveKeychainAuth(ctx, repository)
+}
+
+// ResolveNamespacedDependency resolves registry credentials for the
+// repository of a dependency of the chart of a namespaced Release
+func (r *Resolver) ResolveNamespacedDependency(ctx context.Context, release *namespacedv1beta1.Release, repository string) (*helmClient.RepoCreds, error) {
+	for _, s := range release.Spec.ForProvider.Chart.DependencyPullSecrets {
+		if servedBy(repository, s.Host) {
+			return r.resolveSecretCredentials(ctx, release.Namespace, s.SecretRef.Name)
+		}
+	}
+	return r.ResolveRepository(ctx, repository)
+}
+
+// ResolveClusterDependency resolves registry credentials for the repository
+// of a dependency of the chart of a cluster-scoped Release
+func (r *Resolver) ResolveClusterDependency(ctx context.Context, release *clusterv1beta1.Release, repository string) (*helmClient.RepoCreds, error) {
+	for _, s := range release.Spec.ForProvider.Chart.DependencyPullSecrets {
+		if !servedBy(repository, s.Host) {
+			continue
+		}
+		if s.SecretRef.Namespace == "" {
+			return nil, errors.New("namespace required in dependency pull secret for cluster-scoped Release")
+		}
+		return r.resolveSecretCredentials(ctx, s.SecretRef.Namespace, s.SecretRef.Name)
+	}
+	return r.ResolveRepository(ctx, repository)
+}
+
+// servedBy returns true if the supplied repository is served by the supplied
+// host, e.g. oci://ghcr.io/org/charts by ghcr.io.
+func servedBy(repository, host string) bool {
+	u, err := url.Parse(strings.TrimSpace(repository))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, strings.TrimSpace(host))
+}
+
 func (r *Resolver) resolveSecretCredentials(ctx context.Context, namespace, name string) (*helmClient.RepoCreds, error) {
 	secret := &corev1.Secret{}
 	if err := r.kube.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
@@ -151,6 +231,7 @@ func (r *Resolver) resolveSecretCredentials(ctx context.Context, namespace, name
 		return nil, errors.New("secret must contain 'username' and 'password' keys")
 	}
 
+	metrics.ObserveRegistryAuth(metrics.AuthSourceSecret)
 	return &helmClient.RepoCreds{
 		Username: username,
 		Password: password,
@@ -166,30 +247,45 @@ func (r *Resolver) resolveKeychainAuth(ctx context.Context, registryURL string)
 		return nil, err
 	}
 
-	keychain := r.createKeychain()
+	// Try the keychains one by one rather than as a MultiKeychain, to tell
+	// which of them the credentials came from.
+	for _, kc := range r.createKeychains() {
+		authenticator, err := authn.Resolve(ctx, kc.keychain, ref.Context())
+		if err != nil {
+			// If keychain resolution fails, use public/anonymous access
+			break
+		}
+		if authenticator == authn.Anonymous {
+			continue
+		}
+		return r.resolveCredentialsFromAuthenticator(ctx, kc.source, authenticator), nil
+	}
+	metrics.ObserveRegistryAuth(metrics.AuthSourceAnonymous)
+	return &helmClient.RepoCreds{}, nil
+}
 
-	return r.resolveCredentialsFromKeychain(ctx, keychain, ref.Context())
+// A namedKeychain is a keychain and the source of the credentials it
+// resolves, as recorded in metrics.
+type namedKeychain struct {
+	source   string
+	keychain authn.Keychain
 }
 
-// createKeychain creates a keychain that supports cloud provider authentication.
-// Uses credential helpers (AWS ECR, GCP, Azure).
-func (r *Resolver) createKeychain() authn.Keychain {
-	// Create a custom MultiKeychain with cloud providers first, then fallbacks.
-	//
+// createKeychains creates the keychains that support cloud provider
+// authentication, in the order they are tried. Uses credential helpers (AWS
+// ECR, GCP, Azure).
+func (r *Resolver) createKeychains() []namedKeychain {
 	// Priority:
 	// 1. amazonKeychain - AWS ECR via IRSA (AWS_WEB_IDENTITY_TOKEN_FILE, AWS_ROLE_ARN)
 	// 2. google.Keychain - GCP GAR/GCR via Workload Identity or metadata service
 	// 3. azureKeychain - Azure ACR via Workload Identity or metadata service
 	// 4. authn.DefaultKeychain - Docker config.json and credential helpers
-
-	keychains := []authn.Keychain{
-		amazonKeychain,
-		google.Keychain,
-		azureKeychain,
-		authn.DefaultKeychain,
+	return []namedKeychain{
+		{source: metrics.AuthSourceECR, keychain: amazonKeychain},
+		{source: metrics.AuthSourceGCP, keychain: google.Keychain},
+		{source: metrics.AuthSourceACR, keychain: azureKeychain},
+		{source: metrics.AuthSourceDocker, keychain: authn.DefaultKeychain},
 	}
-
-	return authn.NewMultiKeychain(keychains...)
 }
 
 // parseRegistryReference converts a Helm OCI URL to a container registry reference
@@ -205,25 +301,20 @@ func parseRegistryReference(registryURL string) (name.Reference, error) {
 	return repo.Tag("latest"), nil
 }
 
-// resolveCredentialsFromKeychain resolves credentials from a keychain for a given resource.
-// Returns empty credentials if authentication is not available (for public registries or anonymous access).
-func (r *Resolver) resolveCredentialsFromKeychain(ctx context.Context, keychain authn.Keychain, resource authn.Resource) (*helmClient.RepoCreds, error) {
-	authenticator, err := authn.Resolve(ctx, keychain, resource)
-	if err != nil {
-		// If keychain resolution fails, return empty credentials for public/anonymous access
-		//nolint:nilerr
-		return &helmClient.RepoCreds{}, nil
-	}
-
+// resolveCredentialsFromAuthenticator resolves credentials from an
+// authenticator of the supplied source. Returns empty credentials if
+// authorization is not available (for public registries or anonymous access).
+func (r *Resolver) resolveCredentialsFromAuthenticator(ctx context.Context, source string, authenticator authn.Authenticator) *helmClient.RepoCreds {
 	authConfig, err := authn.Authorization(ctx, authenticator)
 	if err != nil {
 		// If authorization fails, return empty credentials for public/anonymous access
-		//nolint:nilerr
-		return &helmClient.RepoCreds{}, nil
+		metrics.ObserveRegistryAuth(metrics.AuthSourceAnonymous)
+		return &helmClient.RepoCreds{}
 	}
 
+	metrics.ObserveRegistryAuth(source)
 	return &helmClient.RepoCreds{
 		Username: authConfig.Username,
 		Password: authConfig.Password,
-	}, nil
+	}
 }
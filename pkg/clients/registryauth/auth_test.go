@@ -89,6 +89,44 @@ func TestResolveNamespaced(t *testing.T) {
 				err:   nil,
 			},
 		},
+		"GitSecret": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok && key.Name == testSecretName {
+							s.Data = map[string][]byte{
+								"username": []byte(testUsername),
+								"password": []byte(testPassword),
+							}
+							return nil
+						}
+						return errBoom
+					},
+				},
+				release: &namespacedv1beta1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-release",
+						Namespace: testNamespace,
+					},
+					Spec: namespacedv1beta1.ReleaseSpec{
+						ForProvider: namespacedv1beta1.ReleaseParameters{
+							Chart: namespacedv1beta1.ChartSpec{
+								Git: &namespacedv1beta1.GitChartSource{
+									URL:       "https://git.example.com/charts.git",
+									SecretRef: &xpv2.LocalSecretReference{Name: testSecretName},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{
+					Username: testUsername,
+					Password: testPassword,
+				},
+			},
+		},
 		"UsernamePasswordSecret": {
 			args: args{
 				kube: &test.MockClient{
@@ -315,6 +353,43 @@ func TestResolveCluster(t *testing.T) {
 				err:   nil,
 			},
 		},
+		"GitSecret": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok && key.Name == testSecretName {
+							s.Data = map[string][]byte{
+								"username": []byte(testUsername),
+								"password": []byte(testPassword),
+							}
+							return nil
+						}
+						return errBoom
+					},
+				},
+				release: &clusterv1beta1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-release",
+					},
+					Spec: clusterv1beta1.ReleaseSpec{
+						ForProvider: clusterv1beta1.ReleaseParameters{
+							Chart: clusterv1beta1.ChartSpec{
+								Git: &clusterv1beta1.GitChartSource{
+									URL:       "https://git.example.com/charts.git",
+									SecretRef: &xpv2.SecretReference{Name: testSecretName, Namespace: testNamespace},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{
+					Username: testUsername,
+					Password: testPassword,
+				},
+			},
+		},
 		"UsernamePasswordSecret": {
 			args: args{
 				kube: &test.MockClient{
@@ -402,3 +477,190 @@ func TestResolveCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveNamespacedDependency(t *testing.T) {
+	secret := func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		if s, ok := obj.(*corev1.Secret); ok && key.Name == testSecretName && key.Namespace == testNamespace {
+			s.Data = map[string][]byte{
+				"username": []byte(testUsername),
+				"password": []byte(testPassword),
+			}
+			return nil
+		}
+		return errBoom
+	}
+	release := &namespacedv1beta1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-release",
+			Namespace: testNamespace,
+		},
+		Spec: namespacedv1beta1.ReleaseSpec{
+			ForProvider: namespacedv1beta1.ReleaseParameters{
+				Chart: namespacedv1beta1.ChartSpec{
+					Repository: "oci://charts.example.com/charts",
+					Name:       testChartName,
+					DependencyPullSecrets: []namespacedv1beta1.DependencyPullSecret{{
+						Host:      "Registry.example.com",
+						SecretRef: xpv2.LocalSecretReference{Name: testSecretName},
+					}},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		kube       client.Client
+		repository string
+	}
+	type want struct {
+		creds *helmClient.RepoCreds
+		err   error
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"SecretOfHost": {
+			args: args{
+				kube:       &test.MockClient{MockGet: secret},
+				repository: testChartRepo,
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{
+					Username: testUsername,
+					Password: testPassword,
+				},
+			},
+		},
+		"OtherHostUsesDefaultKeychain": {
+			args: args{
+				kube:       &test.MockClient{MockGet: secret},
+				repository: "oci://other.example.com/charts",
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{},
+			},
+		},
+		"SecretGetError": {
+			args: args{
+				kube:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				repository: "https://registry.example.com/charts",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToGetSecret),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resolver := NewResolver(tc.args.kube)
+			got, err := resolver.ResolveNamespacedDependency(context.Background(), release, tc.args.repository)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ResolveNamespacedDependency() error: -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("ResolveNamespacedDependency() creds: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveClusterDependency(t *testing.T) {
+	secret := func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		if s, ok := obj.(*corev1.Secret); ok && key.Name == testSecretName && key.Namespace == testNamespace {
+			s.Data = map[string][]byte{
+				"username": []byte(testUsername),
+				"password": []byte(testPassword),
+			}
+			return nil
+		}
+		return errBoom
+	}
+	release := func(ref xpv2.SecretReference) *clusterv1beta1.Release {
+		return &clusterv1beta1.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-release",
+			},
+			Spec: clusterv1beta1.ReleaseSpec{
+				ForProvider: clusterv1beta1.ReleaseParameters{
+					Chart: clusterv1beta1.ChartSpec{
+						Repository: "oci://charts.example.com/charts",
+						Name:       testChartName,
+						DependencyPullSecrets: []clusterv1beta1.DependencyPullSecret{{
+							Host:      "registry.example.com",
+							SecretRef: ref,
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		kube       client.Client
+		release    *clusterv1beta1.Release
+		repository string
+	}
+	type want struct {
+		creds *helmClient.RepoCreds
+		err   error
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"SecretOfHost": {
+			args: args{
+				kube:       &test.MockClient{MockGet: secret},
+				release:    release(xpv2.SecretReference{Name: testSecretName, Namespace: testNamespace}),
+				repository: testChartRepo,
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{
+					Username: testUsername,
+					Password: testPassword,
+				},
+			},
+		},
+		"OtherHostUsesDefaultKeychain": {
+			args: args{
+				kube:       &test.MockClient{MockGet: secret},
+				release:    release(xpv2.SecretReference{Name: testSecretName, Namespace: testNamespace}),
+				repository: "oci://other.example.com/charts",
+			},
+			want: want{
+				creds: &helmClient.RepoCreds{},
+			},
+		},
+		"MissingNamespace": {
+			args: args{
+				kube:       &test.MockClient{MockGet: secret},
+				release:    release(xpv2.SecretReference{Name: testSecretName}),
+				repository: testChartRepo,
+			},
+			want: want{
+				err: errors.New("namespace required in dependency pull secret for cluster-scoped Release"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resolver := NewResolver(tc.args.kube)
+			got, err := resolver.ResolveClusterDependency(context.Background(), tc.args.release, tc.args.repository)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ResolveClusterDependency() error: -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("ResolveClusterDependency() creds: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
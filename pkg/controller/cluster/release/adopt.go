@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+const (
+	errFailedToDiffAdoptedRelease = "failed to compare existing helm release to spec"
+	errFailedToMarshalValues      = "failed to marshal values of existing helm release"
+	errAdoptionDiffTmpl           = "existing helm release differs from spec in %s, update the spec or set spec.forProvider.adopt.acceptDiff to take it over"
+)
+
+// lateInitializeAllowed returns true if the management policies of the
+// Release allow late-initializing its spec.
+func lateInitializeAllowed(cr *v1beta1.Release) bool {
+	mp := sets.New[xpv2.ManagementAction](cr.Spec.ManagementPolicies...)
+	return len(mp) == 0 || mp.HasAny(xpv2.ManagementActionLateInitialize, xpv2.ManagementActionAll)
+}
+
+func adoptEnabled(cr *v1beta1.Release) bool {
+	return cr.Spec.ForProvider.Adopt != nil && cr.Spec.ForProvider.Adopt.Enabled
+}
+
+func lateInitializeValues(cr *v1beta1.Release) bool {
+	return cr.Spec.ForProvider.Adopt != nil && cr.Spec.ForProvider.Adopt.LateInitializeValues
+}
+
+// adopting returns true if the Release is yet to take over an existing helm
+// release.
+func adopting(cr *v1beta1.Release) bool {
+	return adoptEnabled(cr) && (cr.Status.Adoption == nil || !cr.Status.Adoption.Adopted)
+}
+
+// lateInitializeFromRelease late-initializes the chart and values of the spec
+// from an existing helm release. It returns true if the spec was changed.
+func lateInitializeFromRelease(cr *v1beta1.Release, rel *release.Release) (bool, error) {
+	if !lateInitializeAllowed(cr) {
+		return false, nil
+	}
+
+	in := &cr.Spec.ForProvider
+	li := false
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		if in.Chart.Name == "" {
+			in.Chart.Name = rel.Chart.Metadata.Name
+			li = true
+		}
+		// As on install, the version is not late-initialized if a digest is
+		// specified, or the chart is not versioned by a repository.
+		if in.Chart.Version == "" && in.Chart.Digest == "" && in.Chart.Git == nil && !fromContent(in.Chart) {
+			in.Chart.Version = rel.Chart.Metadata.Version
+			li = true
+		}
+	}
+
+	// rel.Config holds the values supplied by the user, not the merged chart
+	// values. They are only late-initialized if no values are specified at
+	// all, as merging them with partially specified values is ambiguous. They
+	// may include secrets, so they are only copied into the spec on request.
+	if lateInitializeValues(cr) && len(in.Values.Raw) == 0 && len(in.ValuesFrom) == 0 && len(in.Set) == 0 && len(rel.Config) > 0 {
+		raw, err := json.Marshal(rel.Config)
+		if err != nil {
+			return false, errors.Wrap(err, errFailedToMarshalValues)
+		}
+		in.Values = runtime.RawExtension{Raw: raw}
+		li = true
+	}
+
+	return li, nil
+}
+
+// adoptionDiff lists the fields in which an existing helm release differs
+// from the spec of the Release adopting it.
+func adoptionDiff(ctx context.Context, kube client.Client, cr *v1beta1.Release, rel *release.Release) ([]string, error) {
+	in := cr.Spec.ForProvider
+
+	var diff []string
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		if in.Chart.Name != "" && in.Chart.Name != rel.Chart.Metadata.Name {
+			diff = append(diff, "chart.name")
+		}
+		if in.Chart.Version != "" && in.Chart.Version != devel && in.Chart.Version != rel.Chart.Metadata.Version {
+			diff = append(diff, "chart.version")
+		}
+	}
+
+	desired, err := composeValuesFromSpec(ctx, kube, in.ValuesSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToComposeValues)
+	}
+
+	return append(diff, valuesDiff("values", normalizeConfig(desired), normalizeConfig(rel.Config))...), nil
+}
+
+// valuesDiff returns the sorted paths of the leaves that differ between the
+// desired and observed values.
+func valuesDiff(path string, desired, observed map[string]interface{}) []string {
+	keys := sets.KeySet(desired).Union(sets.KeySet(observed))
+
+	var diff []string
+	for _, k := range sets.List(keys) {
+		p := path + "." + k
+		d, dok := desired[k]
+		o, ook := observed[k]
+		dm, dIsMap := d.(map[string]interface{})
+		om, oIsMap := o.(map[string]interface{})
+		if dIsMap && oIsMap {
+			diff = append(diff, valuesDiff(p, dm, om)...)
+			continue
+		}
+		if !dok || !ook || !equality.Semantic.DeepEqual(d, o) {
+			diff = append(diff, p)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	defaultChartArchiveKey = "chart.tgz"
+
+	errChartArchiveKeyMissingTmpl = "key %q missing in chart archive source"
+	errFailedToDecodeChartArchive = "failed to decode chart archive"
+	errFailedToGetChartContent    = "failed to get chart content"
+)
+
+// gzipMagic are the first bytes of a gzipped file, e.g. a packaged chart.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func withChartArchive(ctx context.Context, kube client.Client, cr *v1beta1.Release) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.ChartArchive = func() ([]byte, error) {
+			return chartArchive(ctx, kube, cr.Spec.ForProvider.Chart)
+		}
+	}
+}
+
+// fromContent returns true if a chart is given by its content rather than
+// pulled from a repository, i.e. from a ConfigMap, a Secret or inline.
+func fromContent(c v1beta1.ChartSpec) bool {
+	return c.FromConfigMap != nil || c.FromSecret != nil || len(c.Inline) > 0
+}
+
+// chartArchive returns the packaged chart in the ConfigMap or Secret of a
+// chart spec, or nil if it has neither.
+func chartArchive(ctx context.Context, kube client.Client, c v1beta1.ChartSpec) ([]byte, error) {
+	switch {
+	case c.FromConfigMap != nil:
+		s := c.FromConfigMap
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, cm); err != nil {
+			return nil, errors.Wrapf(err, errFailedToGetConfigMap, s.Namespace)
+		}
+		key := chartArchiveKey(s.Key)
+		if b, ok := cm.BinaryData[key]; ok {
+			return b, nil
+		}
+		d, ok := cm.Data[key]
+		if !ok {
+			return nil, errors.Errorf(errChartArchiveKeyMissingTmpl, key)
+		}
+		return decodeChartArchive([]byte(d))
+	case c.FromSecret != nil:
+		s := c.FromSecret
+		d, err := getSecretData(ctx, kube, types.NamespacedName{Namespace: s.Namespace, Name: s.Name})
+		if err != nil {
+			return nil, err
+		}
+		key := chartArchiveKey(s.Key)
+		b, ok := d[key]
+		if !ok {
+			return nil, errors.Errorf(errChartArchiveKeyMissingTmpl, key)
+		}
+		return decodeChartArchive(b)
+	}
+	return nil, nil
+}
+
+// contentDigest returns the digest of the content of a chart given by its
+// content, or an empty string if it is pulled from a repository.
+func contentDigest(ctx context.Context, kube client.Client, c v1beta1.ChartSpec) (string, error) {
+	if len(c.Inline) > 0 {
+		return helmClient.InlineDigest(c.Inline), nil
+	}
+	b, err := chartArchive(ctx, kube, c)
+	if err != nil || b == nil {
+		return "", err
+	}
+	return helmClient.ArchiveDigest(b), nil
+}
+
+func chartArchiveKey(key string) string {
+	if key == "" {
+		return defaultChartArchiveKey
+	}
+	return key
+}
+
+// decodeChartArchive returns a packaged chart that is stored either as is or
+// base64 encoded.
+func decodeChartArchive(b []byte) ([]byte, error) {
+	if bytes.HasPrefix(b, gzipMagic) {
+		return b, nil
+	}
+	d, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	return d, errors.Wrap(err, errFailedToDecodeChartArchive)
+}
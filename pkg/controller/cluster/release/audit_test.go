@@ -0,0 +1,356 @@
+package release
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/chart/common"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+func Test_writeAuditSnapshot(t *testing.T) {
+	rel := &release.Release{
+		Version:  3,
+		Manifest: "kind: ConfigMap\n",
+		Hooks:    []*release.Hook{{Path: "chart/templates/job.yaml", Manifest: "kind: Job"}},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: testChart, Version: testVersion, AppVersion: "1.0"},
+			Raw:      []*common.File{{Name: "Chart.yaml", Data: []byte("name: " + testChart)}},
+		},
+	}
+	cr := helmRelease(func(r *v1beta1.Release) {
+		r.Spec.ForProvider.Audit = &v1beta1.AuditSpec{Enabled: true}
+		r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"replicas": 2}`)}
+		r.Spec.ForProvider.Set = []v1beta1.SetVal{{
+			Name: "password",
+			ValueFrom: &v1beta1.ValueFromSource{
+				SecretKeyRef: &v1beta1.DataKeySelector{NamespacedName: v1beta1.NamespacedName{Name: testSecretName, Namespace: testNamespace}, Key: "password"},
+			},
+		}}
+	})
+	getSecret := func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"password": []byte("s3cr3t")}}
+		return nil
+	}
+	vals := map[string]interface{}{"replicas": 2, "password": "s3cr3t"}
+	name := auditSnapshotName(cr, rel)
+	snapshot := map[string]string{
+		auditKeyChart: "appVersion: \"1.0\"\n" +
+			"contentSha256: 0c091daebea6a63bef2a56c057a102777054d1941ca64c07501d40fc52ec33f9\n" +
+			"name: " + testChart + "\nversion: " + testVersion + "\n",
+		auditKeyPatches: "null\n",
+		auditKeyValues:  "password: REDACTED\nreplicas: 2\n",
+	}
+	secretSnapshot := map[string][]byte{
+		auditKeyManifest: []byte("kind: ConfigMap\n"),
+		auditKeyHooks:    []byte("---\n# Source: chart/templates/job.yaml\nkind: Job\n"),
+		auditKeyValues:   []byte("password: s3cr3t\nreplicas: 2\n"),
+	}
+	// getExisting gets the Secret values are sourced from, and a snapshot
+	// of the supplied content that already exists.
+	getExisting := func(data map[string]string) test.MockGetFn {
+		return func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				o.Data = data
+			case *corev1.Secret:
+				if key.Name == name {
+					o.Data = secretSnapshot
+					return nil
+				}
+				return getSecret(ctx, key, obj)
+			}
+			return nil
+		}
+	}
+	alreadyExists := func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+		return kerrors.NewAlreadyExists(schema.GroupResource{}, obj.GetName())
+	}
+
+	type args struct {
+		kube client.Client
+	}
+	type want struct {
+		ref     *v1beta1.AuditSnapshotReference
+		data    map[string]string
+		secrets map[string][]byte
+		err     error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Success": {
+			args: args{
+				kube: &test.MockClient{MockGet: getSecret},
+			},
+			want: want{
+				ref:     &v1beta1.AuditSnapshotReference{Name: name, Namespace: defaultProviderNamespace, Revision: 3},
+				data:    snapshot,
+				secrets: secretSnapshot,
+			},
+		},
+		"AlreadyExists": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    getExisting(snapshot),
+					MockCreate: alreadyExists,
+				},
+			},
+			want: want{
+				ref: &v1beta1.AuditSnapshotReference{Name: name, Namespace: defaultProviderNamespace, Revision: 3},
+			},
+		},
+		"AlreadyExistsWithDifferentContent": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    getExisting(map[string]string{auditKeyChart: "name: other\n"}),
+					MockCreate: alreadyExists,
+				},
+			},
+			want: want{
+				err: errors.Errorf(errAuditSnapshotDiffersTmpl, defaultProviderNamespace, name),
+			},
+		},
+		"FailedToComposeRedactedValues": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errors.Wrapf(errBoom, errFailedToGetSecret, testNamespace),
+					errFailedToGetDataFromSecretRef), errFailedToGetValueFromSource), errFailedToComposeRedactedValues),
+			},
+		},
+		"FailedToCreateConfigMap": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    getSecret,
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToCreateAuditConfigMap),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var data map[string]string
+			var secrets map[string][]byte
+			kube := tc.args.kube.(*test.MockClient)
+			if kube.MockCreate == nil {
+				kube.MockCreate = func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+					switch o := obj.(type) {
+					case *corev1.ConfigMap:
+						data = o.Data
+					case *corev1.Secret:
+						secrets = o.Data
+					}
+					return nil
+				}
+			}
+			ref, err := writeAuditSnapshot(context.Background(), kube, cr, rel, vals, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("writeAuditSnapshot(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, ref); diff != "" {
+				t.Errorf("writeAuditSnapshot(...): -want reference, +got reference: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.data, data); diff != "" {
+				t.Errorf("writeAuditSnapshot(...): -want configmap data, +got configmap data: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, secrets); diff != "" {
+				t.Errorf("writeAuditSnapshot(...): -want secret data, +got secret data: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_auditSnapshotName(t *testing.T) {
+	deployed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rel := func(firstDeployed time.Time) *release.Release {
+		return &release.Release{Version: 1, Info: &release.Info{FirstDeployed: firstDeployed}}
+	}
+	cr := func(uid types.UID) *v1beta1.Release {
+		return helmRelease(func(r *v1beta1.Release) { r.SetUID(uid) })
+	}
+
+	cases := map[string]struct {
+		reason string
+		a      string
+		b      string
+		same   bool
+	}{
+		"SameInstallation": {
+			reason: "A revision of the same installation by the same Release should have the same snapshot.",
+			a:      auditSnapshotName(cr("a"), rel(deployed)),
+			b:      auditSnapshotName(cr("a"), rel(deployed)),
+			same:   true,
+		},
+		"Reinstalled": {
+			reason: "A revision of a release that was uninstalled and installed again should have a new snapshot.",
+			a:      auditSnapshotName(cr("a"), rel(deployed)),
+			b:      auditSnapshotName(cr("a"), rel(deployed.Add(time.Second))),
+		},
+		"Recreated": {
+			reason: "A revision deployed by a Release recreated with the same name should have a new snapshot.",
+			a:      auditSnapshotName(cr("a"), rel(deployed)),
+			b:      auditSnapshotName(cr("b"), rel(deployed)),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.a == tc.b; got != tc.same {
+				t.Errorf("\n%s\nauditSnapshotName(a) == auditSnapshotName(b): want %t, got %t (%s, %s)", tc.reason, tc.same, got, tc.a, tc.b)
+			}
+		})
+	}
+}
+
+func Test_auditNames(t *testing.T) {
+	long := func(suffix string) *v1beta1.Release {
+		return helmRelease(func(r *v1beta1.Release) {
+			r.SetName(strings.Repeat("a", 250) + suffix)
+			r.SetUID("a")
+		})
+	}
+	rel := &release.Release{Version: 12}
+
+	if n := auditSnapshotName(long("-b"), rel); len(validation.IsDNS1123Subdomain(n)) > 0 {
+		t.Errorf("auditSnapshotName(...): %q is not a valid name: %v", n, validation.IsDNS1123Subdomain(n))
+	}
+	a, b := auditReleaseLabel(long("-b")), auditReleaseLabel(long("-c"))
+	for _, l := range []string{a, b} {
+		if errs := validation.IsValidLabelValue(l); len(errs) > 0 {
+			t.Errorf("auditReleaseLabel(...): %q is not a valid label value: %v", l, errs)
+		}
+	}
+	if a == b {
+		t.Errorf("auditReleaseLabel(...): Releases with long names that only differ at their end should have different labels, got %q", a)
+	}
+	if got := auditReleaseLabel(helmRelease()); got != testReleaseName {
+		t.Errorf("auditReleaseLabel(...): want %q, got %q", testReleaseName, got)
+	}
+}
+
+func Test_pruneAuditSnapshots(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := func(name string, revision int, age time.Duration) corev1.ConfigMap {
+		return corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			Labels:            map[string]string{auditLabelRevision: strconv.Itoa(revision)},
+		}}
+	}
+	list := func(cms ...corev1.ConfigMap) test.MockListFn {
+		return func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			obj.(*corev1.ConfigMapList).Items = cms
+			return nil
+		}
+	}
+
+	type args struct {
+		kube client.Client
+		keep int
+	}
+	type want struct {
+		deleted []string
+		err     error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"WithinLimit": {
+			args: args{
+				kube: &test.MockClient{MockList: list(snapshot("v1", 1, time.Hour))},
+				keep: 1,
+			},
+		},
+		"PruneOldest": {
+			args: args{
+				kube: &test.MockClient{MockList: list(
+					snapshot("v2", 2, time.Minute),
+					snapshot("v1", 1, time.Hour),
+					snapshot("reinstalled-v1", 1, 0),
+					snapshot("v3", 3, time.Minute),
+				)},
+				keep: 2,
+			},
+			want: want{
+				deleted: []string{"Secret/v2", "ConfigMap/v2", "Secret/v1", "ConfigMap/v1"},
+			},
+		},
+		"AlreadyDeleted": {
+			args: args{
+				kube: &test.MockClient{
+					MockList:   list(snapshot("v2", 2, 0), snapshot("v1", 1, time.Hour)),
+					MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "v1")),
+				},
+				keep: 1,
+			},
+		},
+		"FailedToList": {
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				keep: 1,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToListAuditSnapshots),
+			},
+		},
+		"FailedToDelete": {
+			args: args{
+				kube: &test.MockClient{
+					MockList:   list(snapshot("v2", 2, 0), snapshot("v1", 1, time.Hour)),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				keep: 1,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToDeleteAuditSnapshot),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			kube := tc.args.kube.(*test.MockClient)
+			if kube.MockDelete == nil {
+				kube.MockDelete = func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+					kind := "ConfigMap"
+					if _, ok := obj.(*corev1.Secret); ok {
+						kind = "Secret"
+					}
+					deleted = append(deleted, kind+"/"+obj.GetName())
+					return nil
+				}
+			}
+			err := pruneAuditSnapshots(context.Background(), kube, helmRelease(), testNamespace, tc.args.keep)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("pruneAuditSnapshots(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("pruneAuditSnapshots(...): -want deleted, +got deleted: %s", diff)
+			}
+		})
+	}
+}
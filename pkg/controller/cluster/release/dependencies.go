@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+// reasonWaitingForDependencies indicates that a Release waits for the
+// Releases it depends on to become available.
+const reasonWaitingForDependencies xpv2.ConditionReason = "WaitingForDependencies"
+
+// dependenciesPollInterval is how often a Release waiting for its
+// dependencies checks on them again, should a change of them be missed.
+const dependenciesPollInterval = 30 * time.Second
+
+const (
+	errFailedToCheckDependencies = "failed to check dependencies"
+	errFailedToGetDependencyTmpl = "cannot get dependency %s"
+	errDependencyCycleTmpl       = "dependency cycle detected: %s"
+	msgWaitingForDependencies    = "waiting for dependencies to become available: %s"
+)
+
+// waitingForDependencies returns a condition that indicates the Release waits
+// for the supplied Releases it depends on to become available.
+func waitingForDependencies(pending []string) xpv2.Condition {
+	return xpv2.Condition{
+		Type:               xpv2.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonWaitingForDependencies,
+		Message:            fmt.Sprintf(msgWaitingForDependencies, strings.Join(pending, ", ")),
+	}
+}
+
+// waitForDependencies returns the Releases the Release depends on that are not
+// available yet, and marks the Release as waiting for them.
+func waitForDependencies(ctx context.Context, kube client.Client, cr *v1beta1.Release) ([]string, error) {
+	if len(cr.Spec.ForProvider.DependsOn) == 0 {
+		return nil, nil
+	}
+	if err := checkDependencyCycle(ctx, kube, cr); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, d := range cr.Spec.ForProvider.DependsOn {
+		key := dependencyKey(cr, d)
+		dep := &v1beta1.Release{}
+		if err := kube.Get(ctx, key, dep); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, errFailedToGetDependencyTmpl, key.Name)
+			}
+			pending = append(pending, key.Name)
+			continue
+		}
+		if !available(dep) {
+			pending = append(pending, key.Name)
+		}
+	}
+	if len(pending) > 0 {
+		cr.Status.SetConditions(waitingForDependencies(pending))
+	}
+	return pending, nil
+}
+
+// dependentsOf returns a function that maps a Release to the Releases that
+// depend on it, so that they stop waiting as soon as it becomes available.
+func dependentsOf(kube client.Client, log logging.Logger) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1beta1.ReleaseList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Debug("Cannot list Releases", "error", err)
+			return nil
+		}
+
+		key := types.NamespacedName{Name: o.GetName()}
+		var reqs []reconcile.Request
+		for i := range l.Items {
+			r := &l.Items[i]
+			for _, d := range r.Spec.ForProvider.DependsOn {
+				if dependencyKey(r, d) == key {
+					reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: r.GetName()}})
+					break
+				}
+			}
+		}
+		return reqs
+	}
+}
+
+// available returns true if the supplied Release is available at its current
+// generation.
+func available(r *v1beta1.Release) bool {
+	c := r.GetCondition(xpv2.TypeReady)
+	if c.Status != corev1.ConditionTrue || c.Reason != xpv2.ReasonAvailable {
+		return false
+	}
+	return c.ObservedGeneration == 0 || c.ObservedGeneration == r.GetGeneration()
+}
+
+// checkDependencyCycle returns an error if the dependencies of the Release
+// lead back to it.
+func checkDependencyCycle(ctx context.Context, kube client.Client, cr *v1beta1.Release) error {
+	self := types.NamespacedName{Name: cr.GetName()}
+	visited := sets.New[types.NamespacedName]()
+
+	var visit func(r *v1beta1.Release, path []string) error
+	visit = func(r *v1beta1.Release, path []string) error {
+		for _, d := range r.Spec.ForProvider.DependsOn {
+			key := dependencyKey(r, d)
+			p := append(append([]string{}, path...), key.Name)
+			if key == self {
+				return errors.Errorf(errDependencyCycleTmpl, strings.Join(p, " -> "))
+			}
+			if visited.Has(key) {
+				continue
+			}
+			visited.Insert(key)
+
+			dep := &v1beta1.Release{}
+			if err := kube.Get(ctx, key, dep); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return errors.Wrapf(err, errFailedToGetDependencyTmpl, key.Name)
+			}
+			if err := visit(dep, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(cr, []string{self.Name})
+}
+
+func dependencyKey(_ *v1beta1.Release, d v1beta1.ReleaseReference) types.NamespacedName {
+	return types.NamespacedName{Name: d.Name}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+// typeDeployed is the type of the condition that reports the outcome of the
+// last Helm operation on a Release. Failures are classified by its reason,
+// e.g. RenderFailed or AuthFailed, if they are of a known class.
+const typeDeployed xpv2.ConditionType = "Deployed"
+
+// Reasons of the events of the Helm lifecycle of a Release.
+const (
+	reasonValuesComposed     event.Reason = "ValuesComposed"
+	reasonChartPulled        event.Reason = "ChartPulled"
+	reasonInstallStarted     event.Reason = "InstallStarted"
+	reasonInstallSucceeded   event.Reason = "InstallSucceeded"
+	reasonInstallFailed      event.Reason = "InstallFailed"
+	reasonUpgradeStarted     event.Reason = "UpgradeStarted"
+	reasonUpgradeSucceeded   event.Reason = "UpgradeSucceeded"
+	reasonUpgradeFailed      event.Reason = "UpgradeFailed"
+	reasonRollbackTriggered  event.Reason = "RollbackTriggered"
+	reasonRollbackSucceeded  event.Reason = "RollbackSucceeded"
+	reasonRollbackFailed     event.Reason = "RollbackFailed"
+	reasonUninstallStarted   event.Reason = "UninstallStarted"
+	reasonUninstallSucceeded event.Reason = "UninstallSucceeded"
+	reasonUninstallFailed    event.Reason = "UninstallFailed"
+	reasonTestStarted        event.Reason = "TestStarted"
+	reasonTestSucceeded      event.Reason = "TestSucceeded"
+	reasonTestFailed         event.Reason = "TestFailed"
+
+	reasonAuditSnapshotFailed event.Reason = "AuditSnapshotFailed"
+)
+
+// An operation is a Helm operation on a Release, as reported by its events
+// and, unless it uninstalls or tests the Release, its Deployed condition.
+type operation struct {
+	name      string
+	started   event.Reason
+	succeeded event.Reason
+	failed    event.Reason
+}
+
+var (
+	opInstall   = operation{name: "install", started: reasonInstallStarted, succeeded: reasonInstallSucceeded, failed: reasonInstallFailed}
+	opUpgrade   = operation{name: "upgrade", started: reasonUpgradeStarted, succeeded: reasonUpgradeSucceeded, failed: reasonUpgradeFailed}
+	opRollback  = operation{name: "rollback", started: reasonRollbackTriggered, succeeded: reasonRollbackSucceeded, failed: reasonRollbackFailed}
+	opUninstall = operation{name: "uninstall", started: reasonUninstallStarted, succeeded: reasonUninstallSucceeded, failed: reasonUninstallFailed}
+	opTest      = operation{name: "test", started: reasonTestStarted, succeeded: reasonTestSucceeded, failed: reasonTestFailed}
+)
+
+// failureReason returns the reason of a failed operation, i.e. the class of
+// the failure if it is known.
+func failureReason(op operation, err error) event.Reason {
+	if f := helmClient.ClassifyFailure(err); f != helmClient.FailureUnknown {
+		return event.Reason(f)
+	}
+	return op.failed
+}
+
+// deployed returns a condition that indicates the supplied operation on the
+// Release succeeded, or why it failed.
+func deployed(op operation, err error) xpv2.Condition {
+	c := xpv2.Condition{
+		Type:               typeDeployed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv2.ConditionReason(op.succeeded),
+	}
+	if err != nil {
+		c.Status = corev1.ConditionFalse
+		c.Reason = xpv2.ConditionReason(failureReason(op, err))
+		c.Message = err.Error()
+	}
+	return c
+}
+
+// chartMetadata returns the name and version of a chart, if known.
+func chartMetadata(c *chart.Chart) (name, version string) {
+	if c == nil || c.Metadata == nil {
+		return "", ""
+	}
+	return c.Metadata.Name, c.Metadata.Version
+}
+
+// event records an event of the Release, if a recorder is configured.
+func (e *helmExternal) event(cr *v1beta1.Release, ev event.Event) {
+	if e.record != nil {
+		e.record.Event(cr, ev)
+	}
+}
+
+// started records that the supplied operation on the Release started.
+func (e *helmExternal) started(cr *v1beta1.Release, op operation, chart, version string) {
+	msg := fmt.Sprintf("Started %s of release %s", op.name, meta.GetExternalName(cr))
+	if chart != "" {
+		msg = fmt.Sprintf("Started %s of release %s with chart %s version %s", op.name, meta.GetExternalName(cr), chart, version)
+	}
+	e.event(cr, event.Normal(op.started, msg))
+}
+
+// finished records the outcome of the supplied operation on the Release, and
+// returns the supplied error.
+func (e *helmExternal) finished(cr *v1beta1.Release, op operation, err error) error {
+	if op != opUninstall && op != opTest {
+		cr.Status.SetConditions(deployed(op, err))
+	}
+	if err != nil {
+		e.event(cr, event.Warning(failureReason(op, err), err))
+		return err
+	}
+	e.event(cr, event.Normal(op.succeeded, fmt.Sprintf("Finished %s of release %s", op.name, meta.GetExternalName(cr))))
+	return nil
+}
@@ -0,0 +1,88 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestFinished(t *testing.T) {
+	errBoom := errors.New("boom")
+	errAuth := errors.New("failed to pull chart: unauthorized: authentication required")
+
+	type want struct {
+		err       error
+		condition xpv2.Condition
+		event     event.Event
+	}
+	cases := map[string]struct {
+		op   operation
+		err  error
+		want want
+	}{
+		"InstallSucceeded": {
+			op: opInstall,
+			want: want{
+				condition: xpv2.Condition{Type: typeDeployed, Status: corev1.ConditionTrue, Reason: xpv2.ConditionReason(reasonInstallSucceeded)},
+				event:     event.Normal(reasonInstallSucceeded, "Finished install of release "+testReleaseName),
+			},
+		},
+		"UpgradeFailed": {
+			op:  opUpgrade,
+			err: errBoom,
+			want: want{
+				err:       errBoom,
+				condition: xpv2.Condition{Type: typeDeployed, Status: corev1.ConditionFalse, Reason: xpv2.ConditionReason(reasonUpgradeFailed), Message: errBoom.Error()},
+				event:     event.Warning(reasonUpgradeFailed, errBoom),
+			},
+		},
+		"AuthFailed": {
+			op:  opInstall,
+			err: errAuth,
+			want: want{
+				err:       errAuth,
+				condition: xpv2.Condition{Type: typeDeployed, Status: corev1.ConditionFalse, Reason: "AuthFailed", Message: errAuth.Error()},
+				event:     event.Warning("AuthFailed", errAuth),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			e := &helmExternal{record: r}
+			cr := helmRelease()
+			meta.SetExternalName(cr, testReleaseName)
+
+			err := e.finished(cr, tc.op, tc.err)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("finished(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.condition, cr.GetCondition(typeDeployed), cmpopts.IgnoreFields(xpv2.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("finished(...): -want condition, +got condition: %s", diff)
+			}
+			if diff := cmp.Diff([]event.Event{tc.want.event}, r.events); diff != "" {
+				t.Errorf("finished(...): -want events, +got events: %s", diff)
+			}
+		})
+	}
+}
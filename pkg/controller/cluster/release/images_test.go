@@ -0,0 +1,76 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ktypes "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+const testImageDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func Test_kustomizeImages(t *testing.T) {
+	cases := map[string]struct {
+		in   []v1beta1.Image
+		want []ktypes.Image
+	}{
+		"Nil": {
+			in:   nil,
+			want: nil,
+		},
+		"Converted": {
+			in: []v1beta1.Image{
+				{
+					Name:    "nginx",
+					NewName: "mirror.example.com/nginx",
+					Digest:  testImageDigest,
+				},
+			},
+			want: []ktypes.Image{
+				{
+					Name:    "nginx",
+					NewName: "mirror.example.com/nginx",
+					Digest:  testImageDigest,
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := kustomizeImages(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("kustomizeImages(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_imagesSha(t *testing.T) {
+	img := v1beta1.Image{
+		Name:   "nginx",
+		Digest: testImageDigest,
+	}
+
+	empty, err := imagesSha(nil)
+	if err != nil {
+		t.Fatalf("imagesSha(nil): unexpected error: %s", err)
+	}
+	if empty != "" {
+		t.Errorf("imagesSha(nil): want empty sha, got %q", empty)
+	}
+
+	a, err := imagesSha([]v1beta1.Image{img})
+	if err != nil {
+		t.Fatalf("imagesSha(...): unexpected error: %s", err)
+	}
+	img.NewTag = "1.25"
+	b, err := imagesSha([]v1beta1.Image{img})
+	if err != nil {
+		t.Fatalf("imagesSha(...): unexpected error: %s", err)
+	}
+	if a == b {
+		t.Errorf("imagesSha(...): want different sha for different images, got %q for both", a)
+	}
+}
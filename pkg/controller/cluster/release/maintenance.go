@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/maintenance"
+)
+
+// annotationIgnoreMaintenanceWindows allows upgrading a Release outside of its
+// maintenance windows if set to "true".
+const annotationIgnoreMaintenanceWindows = "helm.crossplane.io/ignore-maintenance-windows"
+
+// reasonUpgradePending indicates that an upgrade of a Release is deferred to
+// its next maintenance window.
+const reasonUpgradePending xpv2.ConditionReason = "UpgradePending"
+
+const (
+	errInvalidMaintenanceWindowTmpl = "invalid maintenance window %d"
+	msgUpgradePendingTmpl           = "upgrade deferred until the next maintenance window starts at %s"
+	msgUpgradePendingNoWindow       = "upgrade deferred, none of the maintenance windows ever starts"
+)
+
+// upgradePending returns a condition that indicates an upgrade of the Release
+// is deferred until the supplied start of its next maintenance window.
+func upgradePending(next time.Time) xpv2.Condition {
+	msg := msgUpgradePendingNoWindow
+	if !next.IsZero() {
+		msg = fmt.Sprintf(msgUpgradePendingTmpl, next.UTC().Format(time.RFC3339))
+	}
+	return xpv2.Condition{
+		Type:               xpv2.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonUpgradePending,
+		Message:            msg,
+	}
+}
+
+func maintenanceWindows(cr *v1beta1.Release) ([]*maintenance.Window, error) {
+	ws := make([]*maintenance.Window, 0, len(cr.Spec.ForProvider.MaintenanceWindows))
+	for i, mw := range cr.Spec.ForProvider.MaintenanceWindows {
+		w, err := maintenance.NewWindow(mw.Schedule, mw.Duration.Duration, mw.TimeZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, errInvalidMaintenanceWindowTmpl, i)
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+// deferUpgrade returns true, and marks the Release as pending an upgrade, if
+// the supplied time is outside of all maintenance windows of the Release.
+func deferUpgrade(cr *v1beta1.Release, now time.Time) (bool, error) {
+	if len(cr.Spec.ForProvider.MaintenanceWindows) == 0 || cr.GetAnnotations()[annotationIgnoreMaintenanceWindows] == "true" {
+		return false, nil
+	}
+	ws, err := maintenanceWindows(cr)
+	if err != nil {
+		return false, err
+	}
+	if maintenance.Active(ws, now) {
+		return false, nil
+	}
+	cr.Status.SetConditions(upgradePending(maintenance.NextStart(ws, now)))
+	return true, nil
+}
+
+// pollIntervalHook schedules the next reconcile of a Release with a pending
+// upgrade at the start of its next maintenance window, and of a throttled
+// Release or a Release waiting for its dependencies shortly, if that is
+// earlier than the poll interval.
+func pollIntervalHook(mg resource.Managed, pollInterval time.Duration) time.Duration {
+	cr, ok := mg.(*v1beta1.Release)
+	if !ok {
+		return pollInterval
+	}
+	switch cr.GetCondition(xpv2.TypeReady).Reason {
+	case reasonThrottled:
+		return min(pollInterval, throttledPollInterval)
+	case reasonWaitingForDependencies:
+		return min(pollInterval, dependenciesPollInterval)
+	case reasonUpgradePending:
+	default:
+		return pollInterval
+	}
+	ws, err := maintenanceWindows(cr)
+	if err != nil {
+		return pollInterval
+	}
+	next := maintenance.NextStart(ws, time.Now())
+	if next.IsZero() {
+		return pollInterval
+	}
+	// Reconcile just after the window started.
+	if d := time.Until(next) + time.Second; d < pollInterval {
+		return d
+	}
+	return pollInterval
+}
@@ -0,0 +1,139 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+func withMaintenanceWindow(schedule string, d time.Duration) helmReleaseModifier {
+	return func(r *v1beta1.Release) {
+		r.Spec.ForProvider.MaintenanceWindows = append(r.Spec.ForProvider.MaintenanceWindows, v1beta1.MaintenanceWindow{
+			Schedule: schedule,
+			Duration: metav1.Duration{Duration: d},
+		})
+	}
+}
+
+func Test_deferUpgrade(t *testing.T) {
+	// Saturday 2025-03-15 23:00 UTC.
+	now := time.Date(2025, 3, 15, 23, 0, 0, 0, time.UTC)
+
+	type want struct {
+		pending   bool
+		condition xpv2.Condition
+		err       error
+	}
+	cases := map[string]struct {
+		cr   *v1beta1.Release
+		want want
+	}{
+		"NoWindows": {
+			cr: helmRelease(),
+		},
+		"InWindow": {
+			cr: helmRelease(withMaintenanceWindow("0 22 * * 6", 4*time.Hour)),
+		},
+		"OutsideWindow": {
+			cr: helmRelease(withMaintenanceWindow("0 2 * * 3", 2*time.Hour)),
+			want: want{
+				pending:   true,
+				condition: upgradePending(time.Date(2025, 3, 19, 2, 0, 0, 0, time.UTC)),
+			},
+		},
+		"EarliestOfWindows": {
+			cr: helmRelease(withMaintenanceWindow("0 2 * * 3", 2*time.Hour), withMaintenanceWindow("0 1 * * *", time.Hour)),
+			want: want{
+				pending:   true,
+				condition: upgradePending(time.Date(2025, 3, 16, 1, 0, 0, 0, time.UTC)),
+			},
+		},
+		"IgnoredByAnnotation": {
+			cr: helmRelease(withMaintenanceWindow("0 2 * * 3", 2*time.Hour), func(r *v1beta1.Release) {
+				r.SetAnnotations(map[string]string{annotationIgnoreMaintenanceWindows: "true"})
+			}),
+		},
+		"InvalidWindow": {
+			cr: helmRelease(withMaintenanceWindow("0 2 * *", 2*time.Hour)),
+			want: want{
+				err: errors.Wrapf(errors.New(`invalid schedule "0 2 * *": want five fields: minute hour day-of-month month day-of-week`), errInvalidMaintenanceWindowTmpl, 0),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pending, err := deferUpgrade(tc.cr, now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("deferUpgrade(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.pending, pending); diff != "" {
+				t.Errorf("deferUpgrade(...): -want pending, +got pending:\n%s", diff)
+			}
+			if !tc.want.pending {
+				return
+			}
+			if diff := cmp.Diff(tc.want.condition, tc.cr.GetCondition(xpv2.TypeReady), cmpopts.IgnoreFields(xpv2.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("deferUpgrade(...): -want condition, +got condition:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_pollIntervalHook(t *testing.T) {
+	poll := 10 * time.Minute
+	pending := func(r *v1beta1.Release) {
+		r.Status.SetConditions(upgradePending(time.Time{}))
+	}
+
+	cases := map[string]struct {
+		cr  *v1beta1.Release
+		max time.Duration
+		min time.Duration
+	}{
+		"NotPending": {
+			cr:  helmRelease(withMaintenanceWindow("* * * * *", time.Minute)),
+			min: poll,
+			max: poll,
+		},
+		"WindowStartsSoon": {
+			cr:  helmRelease(withMaintenanceWindow("* * * * *", time.Minute), pending),
+			min: time.Second,
+			max: time.Minute + time.Second,
+		},
+		"WindowStartsLater": {
+			cr:  helmRelease(withMaintenanceWindow("0 0 1 1 *", time.Minute), pending),
+			min: poll,
+			max: poll,
+		},
+		"Throttled": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Status.SetConditions(throttled("default", 1))
+			}),
+			min: throttledPollInterval,
+			max: throttledPollInterval,
+		},
+		"WaitingForDependencies": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Status.SetConditions(waitingForDependencies([]string{"cert-manager"}))
+			}),
+			min: dependenciesPollInterval,
+			max: dependenciesPollInterval,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := pollIntervalHook(tc.cr, poll)
+			if got < tc.min || got > tc.max {
+				t.Errorf("pollIntervalHook(...): want between %s and %s, got %s", tc.min, tc.max, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package release
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -18,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
 )
 
 const (
@@ -38,6 +40,8 @@ keyB:
 	}
 )
 
+var testHookRun = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func Test_generateObservation(t *testing.T) {
 	type args struct {
 		in *release.Release
@@ -80,6 +84,70 @@ func Test_generateObservation(t *testing.T) {
 				},
 			},
 		},
+		"TestsSucceeded": {
+			args: args{
+				in: &release.Release{
+					Version: 2,
+					Hooks: []*release.Hook{
+						{Name: "install-job", Events: []release.HookEvent{release.HookPostInstall}, LastRun: release.HookExecution{StartedAt: testHookRun, Phase: release.HookPhaseFailed}},
+						{Name: "test-connection", Events: []release.HookEvent{release.HookTest}, LastRun: release.HookExecution{StartedAt: testHookRun, Phase: release.HookPhaseSucceeded}},
+					},
+				},
+			},
+			want: want{
+				out: v1beta1.ReleaseObservation{
+					Test: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestSucceeded},
+				},
+			},
+		},
+		"TestsFailed": {
+			args: args{
+				in: &release.Release{
+					Version: 2,
+					Hooks: []*release.Hook{
+						{Name: "test-connection", Events: []release.HookEvent{release.HookTest}, LastRun: release.HookExecution{StartedAt: testHookRun, Phase: release.HookPhaseSucceeded}},
+						{Name: "test-auth", Events: []release.HookEvent{release.HookTest}, LastRun: release.HookExecution{StartedAt: testHookRun, Phase: release.HookPhaseFailed}},
+					},
+				},
+			},
+			want: want{
+				out: v1beta1.ReleaseObservation{
+					Test: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestFailed, Message: "test test-auth failed"},
+				},
+			},
+		},
+		"TestsNotRun": {
+			args: args{
+				in: &release.Release{
+					Version: 2,
+					Hooks: []*release.Hook{
+						{Name: "test-connection", Events: []release.HookEvent{release.HookTest}},
+					},
+				},
+			},
+			want: want{
+				out: v1beta1.ReleaseObservation{},
+			},
+		},
+		"SuccessWithImages": {
+			args: args{
+				in: &release.Release{
+					Name: "",
+					Info: &release.Info{
+						Description: testDescription,
+						Status:      common.StatusDeployed,
+					},
+					Manifest: "---\napiVersion: apps/v1\nkind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n      - name: nginx\n        image: nginx:1.14.2\n",
+				},
+			},
+			want: want{
+				out: v1beta1.ReleaseObservation{
+					State:              common.StatusDeployed,
+					ReleaseDescription: testDescription,
+					Images:             []string{"nginx:1.14.2"},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -92,11 +160,45 @@ func Test_generateObservation(t *testing.T) {
 }
 
 func Test_isUpToDate(t *testing.T) {
+	secretValuesSpec := func(redact bool) *v1beta1.ReleaseSpec {
+		return &v1beta1.ReleaseSpec{
+			ForProvider: v1beta1.ReleaseParameters{
+				Chart: v1beta1.ChartSpec{
+					Name:    testChart,
+					Version: testVersion,
+				},
+				ValuesSpec: v1beta1.ValuesSpec{
+					Set: []v1beta1.SetVal{{
+						Name:      "password",
+						ValueFrom: &v1beta1.ValueFromSource{SecretKeyRef: &v1beta1.DataKeySelector{NamespacedName: v1beta1.NamespacedName{Name: testSecretName, Namespace: testNamespace}, Key: "password"}},
+					}},
+				},
+				SecretValues: &v1beta1.SecretValuesSpec{Redact: redact},
+			},
+		}
+	}
+	getPassword := func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"password": []byte("s3cr3t")}}
+		return nil
+	}
+	passwordSha, _ := valuesSha(map[string]interface{}{"password": "s3cr3t"})
+	redactedRelease := &release.Release{
+		Info: &release.Info{},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    testChart,
+				Version: testVersion,
+			},
+		},
+		Config: map[string]interface{}{"password": redactedValue},
+	}
+
 	type args struct {
 		kube     client.Client
 		spec     *v1beta1.ReleaseSpec
 		observed *release.Release
 		status   v1beta1.ReleaseStatus
+		gitHead  string
 	}
 	type want struct {
 		out bool
@@ -472,6 +574,261 @@ func Test_isUpToDate(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NotUpToDate_GitCommitChanged": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name: testChart,
+							Git:  &v1beta1.GitChartSource{URL: "https://git.example.com/charts.git", Commit: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationGitCommit: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
+		"UpToDate_GitRefMatchesDeployed": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name: testChart,
+							Git:  &v1beta1.GitChartSource{URL: "https://git.example.com/charts.git", Ref: "main"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationGitCommit: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", helmClient.AnnotationGitRef: "main"},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+				gitHead: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			},
+			want: want{
+				out: true,
+				err: nil,
+			},
+		},
+		"NotUpToDate_GitRefMoved": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name: testChart,
+							Git:  &v1beta1.GitChartSource{URL: "https://git.example.com/charts.git", Ref: "main"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationGitCommit: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", helmClient.AnnotationGitRef: "main"},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+				gitHead: "cccccccccccccccccccccccccccccccccccccccc",
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
+		"NotUpToDate_GitRefChanged": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name: testChart,
+							Git:  &v1beta1.GitChartSource{URL: "https://git.example.com/charts.git", Ref: "release"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationGitCommit: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", helmClient.AnnotationGitRef: "main"},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+				gitHead: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
+		"NotUpToDate_NotDeployedFromGit": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name: testChart,
+							Git:  &v1beta1.GitChartSource{URL: "https://git.example.com/charts.git"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: nil,
+						},
+					},
+					Config: testReleaseConfig,
+				},
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
+		"UpToDate_InlineContentMatchesDeployed": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name:   testChart,
+							Inline: map[string]string{"Chart.yaml": "v1"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationContentDigest: helmClient.InlineDigest(map[string]string{"Chart.yaml": "v1"})},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+			},
+			want: want{
+				out: true,
+				err: nil,
+			},
+		},
+		"NotUpToDate_InlineContentChanged": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name:   testChart,
+							Inline: map[string]string{"Chart.yaml": "v2"},
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationContentDigest: helmClient.InlineDigest(map[string]string{"Chart.yaml": "v1"})},
+						},
+					},
+					Config: testReleaseConfig,
+				},
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
 		"UpToDate_DigestSpecifiedButNotYetDeployed": {
 			args: args{
 				kube: &test.MockClient{
@@ -623,6 +980,84 @@ func Test_isUpToDate(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NotUpToDate_ImagesChanged": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				spec: &v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{
+							Name:    testChart,
+							Version: testVersion,
+						},
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{
+								Raw: []byte(testReleaseConfigStr),
+							},
+						},
+						Images: []v1beta1.Image{
+							{
+								Name:    "nginx",
+								NewName: "mirror.example.com/nginx",
+							},
+						},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:    testChart,
+							Version: testVersion,
+						},
+					},
+					Config: testReleaseConfig,
+				},
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
+		"UpToDate_RedactedSecretValues": {
+			args: args{
+				kube:     &test.MockClient{MockGet: getPassword},
+				spec:     secretValuesSpec(true),
+				observed: redactedRelease,
+				status:   v1beta1.ReleaseStatus{ValuesSha: passwordSha},
+			},
+			want: want{
+				out: true,
+			},
+		},
+		"NotUpToDate_RedactedSecretValueChanged": {
+			args: args{
+				kube:     &test.MockClient{MockGet: getPassword},
+				spec:     secretValuesSpec(true),
+				observed: redactedRelease,
+				status:   v1beta1.ReleaseStatus{ValuesSha: "outdated"},
+			},
+			want: want{
+				out: false,
+			},
+		},
+		"NotUpToDate_RedactionEnabled": {
+			args: args{
+				kube: &test.MockClient{MockGet: getPassword},
+				spec: secretValuesSpec(true),
+				observed: &release.Release{
+					Info:   redactedRelease.Info,
+					Chart:  redactedRelease.Chart,
+					Config: map[string]interface{}{"password": "s3cr3t"},
+				},
+				status: v1beta1.ReleaseStatus{ValuesSha: passwordSha},
+			},
+			want: want{
+				out: false,
+			},
+		},
 		"Success_Int64VsFloat64_Set": {
 			args: args{
 				kube: &test.MockClient{
@@ -664,7 +1099,7 @@ func Test_isUpToDate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr := isUpToDate(context.Background(), tc.args.kube, tc.args.spec, tc.args.observed, tc.args.status)
+			got, gotErr := isUpToDate(context.Background(), tc.args.kube, tc.args.spec, tc.args.observed, tc.args.status, tc.args.gitHead)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("isUpToDate(...): -want error, +got error: %s", diff)
 			}
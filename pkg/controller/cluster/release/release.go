@@ -18,6 +18,8 @@ package release
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
@@ -28,10 +30,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ktype "sigs.k8s.io/kustomize/api/types"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
@@ -43,6 +48,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/statemetrics"
 	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"go.opentelemetry.io/otel/trace"
 
 	kubeclient "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/client"
 
@@ -50,6 +56,9 @@ import (
 	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/cluster/v1beta1"
 	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
 	"github.com/crossplane-contrib/provider-helm/pkg/clients/registryauth"
+	"github.com/crossplane-contrib/provider-helm/pkg/metrics"
+	"github.com/crossplane-contrib/provider-helm/pkg/throttle"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 const (
@@ -59,12 +68,15 @@ const (
 	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
 	helmNamespaceLabel             = "app.kubernetes.io/managed-by"
 	helmProviderName               = "provider-helm"
+
+	spanReconcile = "Reconcile"
 )
 
 const (
 	errNotRelease                 = "managed resource is not a Release custom resource"
 	errGetProviderConfig          = "cannot get provider config"
 	errNewHelmClient              = "cannot create new Helm client"
+	errNewControlPlaneClient      = "cannot create control plane client"
 	errFailedToGetLastRelease     = "failed to get last helm release"
 	errLastReleaseIsNil           = "last helm release is nil"
 	errFailedToCheckIfUpToDate    = "failed to check if release is up to date"
@@ -72,30 +84,45 @@ const (
 	errFailedToUpgrade            = "failed to upgrade release"
 	errFailedToUninstall          = "failed to uninstall release"
 	errFailedToGetRepoCreds       = "failed to get user name and password from secret reference"
+	errFailedToResolveGitRef      = "failed to resolve git ref of chart"
 	errFailedToComposeValues      = "failed to compose values"
 	errBuildKubeForProviderConfig = "cannot build kube client for provider config"
+	errGetThrottleLimits          = "cannot get limits of the target cluster"
+	errGetProviderConfigVersion   = "cannot get version of provider config"
 	errFailedToTrackUsage         = "cannot track provider config usage"
 	errFailedToLoadPatches        = "failed to load patches"
 	errFailedToUpdatePatchSha     = "failed to update patch sha"
 	errFailedToLateInitialize     = "failed to update chart spec with late-initialized values"
 	errFailedToCreateNamespace    = "failed to create namespace for release"
+	errFailedToWriteAuditSnapshot = "failed to write audit snapshot"
 )
 
 // Setup adds a controller that reconciles Release managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
 	name := managed.ControllerName(v1beta1.ReleaseGroupKind)
 
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, errNewControlPlaneClient)
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	reconcilerOptions := []managed.ReconcilerOption{
 		managed.WithExternalConnector(&connector{
 			client:          mgr.GetClient(),
 			logger:          o.Logger,
 			usage:           resource.NewLegacyProviderConfigUsageTracker(mgr.GetClient(), &helmv1beta1.ProviderConfigUsage{}),
 			clientBuilder:   kubeclient.NewIdentityAwareBuilder(mgr.GetClient()),
+			controlPlane:    cs.CoreV1(),
 			newHelmClientFn: helmClient.NewClient,
+			throttle:        throttle.Targets,
+			connections:     helmClient.NewConnectionCache(),
+			record:          recorder,
 		}),
 		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollIntervalHook(pollIntervalHook),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithTimeout(timeout),
 		managed.WithMetricRecorder(o.MetricOptions.MRMetrics),
 		managed.WithDeterministicExternalName(true),
@@ -121,9 +148,9 @@ func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		For(&v1beta1.Release{}).
+		For(&v1beta1.Release{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1beta1.Release{}, handler.EnqueueRequestsFromMapFunc(dependentsOf(mgr.GetClient(), o.Logger))).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
 		Complete(r)
 }
 
@@ -144,7 +171,35 @@ type connector struct {
 	usage  resource.LegacyTracker
 
 	clientBuilder   kubeclient.Builder
+	controlPlane    corev1client.SecretsGetter
 	newHelmClientFn func(log logging.Logger, config *rest.Config, helmArgs ...helmClient.ArgsApplier) (helmClient.Client, error)
+	throttle        *throttle.Registry
+	connections     *helmClient.ConnectionCache
+	record          event.Recorder
+}
+
+// withConnection makes the Helm client of a Release use the shared connection
+// to its target cluster, which caches the client for the Release.
+func withConnection(conn *helmClient.Connection, cr *v1beta1.Release) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Connection = conn
+		config.ClientKey = string(cr.GetUID())
+	}
+}
+
+func withTrace(span trace.Span) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Trace = span
+	}
+}
+
+func withDependencyCredentials(ctx context.Context, kube client.Client, cr *v1beta1.Release) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		resolver := registryauth.NewResolver(kube)
+		config.DependencyCredentials = func(repository string) (*helmClient.RepoCreds, error) {
+			return resolver.ResolveClusterDependency(ctx, cr, repository)
+		}
+	}
 }
 
 func withRelease(cr *v1beta1.Release) helmClient.ArgsApplier {
@@ -153,6 +208,7 @@ func withRelease(cr *v1beta1.Release) helmClient.ArgsApplier {
 		config.Wait = cr.Spec.ForProvider.Wait
 		config.Timeout = waitTimeout(cr)
 		config.SkipCRDs = cr.Spec.ForProvider.SkipCRDs
+		config.CRDPolicy = string(cr.Spec.ForProvider.CRDPolicy)
 		config.InsecureSkipTLSVerify = cr.Spec.ForProvider.InsecureSkipTLSVerify
 		config.PlainHTTP = cr.Spec.ForProvider.PlainHTTP
 		// Only use TakeOwnership if requested AND not already used
@@ -160,28 +216,101 @@ func withRelease(cr *v1beta1.Release) helmClient.ArgsApplier {
 		config.TakeOwnership = cr.Spec.ForProvider.TakeOwnership && !cr.Status.AtProvider.OwnershipTaken
 		config.MaxHistory = cr.Spec.ForProvider.MaxHistory
 		config.SSAForceConflicts = cr.Spec.ForProvider.SSAForceConflicts
+		config.Images = kustomizeImages(cr.Spec.ForProvider.Images)
+		// Deletion options only apply when the Release is deleted, not when
+		// a failed first install is uninstalled to retry it.
+		if d := cr.Spec.ForProvider.Deletion; d != nil && meta.WasDeleted(cr) {
+			config.KeepKinds = d.KeepKinds
+			config.KeepHistory = d.KeepHistory
+			config.DeletionPropagation = string(d.Cascade)
+		}
 	}
 }
 
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (_ managed.ExternalClient, err error) { //nolint:gocyclo
 	cr, ok := mg.(*v1beta1.Release)
 	if !ok {
 		return nil, errors.New(errNotRelease)
 	}
+
+	// The span of a connected client ends when it is disconnected.
+	ctx, span := tracing.Start(ctx, spanReconcile,
+		tracing.AttrRelease.String(meta.GetExternalName(cr)),
+		tracing.AttrChart.String(cr.Spec.ForProvider.Chart.Name),
+		tracing.AttrVersion.String(cr.Spec.ForProvider.Chart.Version))
+	defer func() {
+		if err != nil {
+			tracing.End(span, err)
+		}
+	}()
 	l := c.logger.WithValues("request", cr.Name)
 
 	l.Debug("Connecting")
 
-	pcSpec, err := helmClient.ResolveProviderConfig(ctx, c.client, c.usage, nil, mg)
+	pcSpec, pc, err := helmClient.ResolveProviderConfigObject(ctx, c.client, c.usage, nil, mg)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to resolve provider config")
 	}
 
-	k, rc, err := c.clientBuilder.KubeForProviderConfig(ctx, *pcSpec)
+	// All Releases of a provider config share its connection. All Releases
+	// targeting the same cluster share its operation slots and rate limiter,
+	// even if they use different provider configs. Their limits are the
+	// strictest limits of these provider configs. A connection is rebuilt
+	// when the limits of its target change, so that it shares the rate
+	// limiter of the target.
+	pcID := string(pc.GetUID())
+	var limits, targetLimits throttle.Limits
+	if c.throttle != nil {
+		if limits, err = providerConfigLimits(c.throttle.Defaults(), pc); err != nil {
+			return nil, errors.Wrap(err, errGetThrottleLimits)
+		}
+		targetLimits = c.throttle.TargetLimits(pcID, limits)
+	}
+	version := ""
+	if c.connections != nil {
+		if version, err = helmClient.ProviderConfigVersion(ctx, c.client, pc, *pcSpec); err != nil {
+			return nil, errors.Wrap(err, errGetProviderConfigVersion)
+		}
+	}
+	conn, err := c.connections.Get(pcID, fmt.Sprintf("%s/%+v", version, targetLimits), func() (*helmClient.Connection, error) {
+		// The connection outlives this reconcile, so it must not be bound to
+		// its context, e.g. when refreshing credentials.
+		k, rc, err := c.clientBuilder.KubeForProviderConfig(context.Background(), *pcSpec)
+		if err != nil {
+			return nil, errors.Wrap(err, errBuildKubeForProviderConfig)
+		}
+		if c.throttle != nil {
+			target := throttle.TargetKey(rc)
+			if rl := c.throttle.RateLimiter(target, c.throttle.SetLimits(target, pcID, limits)); rl != nil {
+				rc = rest.CopyConfig(rc)
+				rc.RateLimiter = rl
+			}
+		}
+		return helmClient.NewConnection(k, rc), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	target := throttle.TargetKey(conn.RESTConfig)
+	if c.throttle != nil {
+		targetLimits = c.throttle.SetLimits(target, pcID, limits)
+	}
+	if conn.RESTConfig != nil {
+		span.SetAttributes(tracing.AttrTarget.String(conn.RESTConfig.Host))
+	}
+	r, err := newRedaction(ctx, c.client, cr)
 	if err != nil {
-		return nil, errors.Wrap(err, errBuildKubeForProviderConfig)
+		return nil, errors.Wrap(err, errFailedToComposeSecretValues)
 	}
-	h, err := c.newHelmClientFn(c.logger, rc, withRelease(cr))
+	key, err := encryptionKey(ctx, c.client, cr)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := sqlConnectionString(ctx, c.client, cr)
+	if err != nil {
+		return nil, err
+	}
+	h, err := c.newHelmClientFn(c.logger, conn.RESTConfig, withRelease(cr), withSecretValues(r, key), withStorage(cr, cs, throttle.TargetKey(conn.RESTConfig), c.controlPlane), withConnection(conn, cr), withTrace(span), withDependencyCredentials(ctx, c.client, cr), withChartArchive(ctx, c.client, cr))
 	if err != nil {
 		return nil, errors.Wrap(err, errNewHelmClient)
 	}
@@ -189,9 +318,16 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	return &helmExternal{
 		logger:    l,
 		localKube: c.client,
-		kube:      k,
+		kube:      conn.Kube,
 		helm:      h,
 		patch:     newPatcher(),
+		redaction: r,
+		throttle:  c.throttle,
+		target:    target,
+		limits:    targetLimits,
+		id:        string(cr.GetUID()),
+		span:      span,
+		record:    c.record,
 	}, nil
 }
 
@@ -201,9 +337,33 @@ type helmExternal struct {
 	kube      client.Client
 	helm      helmClient.Client
 	patch     Patcher
+	redaction *helmClient.Redaction
+	record    event.Recorder
+
+	throttle    *throttle.Registry
+	target      string
+	limits      throttle.Limits
+	id          string
+	releaseSlot func()
+	// queued is true if the Release was queued for a slot in this reconcile.
+	queued bool
+	span   trace.Span
 }
 
 func (e *helmExternal) Disconnect(ctx context.Context) error {
+	switch {
+	case e.releaseSlot != nil:
+		e.releaseSlot()
+		e.releaseSlot = nil
+	case e.throttle != nil && !e.queued:
+		// A Release that no longer tries to install or upgrade gives up its
+		// place in the queue right away.
+		e.throttle.Leave(e.target, e.id)
+	}
+	if e.span != nil {
+		e.span.End()
+		e.span = nil
+	}
 	return nil
 }
 
@@ -217,8 +377,20 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 
 	rel, err := e.helm.GetLastRelease(meta.GetExternalName(cr))
 	if errors.Is(err, driver.ErrReleaseNotFound) {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		// Report the release as existing until its dependencies are
+		// available and it may install against its target cluster, and after
+		// it failed permanently until its spec or sources change.
+		waiting, err := e.dependenciesPending(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		waiting = waiting || e.failedTerminally(ctx, cr) || !e.acquireSlot(cr)
 		return managed.ExternalObservation{
-			ResourceExists: false,
+			ResourceExists:   waiting,
+			ResourceUpToDate: waiting,
 		}, nil
 	}
 
@@ -233,9 +405,17 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 	// Preserve the last-deployed digest from the persisted status so isUpToDate
 	// can detect spec.digest changes. generateObservation reconstructs the
 	// observation from the Helm release, which has no notion of OCI digest.
-	lastDigest := cr.Status.AtProvider.Digest
+	lastDigest, lastTest := cr.Status.AtProvider.Digest, cr.Status.AtProvider.Test
 	cr.Status.AtProvider = generateObservation(rel)
-	cr.Status.AtProvider.Digest = lastDigest
+	if cr.Status.AtProvider.Digest == "" {
+		cr.Status.AtProvider.Digest = lastDigest
+	}
+	// The outcome of tests that ran no test hook is only recorded in the
+	// status.
+	if cr.Status.AtProvider.Test == nil && lastTest != nil && lastTest.Revision == rel.Version {
+		cr.Status.AtProvider.Test = lastTest
+	}
+	recordReleaseInfo(cr, rel)
 
 	// Determining whether the release is up to date may involve reading values
 	// from secrets, configmaps, etc. This will fail if said dependencies have
@@ -243,14 +423,58 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 	// order to delete the release, so if we know we're about to be deleted we
 	// return early to avoid blocking unnecessarily on missing dependencies.
 	if meta.WasDeleted(cr) {
+		// A release uninstalled with its history kept no longer exists.
+		if rel.Info != nil && rel.Info.Status == common.StatusUninstalled {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
 		return managed.ExternalObservation{ResourceExists: true}, nil
 	}
 
-	s, err := isUpToDate(ctx, e.localKube, &cr.Spec, rel, cr.Status)
+	li := false
+	if adopting(cr) {
+		if li, err = lateInitializeFromRelease(cr, rel); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToLateInitialize)
+		}
+		diff, err := adoptionDiff(ctx, e.localKube, cr, rel)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(e.redaction.MaskError(err), errFailedToDiffAdoptedRelease)
+		}
+		cr.Status.Adoption = &v1beta1.AdoptionStatus{Diff: diff}
+		if len(diff) > 0 && !cr.Spec.ForProvider.Adopt.AcceptDiff {
+			// Report the existing release as up to date, so that it is not
+			// upgraded until the diff is resolved or accepted.
+			cr.Status.Synced = false
+			cr.Status.SetConditions(xpv2.Unavailable().WithMessage(fmt.Sprintf(errAdoptionDiffTmpl, strings.Join(diff, ", "))))
+			return managed.ExternalObservation{
+				ResourceExists:          true,
+				ResourceUpToDate:        true,
+				ResourceLateInitialized: li,
+			}, nil
+		}
+		cr.Status.Adoption.Adopted = true
+	}
+
+	head, err := e.gitHead(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFailedToResolveGitRef)
+	}
+
+	s, err := isUpToDate(ctx, e.localKube, &cr.Spec, rel, cr.Status, head)
 	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, errFailedToCheckIfUpToDate)
+		return managed.ExternalObservation{}, errors.Wrap(e.redaction.MaskError(err), errFailedToCheckIfUpToDate)
 	}
 	cr.Status.Synced = s
+
+	// An upgrade outside of the maintenance windows of the Release is
+	// deferred, without waiting for an operation slot of its target. Rolling
+	// back a failed release is not.
+	deferred := false
+	if !s && !shouldRollBack(cr) {
+		if deferred, err = deferUpgrade(cr, time.Now()); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+
 	cd := managed.ConnectionDetails{}
 	if cr.Status.AtProvider.State == common.StatusDeployed && s {
 		cr.Status.Failed = 0
@@ -263,47 +487,93 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 			cr.Status.AtProvider.Digest = cr.Spec.ForProvider.Chart.Digest
 		}
 		cr.Status.SetConditions(xpv2.Available())
-	} else {
+	} else if !deferred {
+		// A pending upgrade is kept as is, so that it is not reported as
+		// changed on every poll.
 		cr.Status.SetConditions(xpv2.Unavailable())
 	}
 
+	upToDate := deferred || cr.Status.Synced && !(shouldRollBack(cr) && !rollBackLimitReached(cr))
+	if !upToDate {
+		if upToDate, err = e.dependenciesPending(ctx, cr); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		upToDate = upToDate || e.failedTerminally(ctx, cr) || !e.acquireSlot(cr)
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  cr.Status.Synced && !(shouldRollBack(cr) && !rollBackLimitReached(cr)),
-		ConnectionDetails: cd,
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: li,
+		ConnectionDetails:       cd,
 	}, nil
 }
 
+// gitHead resolves the ref a chart in Git follows to the commit it points to
+// now, so that a Release is upgraded when its branch moves. It returns an
+// empty commit for charts that are not in Git or are pinned to a commit.
+func (e *helmExternal) gitHead(ctx context.Context, cr *v1beta1.Release) (string, error) {
+	g := cr.Spec.ForProvider.Chart.Git
+	if g == nil || g.Commit != "" {
+		return "", nil
+	}
+	creds, err := registryauth.NewResolver(e.localKube).ResolveCluster(ctx, cr)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToGetRepoCreds)
+	}
+	return helmClient.ResolveGitCommit(helmClient.GitSource{URL: g.URL, Ref: g.Ref, Path: g.Path}, creds, cr.Spec.ForProvider.InsecureSkipTLSVerify)
+}
+
+// dependenciesPending returns true while any of the Releases the Release
+// depends on is not available, so that it is neither installed nor upgraded
+// before them. The Release is marked as waiting for them meanwhile.
+func (e *helmExternal) dependenciesPending(ctx context.Context, cr *v1beta1.Release) (bool, error) {
+	pending, err := waitForDependencies(ctx, e.localKube, cr)
+	if err != nil {
+		return false, errors.Wrap(err, errFailedToCheckDependencies)
+	}
+	return len(pending) > 0, nil
+}
+
 type deployAction func(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
 
-func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, action deployAction) error { //nolint:gocyclo // easier to follow as a unit
+// A deployment is a revision deployed by an install or upgrade, and the
+// values and patches it was deployed with.
+type deployment struct {
+	release *release.Release
+	values  map[string]interface{}
+	patches []ktype.Patch
+}
+
+func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, op operation, action deployAction) (*deployment, error) { //nolint:gocyclo // easier to follow as a unit
+	ctx = trace.ContextWithSpan(ctx, e.span)
+
 	cv, err := composeValuesFromSpec(ctx, e.localKube, cr.Spec.ForProvider.ValuesSpec)
 	if err != nil {
-		return errors.Wrap(err, errFailedToComposeValues)
+		return nil, errors.Wrap(e.redaction.MaskError(err), errFailedToComposeValues)
 	}
+	e.event(cr, event.Normal(reasonValuesComposed, "Composed values of release "+meta.GetExternalName(cr)))
 
 	resolver := registryauth.NewResolver(e.localKube)
 	creds, err := resolver.ResolveCluster(ctx, cr)
 	if err != nil {
-		return errors.Wrap(err, errFailedToGetRepoCreds)
+		return nil, errors.Wrap(err, errFailedToGetRepoCreds)
 	}
 
-	p, err := e.patch.getFromSpec(ctx, e.localKube, cr.Spec.ForProvider.PatchesFrom)
+	p, err := e.patch.getFromSpec(ctx, e.localKube, cr.Spec.ForProvider.PatchesFrom, cr.Spec.ForProvider.Patches)
 	if err != nil {
-		return errors.Wrap(err, errFailedToLoadPatches)
+		return nil, errors.Wrap(err, errFailedToLoadPatches)
 	}
 
 	chart, err := e.helm.PullAndLoadChart(cr, creds)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// Check if LateInitialize is allowed by management policies
-	mp := sets.New[xpv2.ManagementAction](cr.Spec.ManagementPolicies...)
-	shouldLateInit := len(mp) == 0 || mp.HasAny(xpv2.ManagementActionLateInitialize, xpv2.ManagementActionAll)
+	chartName, chartVersion := chartMetadata(chart)
+	e.event(cr, event.Normal(reasonChartPulled, fmt.Sprintf("Pulled chart %s version %s", chartName, chartVersion)))
 
 	needsUpdate := false
-	if shouldLateInit {
+	if lateInitializeAllowed(cr) {
 		if cr.Spec.ForProvider.Chart.Name == "" {
 			cr.Spec.ForProvider.Chart.Name = chart.Metadata.Name
 			needsUpdate = true
@@ -311,7 +581,10 @@ func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, action d
 		// Late-initialize version only when digest is NOT specified
 		// When digest is specified, it's the source of truth and version becomes optional metadata
 		// This prevents spec pollution and GitOps drift in digest-only workflows
-		if cr.Spec.ForProvider.Chart.Version == "" && cr.Spec.ForProvider.Chart.Digest == "" {
+		// The same goes for charts in Git, which are tracked by commit, and
+		// for charts given by their content, which are tracked by its digest
+		chartSpec := cr.Spec.ForProvider.Chart
+		if chartSpec.Version == "" && chartSpec.Digest == "" && chartSpec.Git == nil && !fromContent(chartSpec) {
 			cr.Spec.ForProvider.Chart.Version = chart.Metadata.Version
 			needsUpdate = true
 		}
@@ -321,34 +594,56 @@ func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, action d
 
 	if needsUpdate {
 		if err := e.localKube.Update(ctx, cr); err != nil {
-			return errors.Wrap(err, errFailedToLateInitialize)
+			return nil, errors.Wrap(err, errFailedToLateInitialize)
 		}
 	}
 
+	e.started(cr, op, chartName, chartVersion)
 	rel, err := action(meta.GetExternalName(cr), chart, cv, p)
 
 	if err != nil {
-		return err
+		return nil, e.redaction.MaskError(err)
 	}
 
 	if rel == nil {
-		return errors.New(errLastReleaseIsNil)
+		return nil, errors.New(errLastReleaseIsNil)
 	}
 
 	sha, err := e.patch.shaOf(p)
 	if err != nil {
-		return errors.Wrap(err, errFailedToUpdatePatchSha)
+		return nil, errors.Wrap(err, errFailedToUpdatePatchSha)
 	}
 	cr.Status.PatchesSha = sha
+	isha, err := imagesSha(cr.Spec.ForProvider.Images)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToHashImages)
+	}
+	cr.Status.ImagesSha = isha
+	cr.Status.ValuesSha = ""
+	if redactSecretValues(cr) {
+		vsha, err := valuesSha(cv)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToHashValues)
+		}
+		cr.Status.ValuesSha = vsha
+	}
 	cr.Status.AtProvider = generateObservation(rel)
-	// Store the digest in status for drift detection
-	cr.Status.AtProvider.Digest = cr.Spec.ForProvider.Chart.Digest
+	// Store the digest in status for drift detection, unless it is that of
+	// the content of the chart
+	if cr.Status.AtProvider.Digest == "" {
+		cr.Status.AtProvider.Digest = cr.Spec.ForProvider.Chart.Digest
+	}
 	// Mark ownership as taken if TakeOwnership was used
 	if cr.Spec.ForProvider.TakeOwnership {
 		cr.Status.AtProvider.OwnershipTaken = true
 	}
 
-	return nil
+	// A release deployed by the Release needs no adoption.
+	if adoptEnabled(cr) {
+		cr.Status.Adoption = &v1beta1.AdoptionStatus{Adopted: true}
+	}
+
+	return &deployment{release: rel, values: cv, patches: p}, nil
 }
 
 func (e *helmExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -367,7 +662,13 @@ func (e *helmExternal) Create(ctx context.Context, mg resource.Managed) (managed
 		}
 	}
 
-	return managed.ExternalCreation{}, errors.Wrap(e.deploy(ctx, cr, e.helm.Install), errFailedToInstall)
+	d, err := e.deploy(ctx, cr, opInstall, e.helm.Install)
+	e.recordTerminalFailure(ctx, cr, err)
+	if err := e.finished(cr, opInstall, err); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToInstall)
+	}
+	e.test(cr)
+	return managed.ExternalCreation{}, e.audit(ctx, cr, d)
 }
 
 func (e *helmExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -386,20 +687,59 @@ func (e *helmExternal) Update(ctx context.Context, mg resource.Managed) (managed
 			// We need to uninstall to retry.
 			if cr.Status.AtProvider.Revision == 1 {
 				e.logger.Debug("Uninstalling")
-				return managed.ExternalUpdate{}, e.helm.Uninstall(meta.GetExternalName(cr))
+				e.started(cr, opUninstall, "", "")
+				_, err := e.helm.Uninstall(meta.GetExternalName(cr))
+				return managed.ExternalUpdate{}, e.finished(cr, opUninstall, err)
 			}
 			e.logger.Debug("Rolling back to previous release version")
-			return managed.ExternalUpdate{}, e.helm.Rollback(meta.GetExternalName(cr))
+			e.started(cr, opRollback, "", "")
+			return managed.ExternalUpdate{}, e.finished(cr, opRollback, e.redaction.MaskError(e.helm.Rollback(meta.GetExternalName(cr))))
 		}
 		e.logger.Debug("Reached max rollback retries, will not retry")
 		return managed.ExternalUpdate{}, nil
 	}
 
 	e.logger.Debug("Updating")
-	return managed.ExternalUpdate{}, errors.Wrap(e.deploy(ctx, cr, e.helm.Upgrade), errFailedToUpgrade)
+	d, err := e.deploy(ctx, cr, opUpgrade, e.helm.Upgrade)
+	e.recordTerminalFailure(ctx, cr, err)
+	if err := e.finished(cr, opUpgrade, err); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToUpgrade)
+	}
+	e.test(cr)
+	return managed.ExternalUpdate{}, e.audit(ctx, cr, d)
+}
+
+const msgNoTests = "no test hook ran, the chart has no tests"
+
+// test runs the test hooks of the release if the Release asks for it, and
+// reports their outcome in its status. Failed tests are not returned as an
+// error, as deploying the release again would not make them pass.
+func (e *helmExternal) test(cr *v1beta1.Release) {
+	if !cr.Spec.ForProvider.RunTests {
+		return
+	}
+	e.started(cr, opTest, "", "")
+	rel, err := e.helm.Test(meta.GetExternalName(cr))
+	err = e.finished(cr, opTest, e.redaction.MaskError(err))
+	if rel != nil {
+		cr.Status.AtProvider.Test = testObservation(rel)
+	}
+	if cr.Status.AtProvider.Test != nil {
+		return
+	}
+	// Record the outcome when no test hook ran, e.g. as the chart has none.
+	cr.Status.AtProvider.Test = &v1beta1.ReleaseTestObservation{
+		Revision: cr.Status.AtProvider.Revision,
+		Phase:    v1beta1.TestSucceeded,
+		Message:  msgNoTests,
+	}
+	if err != nil {
+		cr.Status.AtProvider.Test.Phase = v1beta1.TestFailed
+		cr.Status.AtProvider.Test.Message = err.Error()
+	}
 }
 
-func (e *helmExternal) Delete(_ context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+func (e *helmExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1beta1.Release)
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotRelease)
@@ -407,7 +747,35 @@ func (e *helmExternal) Delete(_ context.Context, mg resource.Managed) (managed.E
 
 	e.logger.Debug("Deleting")
 
-	return managed.ExternalDelete{}, errors.Wrap(e.helm.Uninstall(meta.GetExternalName(cr)), errFailedToUninstall)
+	// Refusing to uninstall surfaces as a warning event and a Synced
+	// condition with the reason, and is retried until it is resolved.
+	if cr.Spec.ForProvider.DeletionProtection {
+		return managed.ExternalDelete{}, errors.New(errDeletionProtected)
+	}
+	if len(uninstallSafeguards(cr)) > 0 {
+		rel, err := e.helm.GetLastRelease(meta.GetExternalName(cr))
+		if err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errFailedToGetLastRelease)
+		}
+		if rel == nil {
+			return managed.ExternalDelete{}, errors.New(errLastReleaseIsNil)
+		}
+		if err := checkUninstallSafeguards(ctx, e.kube, cr, rel); err != nil {
+			return managed.ExternalDelete{}, err
+		}
+	}
+
+	e.started(cr, opUninstall, "", "")
+	kept, err := e.helm.Uninstall(meta.GetExternalName(cr))
+	if err := e.finished(cr, opUninstall, err); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errFailedToUninstall)
+	}
+	metrics.DeleteReleaseInfo(v1beta1.ReleaseGroupKind, cr.GetNamespace(), cr.GetName())
+	cr.Status.KeptResources = kept
+	if len(kept) > 0 {
+		e.logger.Info("Kept resources of uninstalled release", "resources", kept)
+	}
+	return managed.ExternalDelete{}, nil
 }
 
 func shouldRollBack(cr *v1beta1.Release) bool {
@@ -0,0 +1,251 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	pvcPhaseBound = "Bound"
+)
+
+var (
+	pvcGVK         = schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}
+	pvcListGVK     = schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaimList"}
+	statefulSetGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+)
+
+const (
+	errDeletionProtected              = "deletion protection is enabled, clear spec.forProvider.deletionProtection to uninstall the release"
+	errUninstallSafeguardTmpl         = "release still owns %s matching uninstall safeguard %d, refusing to uninstall"
+	errInvalidSafeguardSelectorTmpl   = "invalid selector in uninstall safeguard %d"
+	errFailedToCheckSafeguardTmpl     = "cannot check %s against uninstall safeguard %d"
+	errFailedToListCustomResourceTmpl = "cannot list custom resources of %s"
+	errFailedToListClaimsTmpl         = "cannot list persistent volume claims of %s"
+)
+
+func uninstallSafeguards(cr *v1beta1.Release) []v1beta1.UninstallSafeguard {
+	if cr.Spec.ForProvider.Deletion == nil {
+		return nil
+	}
+	return cr.Spec.ForProvider.Deletion.Safeguards
+}
+
+// checkUninstallSafeguards returns an error if the supplied release still owns
+// resources matching one of the uninstall safeguards of the Release. The live
+// resources are checked, as their labels and state may differ from the
+// manifest stored by Helm.
+func checkUninstallSafeguards(ctx context.Context, kube client.Client, cr *v1beta1.Release, rel *release.Release) error {
+	objs, err := releaseObjects(ctx, kube, cr, rel)
+	if err != nil {
+		return err
+	}
+	for i, sg := range uninstallSafeguards(cr) {
+		sel := labels.Everything()
+		if sg.Selector != nil {
+			s, err := metav1.LabelSelectorAsSelector(sg.Selector)
+			if err != nil {
+				return errors.Wrapf(err, errInvalidSafeguardSelectorTmpl, i)
+			}
+			sel = s
+		}
+		for _, o := range objs {
+			if o.GetAPIVersion() != sg.APIVersion || o.GetKind() != sg.Kind {
+				continue
+			}
+			blocks, err := safeguardBlocks(ctx, kube, sg, sel, o, rel.Namespace)
+			if err != nil {
+				return errors.Wrapf(err, errFailedToCheckSafeguardTmpl, objectRef(o), i)
+			}
+			if blocks {
+				return errors.Errorf(errUninstallSafeguardTmpl, objectRef(o), i)
+			}
+		}
+	}
+	return nil
+}
+
+// releaseObjects returns the objects of the supplied release uninstall
+// safeguards are checked against. Besides the objects in its manifest, these
+// are the CRDs in the crds/ directory of its chart, unless they were skipped,
+// and the PersistentVolumeClaims created from the volumeClaimTemplates of its
+// StatefulSets, which are in neither.
+func releaseObjects(ctx context.Context, kube client.Client, cr *v1beta1.Release, rel *release.Release) ([]*unstructured.Unstructured, error) {
+	objs := helmClient.ObjectsFromManifest(rel.Manifest)
+	if rel.Chart != nil && !cr.Spec.ForProvider.SkipCRDs && cr.Spec.ForProvider.CRDPolicy != v1beta1.CRDPolicySkip {
+		for _, crd := range rel.Chart.CRDObjects() {
+			objs = append(objs, helmClient.ObjectsFromManifest(string(crd.File.Data))...)
+		}
+	}
+	if !safeguardsKind(cr, pvcGVK) {
+		return objs, nil
+	}
+	for _, o := range objs {
+		if o.GroupVersionKind() != statefulSetGVK {
+			continue
+		}
+		claims, err := statefulSetClaims(ctx, kube, o, rel.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, claims...)
+	}
+	return objs, nil
+}
+
+// safeguardsKind returns true if an uninstall safeguard of the Release
+// matches objects of the supplied kind.
+func safeguardsKind(cr *v1beta1.Release, gvk schema.GroupVersionKind) bool {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	for _, sg := range uninstallSafeguards(cr) {
+		if sg.APIVersion == apiVersion && sg.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// statefulSetClaims returns the PersistentVolumeClaims the StatefulSet
+// controller created from the volumeClaimTemplates of the supplied
+// StatefulSet. They carry the labels of its selector and are named
+// <template>-<statefulset>-<ordinal>.
+func statefulSetClaims(ctx context.Context, kube client.Client, sts *unstructured.Unstructured, namespace string) ([]*unstructured.Unstructured, error) {
+	templates, _, _ := unstructured.NestedSlice(sts.Object, "spec", "volumeClaimTemplates")
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	if ns := sts.GetNamespace(); ns != "" {
+		namespace = ns
+	}
+	sel, _, _ := unstructured.NestedStringMap(sts.Object, "spec", "selector", "matchLabels")
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(pvcListGVK)
+	if err := kube.List(ctx, l, client.InNamespace(namespace), client.MatchingLabels(sel)); err != nil {
+		return nil, errors.Wrapf(err, errFailedToListClaimsTmpl, objectRef(sts))
+	}
+
+	var claims []*unstructured.Unstructured
+	for _, t := range templates {
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(m, "metadata", "name")
+		prefix := name + "-" + sts.GetName() + "-"
+		for i := range l.Items {
+			ordinal, ok := strings.CutPrefix(l.Items[i].GetName(), prefix)
+			if _, err := strconv.Atoi(ordinal); !ok || err != nil {
+				continue
+			}
+			c := &l.Items[i]
+			c.SetGroupVersionKind(pvcGVK)
+			claims = append(claims, c)
+		}
+	}
+	return claims, nil
+}
+
+// safeguardBlocks returns true if the live resource of the supplied object
+// meets the condition of an uninstall safeguard.
+func safeguardBlocks(ctx context.Context, kube client.Client, sg v1beta1.UninstallSafeguard, sel labels.Selector, o *unstructured.Unstructured, namespace string) (bool, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(o.GroupVersionKind())
+	namespaced, err := kube.IsObjectNamespaced(live)
+	if err != nil {
+		return false, err
+	}
+	key := client.ObjectKey{Name: o.GetName()}
+	if namespaced {
+		key.Namespace = o.GetNamespace()
+		if key.Namespace == "" {
+			key.Namespace = namespace
+		}
+	}
+	if err := kube.Get(ctx, key, live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if !sel.Matches(labels.Set(live.GetLabels())) {
+		return false, nil
+	}
+
+	switch sg.When {
+	case v1beta1.SafeguardConditionBound:
+		phase, _, _ := unstructured.NestedString(live.Object, "status", "phase")
+		return phase == pvcPhaseBound, nil
+	case v1beta1.SafeguardConditionHasInstances:
+		return hasCustomResources(ctx, kube, live)
+	case v1beta1.SafeguardConditionExists:
+	}
+	return true, nil
+}
+
+// hasCustomResources returns true if custom resources of the supplied
+// CustomResourceDefinition exist.
+func hasCustomResources(ctx context.Context, kube client.Client, crd *unstructured.Unstructured) (bool, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+
+	version := ""
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if served, _ := m["served"].(bool); served {
+			version, _ = m["name"].(string)
+			break
+		}
+	}
+	if kind == "" || version == "" {
+		return false, nil
+	}
+
+	gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(kind + "List"))
+	if err := kube.List(ctx, l, client.Limit(1)); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, errFailedToListCustomResourceTmpl, gvk.String())
+	}
+	return len(l.Items) > 0, nil
+}
+
+func objectRef(o *unstructured.Unstructured) string {
+	if o.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", o.GetKind(), o.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", o.GetKind(), o.GetNamespace(), o.GetName())
+}
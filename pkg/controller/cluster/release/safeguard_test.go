@@ -0,0 +1,253 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v4/pkg/chart/common"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/cluster/release/v1beta1"
+)
+
+const testStatefulSetManifest = `---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  selector:
+    matchLabels:
+      app: db
+  volumeClaimTemplates:
+    - metadata:
+        name: data
+`
+
+const testChartCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+`
+
+const testSafeguardManifest = `---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+`
+
+func Test_checkUninstallSafeguards(t *testing.T) {
+	pvc := func(phase string, labels map[string]string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Namespace != testNamespace || key.Name != "data" {
+				return errBoom
+			}
+			u := obj.(*unstructured.Unstructured)
+			u.SetLabels(labels)
+			return unstructured.SetNestedField(u.Object, phase, "status", "phase")
+		}
+	}
+	crd := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace != "" {
+			return errBoom
+		}
+		u := obj.(*unstructured.Unstructured)
+		u.Object["spec"] = map[string]interface{}{
+			"group": "example.org",
+			"names": map[string]interface{}{"kind": "Widget"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": false},
+				map[string]interface{}{"name": "v1", "served": true},
+			},
+		}
+		return nil
+	}
+	namespaced := func(obj runtime.Object) (bool, error) {
+		return obj.GetObjectKind().GroupVersionKind().Kind == "PersistentVolumeClaim", nil
+	}
+	withSafeguards := func(sg ...v1beta1.UninstallSafeguard) *v1beta1.Release {
+		return helmRelease(func(r *v1beta1.Release) {
+			r.Spec.ForProvider.Deletion = &v1beta1.DeletionSpec{Safeguards: sg}
+		})
+	}
+	withCRDs := func(manifest string) *release.Release {
+		return &release.Release{
+			Namespace: testNamespace,
+			Manifest:  manifest,
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: testChart},
+				Files:    []*common.File{{Name: "crds/widgets.yaml", Data: []byte(testChartCRD)}},
+			},
+		}
+	}
+	instances := func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		l := list.(*unstructured.UnstructuredList)
+		if l.GroupVersionKind().String() != "example.org/v1, Kind=WidgetList" {
+			return errBoom
+		}
+		l.Items = []unstructured.Unstructured{{}}
+		return nil
+	}
+	// claims lists the claims of the db StatefulSet, which only carry the
+	// labels of its selector, and others.
+	claims := func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		l := list.(*unstructured.UnstructuredList)
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		if l.GroupVersionKind().Kind != "PersistentVolumeClaimList" || lo.Namespace != testNamespace || lo.LabelSelector.String() != "app=db" {
+			return errBoom
+		}
+		for _, n := range []string{"data-db-0", "logs-db-0", "data-db-backup"} {
+			c := unstructured.Unstructured{}
+			c.SetName(n)
+			c.SetNamespace(testNamespace)
+			l.Items = append(l.Items, c)
+		}
+		return nil
+	}
+	boundClaim := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace != testNamespace || key.Name != "data-db-0" {
+			return errBoom
+		}
+		return unstructured.SetNestedField(obj.(*unstructured.Unstructured).Object, "Bound", "status", "phase")
+	}
+
+	cases := map[string]struct {
+		kube client.Client
+		cr   *v1beta1.Release
+		rel  *release.Release
+		want error
+	}{
+		"NoSafeguards": {
+			cr: helmRelease(),
+		},
+		"BoundPVC": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                pvc("Bound", nil),
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim", When: v1beta1.SafeguardConditionBound}),
+			want: errors.Errorf(errUninstallSafeguardTmpl, "PersistentVolumeClaim/data", 0),
+		},
+		"PendingPVC": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                pvc("Pending", nil),
+			},
+			cr: withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim", When: v1beta1.SafeguardConditionBound}),
+		},
+		"SelectorDoesNotMatch": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                pvc("Bound", map[string]string{"tier": "cache"}),
+			},
+			cr: withSafeguards(v1beta1.UninstallSafeguard{
+				APIVersion: "v1",
+				Kind:       "PersistentVolumeClaim",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "data"}},
+			}),
+		},
+		"PVCGone": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "persistentvolumeclaims"}, "data")),
+			},
+			cr: withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim"}),
+		},
+		"CRDWithInstances": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                crd,
+				MockList:               instances,
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", When: v1beta1.SafeguardConditionHasInstances}),
+			want: errors.Errorf(errUninstallSafeguardTmpl, "CustomResourceDefinition/widgets.example.org", 0),
+		},
+		"CRDWithoutInstances": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                crd,
+				MockList:               test.NewMockListFn(nil),
+			},
+			cr: withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", When: v1beta1.SafeguardConditionHasInstances}),
+		},
+		"StatefulSetClaimBound": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                boundClaim,
+				MockList:               claims,
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim", When: v1beta1.SafeguardConditionBound}),
+			rel:  &release.Release{Namespace: testNamespace, Manifest: testStatefulSetManifest},
+			want: errors.Errorf(errUninstallSafeguardTmpl, "PersistentVolumeClaim/"+testNamespace+"/data-db-0", 0),
+		},
+		"FailedToListStatefulSetClaims": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockList:               test.NewMockListFn(errBoom),
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim"}),
+			rel:  &release.Release{Namespace: testNamespace, Manifest: testStatefulSetManifest},
+			want: errors.Wrapf(errBoom, errFailedToListClaimsTmpl, "StatefulSet/db"),
+		},
+		"ChartCRDWithInstances": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                crd,
+				MockList:               instances,
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", When: v1beta1.SafeguardConditionHasInstances}),
+			rel:  withCRDs(""),
+			want: errors.Errorf(errUninstallSafeguardTmpl, "CustomResourceDefinition/widgets.example.org", 0),
+		},
+		"ChartCRDsSkipped": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                crd,
+				MockList:               instances,
+			},
+			cr: func() *v1beta1.Release {
+				r := withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", When: v1beta1.SafeguardConditionHasInstances})
+				r.Spec.ForProvider.CRDPolicy = v1beta1.CRDPolicySkip
+				return r
+			}(),
+			rel: withCRDs(""),
+		},
+		"FailedToGet": {
+			kube: &test.MockClient{
+				MockIsObjectNamespaced: namespaced,
+				MockGet:                test.NewMockGetFn(errBoom),
+			},
+			cr:   withSafeguards(v1beta1.UninstallSafeguard{APIVersion: "v1", Kind: "PersistentVolumeClaim"}),
+			want: errors.Wrapf(errBoom, errFailedToCheckSafeguardTmpl, "PersistentVolumeClaim/data", 0),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rel := tc.rel
+			if rel == nil {
+				rel = &release.Release{Namespace: testNamespace, Manifest: testSafeguardManifest}
+			}
+			err := checkUninstallSafeguards(context.Background(), tc.kube, tc.cr, rel)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("checkUninstallSafeguards(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-helm/pkg/controller/namespaced/config"
 	"github.com/crossplane-contrib/provider-helm/pkg/controller/namespaced/release"
+	"github.com/crossplane-contrib/provider-helm/pkg/controller/namespaced/releaseset"
 )
 
 // Setup creates all Helm controllers with the supplied logger and adds them
@@ -32,6 +33,7 @@ func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error
 	for _, setup := range []func(ctrl.Manager, controller.Options, time.Duration) error{
 		config.Setup,
 		release.Setup,
+		releaseset.Setup,
 	} {
 		if err := setup(mgr, o, timeout); err != nil {
 			return err
@@ -46,6 +48,7 @@ func SetupGated(mgr ctrl.Manager, o controller.Options, timeout time.Duration) e
 	for _, setup := range []func(ctrl.Manager, controller.Options, time.Duration) error{
 		config.SetupGated,
 		release.SetupGated,
+		releaseset.SetupGated,
 	} {
 		if err := setup(mgr, o, timeout); err != nil {
 			return err
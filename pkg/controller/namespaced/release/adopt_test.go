@@ -0,0 +1,184 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func existingRelease(version string, config map[string]interface{}) *release.Release {
+	return &release.Release{
+		Name: testReleaseName,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    testChart,
+				Version: version,
+			},
+		},
+		Config: config,
+	}
+}
+
+func Test_lateInitializeFromRelease(t *testing.T) {
+	type want struct {
+		li  bool
+		cr  *v1beta1.Release
+		err error
+	}
+	cases := map[string]struct {
+		cr   *v1beta1.Release
+		rel  *release.Release
+		want want
+	}{
+		"VersionAndValues": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = ""
+				r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true, LateInitializeValues: true}
+			}),
+			rel: existingRelease(testVersion, map[string]interface{}{"replicas": 2}),
+			want: want{
+				li: true,
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true, LateInitializeValues: true}
+					r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}
+				}),
+			},
+		},
+		"ValuesNotRequested": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = ""
+				r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true}
+			}),
+			rel: existingRelease(testVersion, map[string]interface{}{"password": "s3cr3t"}),
+			want: want{
+				li: true,
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true}
+				}),
+			},
+		},
+		"GitChartVersionNotLateInitialized": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = ""
+				r.Spec.ForProvider.Chart.Git = &v1beta1.GitChartSource{URL: "https://example.org/charts.git"}
+			}),
+			rel: existingRelease(testVersion, nil),
+			want: want{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Version = ""
+					r.Spec.ForProvider.Chart.Git = &v1beta1.GitChartSource{URL: "https://example.org/charts.git"}
+				}),
+			},
+		},
+		"InlineChartVersionNotLateInitialized": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = ""
+				r.Spec.ForProvider.Chart.Inline = map[string]string{"Chart.yaml": "name: test"}
+			}),
+			rel: existingRelease(testVersion, nil),
+			want: want{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Version = ""
+					r.Spec.ForProvider.Chart.Inline = map[string]string{"Chart.yaml": "name: test"}
+				}),
+			},
+		},
+		"ValuesAlreadySpecified": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Set = []v1beta1.SetVal{{Name: "replicas", Value: "3"}}
+			}),
+			rel: existingRelease(testVersion, map[string]interface{}{"replicas": 2}),
+			want: want{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Set = []v1beta1.SetVal{{Name: "replicas", Value: "3"}}
+				}),
+			},
+		},
+		"NotAllowedByManagementPolicies": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = ""
+				r.Spec.ManagementPolicies = xpv2.ManagementPolicies{xpv2.ManagementActionObserve}
+			}),
+			rel: existingRelease(testVersion, map[string]interface{}{"replicas": 2}),
+			want: want{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Version = ""
+					r.Spec.ManagementPolicies = xpv2.ManagementPolicies{xpv2.ManagementActionObserve}
+				}),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li, err := lateInitializeFromRelease(tc.cr, tc.rel)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("lateInitializeFromRelease(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.li, li); diff != "" {
+				t.Errorf("lateInitializeFromRelease(...): -want late-initialized, +got late-initialized: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr); diff != "" {
+				t.Errorf("lateInitializeFromRelease(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_adoptionDiff(t *testing.T) {
+	type want struct {
+		diff []string
+		err  error
+	}
+	cases := map[string]struct {
+		cr   *v1beta1.Release
+		rel  *release.Release
+		want want
+	}{
+		"NoDiff": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"image":{"tag":"1.0"},"replicas":2}`)}
+			}),
+			rel: existingRelease(testVersion, map[string]interface{}{
+				"image":    map[string]interface{}{"tag": "1.0"},
+				"replicas": int64(2),
+			}),
+		},
+		"ChartAndValues": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"image":{"tag":"1.1"},"ingress":{"enabled":true}}`)}
+			}),
+			rel: existingRelease("0.9.0", map[string]interface{}{
+				"image":    map[string]interface{}{"tag": "1.0"},
+				"replicas": 2,
+			}),
+			want: want{
+				diff: []string{"chart.version", "values.image.tag", "values.ingress", "values.replicas"},
+			},
+		},
+		"DevelVersion": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Chart.Version = devel
+			}),
+			rel: existingRelease("0.9.0", nil),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diff, err := adoptionDiff(context.Background(), nil, tc.cr, tc.rel)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("adoptionDiff(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.diff, diff); diff != "" {
+				t.Errorf("adoptionDiff(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
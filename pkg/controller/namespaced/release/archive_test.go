@@ -0,0 +1,118 @@
+package release
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func Test_chartArchive(t *testing.T) {
+	archive := []byte{0x1f, 0x8b, 0x08, 0x00}
+	encoded := base64.StdEncoding.EncodeToString(archive)
+
+	getConfigMap := func(cm corev1.ConfigMap) test.MockGetFn {
+		return func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name != testCMName || key.Namespace != testNamespace {
+				return errBoom
+			}
+			*obj.(*corev1.ConfigMap) = cm
+			return nil
+		}
+	}
+	getSecret := func(data map[string][]byte) test.MockGetFn {
+		return func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name != testSecretName || key.Namespace != testNamespace {
+				return errBoom
+			}
+			*obj.(*corev1.Secret) = corev1.Secret{Data: data}
+			return nil
+		}
+	}
+	fromConfigMap := func(key string) v1beta1.ChartSpec {
+		return v1beta1.ChartSpec{FromConfigMap: &v1beta1.DataKeySelector{Name: testCMName, Key: key}}
+	}
+	fromSecret := func(key string) v1beta1.ChartSpec {
+		return v1beta1.ChartSpec{FromSecret: &v1beta1.DataKeySelector{Name: testSecretName, Key: key}}
+	}
+
+	type want struct {
+		out []byte
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		chart  v1beta1.ChartSpec
+		want   want
+	}{
+		"NoArchive": {
+			reason: "A chart pulled from a repository has no archive.",
+			kube:   &test.MockClient{},
+			chart:  v1beta1.ChartSpec{Repository: "https://charts.example.com"},
+		},
+		"ConfigMapBinaryData": {
+			reason: "An archive should be read from the binary data of a ConfigMap.",
+			kube:   &test.MockClient{MockGet: getConfigMap(corev1.ConfigMap{BinaryData: map[string][]byte{defaultChartArchiveKey: archive}})},
+			chart:  fromConfigMap(""),
+			want:   want{out: archive},
+		},
+		"ConfigMapData": {
+			reason: "A base64 encoded archive should be read from the data of a ConfigMap.",
+			kube:   &test.MockClient{MockGet: getConfigMap(corev1.ConfigMap{Data: map[string]string{"chart": encoded + "\n"}})},
+			chart:  fromConfigMap("chart"),
+			want:   want{out: archive},
+		},
+		"ConfigMapKeyMissing": {
+			reason: "An error should be returned if the key is missing from a ConfigMap.",
+			kube:   &test.MockClient{MockGet: getConfigMap(corev1.ConfigMap{Data: map[string]string{"other": encoded}})},
+			chart:  fromConfigMap(""),
+			want:   want{err: errors.Errorf(errChartArchiveKeyMissingTmpl, defaultChartArchiveKey)},
+		},
+		"FailedToGetConfigMap": {
+			reason: "An error should be returned if the ConfigMap cannot be read.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			chart:  fromConfigMap(""),
+			want:   want{err: errors.Wrapf(errBoom, errFailedToGetConfigMap, testNamespace)},
+		},
+		"Secret": {
+			reason: "An archive should be read from a Secret.",
+			kube:   &test.MockClient{MockGet: getSecret(map[string][]byte{defaultChartArchiveKey: archive})},
+			chart:  fromSecret(""),
+			want:   want{out: archive},
+		},
+		"SecretBase64": {
+			reason: "A base64 encoded archive should be read from a Secret.",
+			kube:   &test.MockClient{MockGet: getSecret(map[string][]byte{"chart": []byte(encoded)})},
+			chart:  fromSecret("chart"),
+			want:   want{out: archive},
+		},
+		"SecretNotAnArchive": {
+			reason: "An error should be returned if a Secret holds neither an archive nor a base64 encoded one.",
+			kube:   &test.MockClient{MockGet: getSecret(map[string][]byte{defaultChartArchiveKey: []byte("not a chart")})},
+			chart:  fromSecret(""),
+			want:   want{err: errors.Wrap(base64.CorruptInputError(3), errFailedToDecodeChartArchive)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := chartArchive(context.Background(), tc.kube, tc.chart, testNamespace)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nchartArchive(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("\n%s\nchartArchive(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
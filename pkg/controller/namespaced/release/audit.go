@@ -0,0 +1,305 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ktypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+const (
+	auditKeyManifest = "manifest.yaml"
+	auditKeyHooks    = "hooks.yaml"
+	auditKeyChart    = "chart.yaml"
+	auditKeyPatches  = "patches.yaml"
+	auditKeyValues   = "values.yaml"
+
+	auditLabelRelease  = "helm.crossplane.io/release"
+	auditLabelRevision = "helm.crossplane.io/revision"
+
+	defaultKeepAuditSnapshots = 10
+)
+
+const (
+	errFailedToComposeRedactedValues = "failed to compose redacted values"
+	errFailedToMarshalAuditSnapshot  = "failed to marshal audit snapshot"
+	errFailedToCreateAuditConfigMap  = "failed to create audit snapshot configmap"
+	errFailedToCreateAuditSecret     = "failed to create audit snapshot secret"
+	errFailedToGetAuditConfigMap     = "failed to get existing audit snapshot configmap"
+	errFailedToGetAuditSecret        = "failed to get existing audit snapshot secret"
+	errFailedToListAuditSnapshots    = "failed to list audit snapshots"
+	errFailedToDeleteAuditSnapshot   = "failed to delete audit snapshot"
+	errAuditSnapshotDiffersTmpl      = "audit snapshot %s/%s already exists with different content"
+)
+
+// auditChart records the chart a revision was deployed from.
+type auditChart struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	AppVersion    string `json:"appVersion,omitempty"`
+	Repository    string `json:"repository,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Digest        string `json:"digest,omitempty"`
+	ContentSha256 string `json:"contentSha256,omitempty"`
+}
+
+func auditEnabled(cr *v1beta1.Release) bool {
+	return cr.Spec.ForProvider.Audit != nil && cr.Spec.ForProvider.Audit.Enabled
+}
+
+func keepAuditSnapshots(cr *v1beta1.Release) int {
+	if k := cr.Spec.ForProvider.Audit.KeepSnapshots; k > 0 {
+		return k
+	}
+	return defaultKeepAuditSnapshots
+}
+
+// auditSnapshotName returns the name of the snapshot of a deployed revision.
+// Helm numbers revisions from 1 again once a release is uninstalled and
+// installed again, and so does a Release recreated with the same name, so the
+// name identifies the Release and the installation of the revision, too. The
+// name of the Release is truncated if the snapshot name would be too long.
+func auditSnapshotName(cr *v1beta1.Release, rel *release.Release) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00", cr.GetUID())
+	if rel.Info != nil {
+		_, _ = fmt.Fprint(h, rel.Info.FirstDeployed.UTC().Format(time.RFC3339Nano))
+	}
+	suffix := fmt.Sprintf("-audit-%x-v%d", h.Sum(nil)[:4], rel.Version)
+	return truncateName(cr.GetName(), validation.DNS1123SubdomainMaxLength-len(suffix)) + suffix
+}
+
+// auditReleaseLabel returns the value of the label the snapshots of a Release
+// are selected by. Names too long for a label value are truncated and
+// suffixed with their hash to keep them apart.
+func auditReleaseLabel(cr *v1beta1.Release) string {
+	n := cr.GetName()
+	if len(n) <= validation.LabelValueMaxLength {
+		return n
+	}
+	h := sha256.Sum256([]byte(n))
+	suffix := fmt.Sprintf("-%x", h[:4])
+	return truncateName(n, validation.LabelValueMaxLength-len(suffix)) + suffix
+}
+
+// truncateName truncates a name to the supplied length, without leaving a
+// separator at its end.
+func truncateName(n string, length int) string {
+	if len(n) > length {
+		n = n[:length]
+	}
+	return strings.TrimRight(n, ".-_")
+}
+
+// audit writes the snapshot of a deployed revision, if auditing is enabled.
+// The revision was deployed regardless, so a failure to write the snapshot is
+// reported under its own reason rather than as a failed install or upgrade.
+func (e *helmExternal) audit(ctx context.Context, cr *v1beta1.Release, d *deployment) error {
+	if !auditEnabled(cr) {
+		return nil
+	}
+	ref, err := writeAuditSnapshot(ctx, e.localKube, cr, d.release, d.values, d.patches)
+	if err == nil {
+		cr.Status.AuditSnapshot = ref
+		err = pruneAuditSnapshots(ctx, e.localKube, cr, ref.Namespace, keepAuditSnapshots(cr))
+	}
+	if err != nil {
+		err = errors.Wrap(err, errFailedToWriteAuditSnapshot)
+		e.event(cr, event.Warning(reasonAuditSnapshotFailed, err))
+		return err
+	}
+	return nil
+}
+
+// writeAuditSnapshot writes the snapshot of a deployed revision. The chart and
+// patches go into a ConfigMap together with the values, where values sourced
+// from Secrets are redacted. The manifest and hooks, which may render Secrets,
+// go into a Secret of the same name together with the values as they were
+// passed to Helm. Both are immutable, and are left untouched if they already
+// exist with the same content.
+func writeAuditSnapshot(ctx context.Context, kube client.Client, cr *v1beta1.Release, rel *release.Release, vals map[string]interface{}, patches []ktypes.Patch) (*v1beta1.AuditSnapshotReference, error) {
+	redacted, err := composeRedactedValuesFromSpec(ctx, kube, cr.Spec.ForProvider.ValuesSpec, cr.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToComposeRedactedValues)
+	}
+
+	data := map[string]string{}
+	for k, v := range map[string]interface{}{
+		auditKeyChart:   chartOf(cr, rel),
+		auditKeyPatches: patches,
+		auditKeyValues:  redacted,
+	} {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToMarshalAuditSnapshot)
+		}
+		data[k] = string(b)
+	}
+
+	v, err := yaml.Marshal(vals)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToMarshalAuditSnapshot)
+	}
+	secretData := map[string][]byte{
+		auditKeyManifest: []byte(rel.Manifest),
+		auditKeyValues:   v,
+	}
+	if h := hooksManifest(rel); h != "" {
+		secretData[auditKeyHooks] = []byte(h)
+	}
+
+	om := metav1.ObjectMeta{
+		Name:      auditSnapshotName(cr, rel),
+		Namespace: cr.Namespace,
+		Labels: map[string]string{
+			auditLabelRelease:  auditReleaseLabel(cr),
+			auditLabelRevision: strconv.Itoa(rel.Version),
+		},
+	}
+	immutable := true
+
+	cm := &corev1.ConfigMap{ObjectMeta: *om.DeepCopy(), Immutable: &immutable, Data: data}
+	if err := kube.Create(ctx, cm); kerrors.IsAlreadyExists(err) {
+		existing := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(cm), existing); err != nil {
+			return nil, errors.Wrap(err, errFailedToGetAuditConfigMap)
+		}
+		if !maps.Equal(existing.Data, cm.Data) {
+			return nil, errors.Errorf(errAuditSnapshotDiffersTmpl, om.Namespace, om.Name)
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateAuditConfigMap)
+	}
+	s := &corev1.Secret{ObjectMeta: *om.DeepCopy(), Immutable: &immutable, Data: secretData}
+	if err := kube.Create(ctx, s); kerrors.IsAlreadyExists(err) {
+		existing := &corev1.Secret{}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(s), existing); err != nil {
+			return nil, errors.Wrap(err, errFailedToGetAuditSecret)
+		}
+		if !maps.EqualFunc(existing.Data, s.Data, bytes.Equal) {
+			return nil, errors.Errorf(errAuditSnapshotDiffersTmpl, om.Namespace, om.Name)
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateAuditSecret)
+	}
+
+	return &v1beta1.AuditSnapshotReference{
+		Name:      om.Name,
+		Namespace: om.Namespace,
+		Revision:  rel.Version,
+	}, nil
+}
+
+// pruneAuditSnapshots deletes the snapshots of a Release in the supplied
+// namespace but the supplied number of most recent ones.
+func pruneAuditSnapshots(ctx context.Context, kube client.Client, cr *v1beta1.Release, namespace string, keep int) error {
+	l := &corev1.ConfigMapList{}
+	if err := kube.List(ctx, l, client.InNamespace(namespace), client.MatchingLabels{auditLabelRelease: auditReleaseLabel(cr)}); err != nil {
+		return errors.Wrap(err, errFailedToListAuditSnapshots)
+	}
+	if len(l.Items) <= keep {
+		return nil
+	}
+	sort.SliceStable(l.Items, func(i, j int) bool {
+		ti, tj := l.Items[i].GetCreationTimestamp(), l.Items[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		ri, _ := strconv.Atoi(l.Items[i].GetLabels()[auditLabelRevision])
+		rj, _ := strconv.Atoi(l.Items[j].GetLabels()[auditLabelRevision])
+		return ri > rj
+	})
+	for i := range l.Items[keep:] {
+		cm := &l.Items[keep+i]
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: cm.GetName(), Namespace: cm.GetNamespace()}}
+		for _, o := range []client.Object{s, cm} {
+			if err := kube.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errFailedToDeleteAuditSnapshot)
+			}
+		}
+	}
+	return nil
+}
+
+func chartOf(cr *v1beta1.Release, rel *release.Release) auditChart {
+	c := auditChart{
+		Name:       cr.Spec.ForProvider.Chart.Name,
+		Version:    cr.Spec.ForProvider.Chart.Version,
+		Repository: cr.Spec.ForProvider.Chart.Repository,
+		URL:        cr.Spec.ForProvider.Chart.URL,
+		Digest:     cr.Spec.ForProvider.Chart.Digest,
+	}
+	if rel.Chart == nil {
+		return c
+	}
+	if m := rel.Chart.Metadata; m != nil {
+		c.Name = m.Name
+		c.Version = m.Version
+		c.AppVersion = m.AppVersion
+	}
+	if len(rel.Chart.Raw) == 0 {
+		return c
+	}
+	// Hash the raw chart files in a stable order so that charts pulled from
+	// non-OCI repositories can be identified, too.
+	files := make([]string, 0, len(rel.Chart.Raw))
+	content := make(map[string][]byte, len(rel.Chart.Raw))
+	for _, f := range rel.Chart.Raw {
+		files = append(files, f.Name)
+		content[f.Name] = f.Data
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, f := range files {
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", f, len(content[f]))
+		_, _ = h.Write(content[f])
+	}
+	c.ContentSha256 = fmt.Sprintf("%x", h.Sum(nil))
+	return c
+}
+
+func hooksManifest(rel *release.Release) string {
+	var b strings.Builder
+	for _, h := range rel.Hooks {
+		if h == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", h.Path, h.Manifest)
+	}
+	return b.String()
+}
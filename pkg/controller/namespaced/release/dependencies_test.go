@@ -0,0 +1,163 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func Test_waitForDependencies(t *testing.T) {
+	dependsOn := func(names ...string) helmReleaseModifier {
+		return func(r *v1beta1.Release) {
+			for _, n := range names {
+				r.Spec.ForProvider.DependsOn = append(r.Spec.ForProvider.DependsOn, v1beta1.ReleaseReference{Name: n})
+			}
+		}
+	}
+	releases := func(rs map[string]*v1beta1.Release) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			r, ok := rs[key.Name]
+			if !ok || key.Namespace != testNamespace {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "releases"}, key.Name)
+			}
+			r.DeepCopyInto(obj.(*v1beta1.Release))
+			return nil
+		}
+	}
+	ready := func(generation, observed int64) helmReleaseModifier {
+		return func(r *v1beta1.Release) {
+			r.SetGeneration(generation)
+			c := xpv2.Available()
+			c.ObservedGeneration = observed
+			r.Status.SetConditions(c)
+		}
+	}
+
+	type want struct {
+		pending   []string
+		condition xpv2.Condition
+		err       error
+	}
+	cases := map[string]struct {
+		kube client.Client
+		cr   *v1beta1.Release
+		want want
+	}{
+		"NoDependencies": {
+			cr: helmRelease(),
+		},
+		"Available": {
+			kube: &test.MockClient{MockGet: releases(map[string]*v1beta1.Release{
+				"cert-manager": helmRelease(ready(2, 2)),
+			})},
+			cr: helmRelease(dependsOn("cert-manager")),
+		},
+		"AvailableAtPreviousGeneration": {
+			kube: &test.MockClient{MockGet: releases(map[string]*v1beta1.Release{
+				"cert-manager": helmRelease(ready(3, 2)),
+			})},
+			cr: helmRelease(dependsOn("cert-manager")),
+			want: want{
+				pending:   []string{testNamespace + "/cert-manager"},
+				condition: waitingForDependencies([]string{testNamespace + "/cert-manager"}),
+			},
+		},
+		"Missing": {
+			kube: &test.MockClient{MockGet: releases(nil)},
+			cr:   helmRelease(dependsOn("cert-manager", "ingress")),
+			want: want{
+				pending:   []string{testNamespace + "/cert-manager", testNamespace + "/ingress"},
+				condition: waitingForDependencies([]string{testNamespace + "/cert-manager", testNamespace + "/ingress"}),
+			},
+		},
+		"Cycle": {
+			kube: &test.MockClient{MockGet: releases(map[string]*v1beta1.Release{
+				"ingress": helmRelease(dependsOn("cert-manager")),
+				"cert-manager": helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.DependsOn = []v1beta1.ReleaseReference{{Name: testReleaseName}}
+				}),
+			})},
+			cr: helmRelease(dependsOn("ingress")),
+			want: want{
+				err: errors.Errorf(errDependencyCycleTmpl, testNamespace+"/"+testReleaseName+" -> "+testNamespace+"/ingress -> "+testNamespace+"/cert-manager -> "+testNamespace+"/"+testReleaseName),
+			},
+		},
+		"FailedToGet": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:   helmRelease(dependsOn("cert-manager")),
+			want: want{
+				err: errors.Wrapf(errBoom, errFailedToGetDependencyTmpl, testNamespace+"/cert-manager"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pending, err := waitForDependencies(context.Background(), tc.kube, tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("waitForDependencies(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.pending, pending); diff != "" {
+				t.Errorf("waitForDependencies(...): -want pending, +got pending: %s", diff)
+			}
+			if len(tc.want.pending) == 0 {
+				return
+			}
+			if diff := cmp.Diff(tc.want.condition, tc.cr.GetCondition(xpv2.TypeReady), cmpopts.IgnoreFields(xpv2.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("waitForDependencies(...): -want condition, +got condition: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_dependentsOf(t *testing.T) {
+	dependsOn := func(name string, refs ...v1beta1.ReleaseReference) *v1beta1.Release {
+		return helmRelease(func(r *v1beta1.Release) {
+			r.SetName(name)
+			r.Spec.ForProvider.DependsOn = refs
+		})
+	}
+
+	cases := map[string]struct {
+		kube client.Client
+		want []reconcile.Request
+	}{
+		"Dependents": {
+			kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+				obj.(*v1beta1.ReleaseList).Items = []v1beta1.Release{
+					*dependsOn("ingress", v1beta1.ReleaseReference{Name: "cert-manager"}),
+					*dependsOn("app", v1beta1.ReleaseReference{Name: "ingress"}, v1beta1.ReleaseReference{Name: "cert-manager"}),
+					*dependsOn("unrelated", v1beta1.ReleaseReference{Name: "ingress"}),
+				}
+				return nil
+			})},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "ingress"}},
+				{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "app"}},
+			},
+		},
+		"FailedToList": {
+			kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := dependentsOf(tc.kube, logging.NewNopLogger())(context.Background(), dependsOn("cert-manager"))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("dependentsOf(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	ktypes "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+const (
+	errFailedToHashImages = "failed to compute images sha"
+)
+
+// kustomizeImages converts the image overrides of a Release into Kustomize
+// images.
+func kustomizeImages(in []v1beta1.Image) []ktypes.Image {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]ktypes.Image, 0, len(in))
+	for _, i := range in {
+		out = append(out, ktypes.Image{
+			Name:    i.Name,
+			NewName: i.NewName,
+			NewTag:  i.NewTag,
+			Digest:  i.Digest,
+		})
+	}
+	return out
+}
+
+// imagesSha returns the hash of the image overrides, which is stored in the
+// status to detect changes that require an upgrade.
+func imagesSha(in []v1beta1.Image) (string, error) {
+	if len(in) == 0 {
+		return "", nil
+	}
+
+	jb, err := json.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(jb)), nil
+}
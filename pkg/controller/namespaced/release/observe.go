@@ -21,6 +21,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/fieldpath"
@@ -38,6 +39,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/metrics"
 )
 
 const (
@@ -48,6 +51,15 @@ const (
 	devel                              = ">0.0.0-0"
 )
 
+// recordReleaseInfo records the chart deployed by the Release in metrics.
+func recordReleaseInfo(cr *v1beta1.Release, rel *release.Release) {
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return
+	}
+	m := rel.Chart.Metadata
+	metrics.SetReleaseInfo(v1beta1.ReleaseGroupKind, cr.GetNamespace(), cr.GetName(), m.Name, m.Version, m.AppVersion)
+}
+
 // generateObservation generates release observation for the input release object
 func generateObservation(in *release.Release) v1beta1.ReleaseObservation {
 	o := v1beta1.ReleaseObservation{}
@@ -62,11 +74,54 @@ func generateObservation(in *release.Release) v1beta1.ReleaseObservation {
 	// Store actual deployed chart version for observability
 	if in.Chart != nil && in.Chart.Metadata != nil {
 		o.Version = in.Chart.Metadata.Version
+		o.Commit = in.Chart.Metadata.Annotations[helmClient.AnnotationGitCommit]
+		o.Digest = in.Chart.Metadata.Annotations[helmClient.AnnotationContentDigest]
 	}
 
+	o.Images = helmClient.ImagesFromManifest(in.Manifest)
+	o.Test = testObservation(in)
+
 	return o
 }
 
+// testObservation returns the outcome of the test hooks of the supplied
+// release, or nil if none of them ran.
+func testObservation(in *release.Release) *v1beta1.ReleaseTestObservation {
+	var o *v1beta1.ReleaseTestObservation
+	for _, h := range in.Hooks {
+		if !slices.Contains(h.Events, release.HookTest) || h.LastRun.StartedAt.IsZero() {
+			continue
+		}
+		if o == nil {
+			o = &v1beta1.ReleaseTestObservation{Revision: in.Version, Phase: v1beta1.TestSucceeded}
+		}
+		if h.LastRun.Phase != release.HookPhaseSucceeded {
+			o.Phase = v1beta1.TestFailed
+			o.Message = fmt.Sprintf("test %s %s", h.Name, strings.ToLower(h.LastRun.Phase.String()))
+			return o
+		}
+	}
+	return o
+}
+
+// gitUpToDate returns true if a chart was deployed from the commit or ref
+// of the supplied Git source, or if the source is nil. A chart that follows a
+// ref must have been deployed from the head commit the ref resolves to now.
+func gitUpToDate(g *v1beta1.GitChartSource, annotations map[string]string, head string) bool {
+	if g == nil {
+		return true
+	}
+	commit, ok := annotations[helmClient.AnnotationGitCommit]
+	if !ok {
+		return false
+	}
+	if g.Commit != "" {
+		return g.Commit == commit
+	}
+	ref, ok := annotations[helmClient.AnnotationGitRef]
+	return ok && ref == g.Ref && commit == head
+}
+
 // normalizeConfig JSON-serializes and re-deserializes a config map to
 // normalize numeric types (int64 → float64), matching how Helm stores
 // release configs. This ensures desired vs observed comparison succeeds
@@ -91,7 +146,7 @@ func normalizeConfig(cfg map[string]interface{}) map[string]interface{} {
 }
 
 // isUpToDate checks whether desired spec up to date with the observed state for a given release
-func isUpToDate(ctx context.Context, kube client.Client, spec *v1beta1.ReleaseSpec, observed *release.Release, s v1beta1.ReleaseStatus, namespace string) (bool, error) { // nolint:gocyclo
+func isUpToDate(ctx context.Context, kube client.Client, spec *v1beta1.ReleaseSpec, observed *release.Release, s v1beta1.ReleaseStatus, gitHead string, namespace string) (bool, error) { // nolint:gocyclo
 	if observed.Info == nil {
 		return false, errors.New(errReleaseInfoNilInObservedRelease)
 	}
@@ -135,7 +190,32 @@ func isUpToDate(ctx context.Context, kube client.Client, spec *v1beta1.ReleaseSp
 		return false, nil
 	}
 
-	desiredConfig, err := composeValuesFromSpec(ctx, kube, in.ValuesSpec, namespace)
+	// Charts in Git are compared by the commit or ref they were checked out
+	// at, as recorded in the deployed chart, and by the commit the ref points
+	// to now
+	if !gitUpToDate(in.Chart.Git, ocm.Annotations, gitHead) {
+		return false, nil
+	}
+
+	// Charts given by their content are compared by its digest
+	if fromContent(in.Chart) {
+		d, err := contentDigest(ctx, kube, in.Chart, namespace)
+		if err != nil {
+			return false, errors.Wrap(err, errFailedToGetChartContent)
+		}
+		if d != ocm.Annotations[helmClient.AnnotationContentDigest] {
+			return false, nil
+		}
+	}
+
+	// Values sourced from Secrets are redacted in the values stored by Helm
+	// if requested, so they are compared by hash below instead.
+	redact := in.SecretValues != nil && in.SecretValues.Redact
+	compose := composeValuesFromSpec
+	if redact {
+		compose = composeRedactedValuesFromSpec
+	}
+	desiredConfig, err := compose(ctx, kube, in.ValuesSpec, namespace)
 	if err != nil {
 		return false, errors.Wrap(err, errFailedToComposeValues)
 	}
@@ -153,7 +233,21 @@ func isUpToDate(ctx context.Context, kube client.Client, spec *v1beta1.ReleaseSp
 		return false, nil
 	}
 
-	changed, err := newPatcher().hasUpdates(ctx, kube, in.PatchesFrom, s, namespace)
+	if redact {
+		vals, err := composeValuesFromSpec(ctx, kube, in.ValuesSpec, namespace)
+		if err != nil {
+			return false, errors.Wrap(err, errFailedToComposeValues)
+		}
+		sha, err := valuesSha(vals)
+		if err != nil {
+			return false, errors.Wrap(err, errFailedToHashValues)
+		}
+		if sha != s.ValuesSha {
+			return false, nil
+		}
+	}
+
+	changed, err := newPatcher().hasUpdates(ctx, kube, in.PatchesFrom, in.Patches, s, namespace)
 	if err != nil {
 		return false, errors.Wrap(err, errFailedToLoadPatches)
 	}
@@ -162,6 +256,14 @@ func isUpToDate(ctx context.Context, kube client.Client, spec *v1beta1.ReleaseSp
 		return false, nil
 	}
 
+	isha, err := imagesSha(in.Images)
+	if err != nil {
+		return false, errors.Wrap(err, errFailedToHashImages)
+	}
+	if !strings.EqualFold(isha, s.ImagesSha) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ktypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
@@ -22,13 +23,13 @@ const (
 
 // Patcher interface for managing Kustomize patches and detecting updates
 type Patcher interface {
-	hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, s v1beta1.ReleaseStatus, namespace string) (bool, error)
+	hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, inline []v1beta1.Patch, s v1beta1.ReleaseStatus, namespace string) (bool, error)
 	patchGetter
 	patchHasher
 }
 
 type patchGetter interface {
-	getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, namespace string) ([]ktypes.Patch, error)
+	getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, inline []v1beta1.Patch, namespace string) ([]ktypes.Patch, error)
 }
 
 type patchHasher interface {
@@ -47,8 +48,8 @@ type patch struct {
 	patchGetter
 }
 
-func (p patch) hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, s v1beta1.ReleaseStatus, namespace string) (bool, error) {
-	patches, err := p.getFromSpec(ctx, kube, in, namespace)
+func (p patch) hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, inline []v1beta1.Patch, s v1beta1.ReleaseStatus, namespace string) (bool, error) {
+	patches, err := p.getFromSpec(ctx, kube, in, inline, namespace)
 	if err != nil {
 		return false, err
 	}
@@ -78,7 +79,7 @@ func (patchSha) shaOf(patches []ktypes.Patch) (string, error) {
 
 type patchGet struct{}
 
-func (patchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, namespace string) ([]ktypes.Patch, error) {
+func (patchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, inline []v1beta1.Patch, namespace string) ([]ktypes.Patch, error) {
 	var base []ktypes.Patch // nolint:prealloc
 
 	for _, vf := range vals {
@@ -100,5 +101,35 @@ func (patchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1be
 		base = append(base, p.Patches...)
 	}
 
+	// Inline patches are applied after the ones loaded from PatchesFrom.
+	for _, ip := range inline {
+		base = append(base, kustomizePatch(ip))
+	}
+
 	return base, nil
 }
+
+// kustomizePatch converts an inline Release patch into a Kustomize patch.
+func kustomizePatch(in v1beta1.Patch) ktypes.Patch {
+	p := ktypes.Patch{
+		Patch: in.Patch,
+	}
+	if t := in.Target; t != nil {
+		p.Target = &ktypes.Selector{
+			ResId: resid.ResId{
+				Gvk:       resid.Gvk{Group: t.Group, Version: t.Version, Kind: t.Kind},
+				Name:      t.Name,
+				Namespace: t.Namespace,
+			},
+			AnnotationSelector: t.AnnotationSelector,
+			LabelSelector:      t.LabelSelector,
+		}
+	}
+	if o := in.Options; o != nil {
+		p.Options = &ktypes.PatchArgs{
+			AllowNameChange: o.AllowNameChange,
+			AllowKindChange: o.AllowKindChange,
+		}
+	}
+	return p
+}
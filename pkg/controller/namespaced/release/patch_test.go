@@ -119,7 +119,7 @@ type mockPatchGet struct {
 	err     error
 }
 
-func (m mockPatchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, namespace string) ([]types.Patch, error) {
+func (m mockPatchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource, inline []v1beta1.Patch, namespace string) ([]types.Patch, error) {
 	return m.patches, m.err
 }
 
@@ -221,7 +221,7 @@ func Test_PatchHasUpdates(t *testing.T) {
 			s := v1beta1.ReleaseStatus{
 				PatchesSha: tc.existingSha,
 			}
-			got, gotErr := p.hasUpdates(context.Background(), nil, nil, s, testNamespace)
+			got, gotErr := p.hasUpdates(context.Background(), nil, nil, nil, s, testNamespace)
 
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("Patch.hasUpdates(...): -want error, +got error: %s", diff)
@@ -235,8 +235,9 @@ func Test_PatchHasUpdates(t *testing.T) {
 
 func Test_getPatchesFromSpec(t *testing.T) {
 	type args struct {
-		kube client.Client
-		spec []v1beta1.ValueFromSource
+		kube   client.Client
+		spec   []v1beta1.ValueFromSource
+		inline []v1beta1.Patch
 	}
 
 	type want struct {
@@ -354,6 +355,86 @@ func Test_getPatchesFromSpec(t *testing.T) {
 				err: nil,
 			},
 		},
+		"loadInlinePatch": {
+			args: args{
+				inline: []v1beta1.Patch{
+					{
+						Patch: "- op: add\n  path: /metadata/labels/inline\n  value: patch",
+						Target: &v1beta1.PatchSelector{
+							Group:   "apps",
+							Version: "v1",
+							Kind:    "Deployment",
+							Name:    "wordpress",
+						},
+						Options: &v1beta1.PatchOptions{
+							AllowNameChange: true,
+						},
+					},
+				},
+			},
+			want: want{
+				out: []types.Patch{
+					{
+						Patch: "- op: add\n  path: /metadata/labels/inline\n  value: patch",
+						Target: &types.Selector{
+							ResId: resid.ResId{
+								Gvk:  resid.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"},
+								Name: "wordpress",
+							},
+						},
+						Options: &types.PatchArgs{
+							AllowNameChange: true,
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		"inlinePatchesAfterPatchesFrom": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						s := corev1.ConfigMap{
+							Data: map[string]string{
+								keyDefaultPatchFrom: fmt.Sprintf(testPatchConfig, key.Name),
+							},
+						}
+						*obj.(*corev1.ConfigMap) = s
+						return nil
+					},
+				},
+				spec: []v1beta1.ValueFromSource{
+					{
+						ConfigMapKeyRef: &v1beta1.DataKeySelector{
+							Name:     testCMName,
+							Key:      keyDefaultPatchFrom,
+							Optional: false,
+						},
+					},
+				},
+				inline: []v1beta1.Patch{
+					{
+						Patch: "apiVersion: v1\nkind: Service\nmetadata:\n  name: wordpress\n  annotations:\n    inline: patch",
+					},
+				},
+			},
+			want: want{
+				out: []types.Patch{
+					{
+						Patch: "- op: add\n  path: /spec/template/spec/nodeSelector\n  value:\n    node.size: really-big\n    aws.az: us-west-2a\n    patch.name: " + testCMName,
+						Target: &types.Selector{
+							ResId: resid.ResId{
+								Gvk: resid.Gvk{Kind: "Deployment"},
+							},
+						},
+					},
+					{
+						Patch: "apiVersion: v1\nkind: Service\nmetadata:\n  name: wordpress\n  annotations:\n    inline: patch",
+					},
+				},
+				err: nil,
+			},
+		},
 		"noPatchLoadedOptional": {
 			args: args{
 				kube: &test.MockClient{
@@ -380,7 +461,7 @@ func Test_getPatchesFromSpec(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			pg := patchGet{}
-			got, gotErr := pg.getFromSpec(context.Background(), tc.args.kube, tc.args.spec, testNamespace)
+			got, gotErr := pg.getFromSpec(context.Background(), tc.args.kube, tc.args.spec, tc.args.inline, testNamespace)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("getFromSpec(...): -want error, +got error: %s", diff)
 			}
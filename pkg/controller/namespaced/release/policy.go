@@ -0,0 +1,246 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	// policyCostLimit bounds the runtime cost of evaluating a single rule
+	// against a single object, so that a bad expression cannot stall a
+	// reconcile.
+	policyCostLimit = 1000000
+
+	celVarObject  = "object"
+	celVarRelease = "release"
+)
+
+const (
+	errFailedToListPolicies       = "cannot list release policies"
+	errInvalidPolicySelectorTmpl  = "invalid namespace selector in release policy %q"
+	errFailedToGetNamespace       = "cannot get namespace of release"
+	errFailedToCompilePolicyTmpl  = "cannot compile rule %q of release policy %q"
+	errPolicyRuleNotBool          = "expression must evaluate to a bool"
+	errFailedToCreateCELEnv       = "cannot create CEL environment"
+	errPolicyViolationsTmpl       = "rendered manifests violate %d release policy rule(s): %s"
+	errFailedToEvaluatePolicyTmpl = "cannot evaluate expression: %s"
+)
+
+// policyViolationError is returned when rendered objects violate the rules of
+// the ReleasePolicies that apply to a Release.
+type policyViolationError struct {
+	violations []v1beta1.PolicyViolation
+}
+
+func (e *policyViolationError) Error() string {
+	msgs := make([]string, 0, len(e.violations))
+	for _, v := range e.violations {
+		msgs = append(msgs, fmt.Sprintf("%s/%s: %s: %s", v.Policy, v.Rule, v.Object, v.Message))
+	}
+	return fmt.Sprintf(errPolicyViolationsTmpl, len(e.violations), strings.Join(msgs, "; "))
+}
+
+// policyValidator validates the rendered manifests of a Release against the
+// ReleasePolicies that apply to it. Policies are read when the manifests are
+// validated, so that policy changes apply to the next install or upgrade.
+type policyValidator struct {
+	ctx  context.Context
+	kube client.Client
+	cr   *v1beta1.Release
+}
+
+func withPolicyValidator(ctx context.Context, kube client.Client, cr *v1beta1.Release) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Validators = append(config.Validators, policyValidator{ctx: ctx, kube: kube, cr: cr})
+	}
+}
+
+func (v policyValidator) Validate(objs []*unstructured.Unstructured) error {
+	pl := &v1beta1.ReleasePolicyList{}
+	if err := v.kube.List(v.ctx, pl); err != nil {
+		return errors.Wrap(err, errFailedToListPolicies)
+	}
+	sort.Slice(pl.Items, func(i, j int) bool { return pl.Items[i].Name < pl.Items[j].Name })
+
+	env, err := newPolicyEnv()
+	if err != nil {
+		return errors.Wrap(err, errFailedToCreateCELEnv)
+	}
+
+	rel := map[string]string{
+		"name":            v.cr.Name,
+		"namespace":       v.cr.Namespace,
+		"targetNamespace": targetNamespace(v.cr),
+	}
+
+	ns := &namespaceLabels{ctx: v.ctx, kube: v.kube, name: v.cr.Namespace}
+	var violations []v1beta1.PolicyViolation
+	for _, p := range pl.Items {
+		applies, err := policyApplies(p, ns)
+		if err != nil {
+			return err
+		}
+		if !applies {
+			continue
+		}
+		for _, r := range p.Spec.Rules {
+			prg, err := compileRule(env, r)
+			if err != nil {
+				return errors.Wrapf(err, errFailedToCompilePolicyTmpl, r.Name, p.Name)
+			}
+			for _, o := range objs {
+				if !ruleMatches(r, o) {
+					continue
+				}
+				if msg := evaluateRule(prg, r, o, rel); msg != "" {
+					violations = append(violations, v1beta1.PolicyViolation{
+						Policy:  p.Name,
+						Rule:    r.Name,
+						Object:  objectRef(o),
+						Message: msg,
+					})
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &policyViolationError{violations: violations}
+	}
+	return nil
+}
+
+// recordPolicyViolations records the policy violations that caused an install
+// or upgrade to be blocked in the status of the Release, and clears them once
+// an attempt is no longer blocked by policies.
+func recordPolicyViolations(cr *v1beta1.Release, err error) {
+	var pv *policyViolationError
+	if errors.As(err, &pv) {
+		cr.Status.PolicyViolations = pv.violations
+		return
+	}
+	if err == nil {
+		cr.Status.PolicyViolations = nil
+	}
+}
+
+func newPolicyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(celVarObject, cel.DynType),
+		cel.Variable(celVarRelease, cel.MapType(cel.StringType, cel.StringType)),
+	)
+}
+
+// namespaceLabels reads the labels of the namespace of a Release once, when
+// a policy first needs them. Release and tenancy policies select Releases by
+// the labels of their namespace rather than their own, which tenants can
+// change.
+type namespaceLabels struct {
+	ctx    context.Context
+	kube   client.Client
+	name   string
+	labels labels.Set
+}
+
+// matches returns true if the supplied selector matches the namespace.
+func (n *namespaceLabels) matches(s labels.Selector) (bool, error) {
+	if n.labels == nil {
+		ns := &corev1.Namespace{}
+		if err := n.kube.Get(n.ctx, types.NamespacedName{Name: n.name}, ns); err != nil {
+			return false, errors.Wrap(err, errFailedToGetNamespace)
+		}
+		n.labels = labels.Set(ns.GetLabels())
+		if n.labels == nil {
+			n.labels = labels.Set{}
+		}
+	}
+	return s.Matches(n.labels), nil
+}
+
+func policyApplies(p v1beta1.ReleasePolicy, ns *namespaceLabels) (bool, error) {
+	if p.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+	if err != nil {
+		return false, errors.Wrapf(err, errInvalidPolicySelectorTmpl, p.Name)
+	}
+	return ns.matches(s)
+}
+
+func compileRule(env *cel.Env, r v1beta1.ReleasePolicyRule) (cel.Program, error) {
+	ast, iss := env.Compile(r.Expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.New(errPolicyRuleNotBool)
+	}
+	return env.Program(ast, cel.CostLimit(policyCostLimit))
+}
+
+func ruleMatches(r v1beta1.ReleasePolicyRule, o *unstructured.Unstructured) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	return matchesAnyKind(r.Kinds, o.GroupVersionKind().GroupKind())
+}
+
+// evaluateRule returns the violation message for an object, or an empty
+// string if the object satisfies the rule. Objects for which the expression
+// cannot be evaluated are treated as violations.
+func evaluateRule(prg cel.Program, r v1beta1.ReleasePolicyRule, o *unstructured.Unstructured, rel map[string]string) string {
+	out, _, err := prg.Eval(map[string]any{
+		celVarObject:  o.Object,
+		celVarRelease: rel,
+	})
+	if err != nil {
+		return fmt.Sprintf(errFailedToEvaluatePolicyTmpl, err)
+	}
+	if ok, isBool := out.Value().(bool); !isBool {
+		return errPolicyRuleNotBool
+	} else if ok {
+		return ""
+	}
+	if r.Message != "" {
+		return r.Message
+	}
+	return r.Expression
+}
+
+func objectRef(o *unstructured.Unstructured) string {
+	if o.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", o.GetKind(), o.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", o.GetKind(), o.GetNamespace(), o.GetName())
+}
@@ -0,0 +1,255 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func testPod(name string, privileged bool) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testNamespace,
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "app",
+					"image": "nginx",
+					"securityContext": map[string]interface{}{
+						"privileged": privileged,
+					},
+				},
+			},
+		},
+	}}
+}
+
+func testClusterRole() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"metadata": map[string]interface{}{
+			"name": "admin",
+		},
+	}}
+}
+
+func policyList(p ...v1beta1.ReleasePolicy) func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		list.(*v1beta1.ReleasePolicyList).Items = p
+		return nil
+	}
+}
+
+// namespaceWithLabels returns a MockGetFn that returns the namespace of a
+// Release with the supplied labels.
+func namespaceWithLabels(l map[string]string) test.MockGetFn {
+	return func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		ns, ok := obj.(*corev1.Namespace)
+		if !ok || key.Name != testNamespace {
+			return errBoom
+		}
+		ns.SetName(key.Name)
+		ns.SetLabels(l)
+		return nil
+	}
+}
+
+func Test_policyValidator_Validate(t *testing.T) {
+	noPrivileged := v1beta1.ReleasePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-privileged"},
+		Spec: v1beta1.ReleasePolicySpec{
+			Rules: []v1beta1.ReleasePolicyRule{
+				{
+					Name:       "containers",
+					Kinds:      []v1beta1.KindSelector{{Kind: "Pod"}},
+					Expression: "object.spec.containers.all(c, !has(c.securityContext) || !has(c.securityContext.privileged) || !c.securityContext.privileged)",
+					Message:    "privileged containers are not allowed",
+				},
+			},
+		},
+	}
+	namespacedOnly := v1beta1.ReleasePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespaced-only"},
+		Spec: v1beta1.ReleasePolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tenant": "true"},
+			},
+			Rules: []v1beta1.ReleasePolicyRule{
+				{
+					Name:       "namespace",
+					Expression: "has(object.metadata.namespace) && object.metadata.namespace == release.targetNamespace",
+				},
+			},
+		},
+	}
+
+	type args struct {
+		kube client.Client
+		cr   *v1beta1.Release
+		objs []*unstructured.Unstructured
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"FailedToListPolicies": {
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cr:   helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToListPolicies),
+			},
+		},
+		"NoPolicies": {
+			args: args{
+				kube: &test.MockClient{MockList: policyList()},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testPod("privileged", true)},
+			},
+		},
+		"Compliant": {
+			args: args{
+				kube: &test.MockClient{MockList: policyList(noPrivileged)},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testPod("app", false), testClusterRole()},
+			},
+		},
+		"Violation": {
+			args: args{
+				kube: &test.MockClient{MockList: policyList(noPrivileged)},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testPod("app", false), testPod("privileged", true)},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "no-privileged",
+						Rule:    "containers",
+						Object:  "Pod/" + testNamespace + "/privileged",
+						Message: "privileged containers are not allowed",
+					},
+				}},
+			},
+		},
+		"OtherGroup": {
+			args: args{
+				kube: &test.MockClient{MockList: policyList(noPrivileged)},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{func() *unstructured.Unstructured {
+					o := testPod("privileged", true)
+					o.SetAPIVersion("example.org/v1")
+					return o
+				}()},
+			},
+		},
+		"SelectorDoesNotMatch": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: policyList(namespacedOnly),
+					MockGet:  namespaceWithLabels(nil),
+				},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.SetLabels(map[string]string{"tenant": "true"})
+				}),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+		},
+		"SelectorMatches": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: policyList(namespacedOnly),
+					MockGet:  namespaceWithLabels(map[string]string{"tenant": "true"}),
+				},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testPod("app", false), testClusterRole()},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "namespaced-only",
+						Rule:    "namespace",
+						Object:  "ClusterRole/admin",
+						Message: "has(object.metadata.namespace) && object.metadata.namespace == release.targetNamespace",
+					},
+				}},
+			},
+		},
+		"RelabelledReleaseDoesNotEscape": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: policyList(namespacedOnly),
+					MockGet:  namespaceWithLabels(map[string]string{"tenant": "true"}),
+				},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.SetLabels(map[string]string{"tenant": "false"})
+				}),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "namespaced-only",
+						Rule:    "namespace",
+						Object:  "ClusterRole/admin",
+						Message: "has(object.metadata.namespace) && object.metadata.namespace == release.targetNamespace",
+					},
+				}},
+			},
+		},
+		"FailedToGetNamespace": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: policyList(namespacedOnly),
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToGetNamespace),
+			},
+		},
+		"InvalidExpression": {
+			args: args{
+				kube: &test.MockClient{MockList: policyList(v1beta1.ReleasePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "invalid"},
+					Spec: v1beta1.ReleasePolicySpec{
+						Rules: []v1beta1.ReleasePolicyRule{{Name: "string", Expression: "'not a bool'"}},
+					},
+				})},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errPolicyRuleNotBool), errFailedToCompilePolicyTmpl, "string", "invalid"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := policyValidator{ctx: context.Background(), kube: tc.args.kube, cr: tc.args.cr}
+			gotErr := v.Validate(tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Errorf("Validate(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package release
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	kubeclient "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/client"
 	kconfig "github.com/crossplane-contrib/provider-kubernetes/pkg/kube/config"
@@ -10,14 +14,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	helmcommon "helm.sh/helm/v4/pkg/release/common"
 	release "helm.sh/helm/v4/pkg/release/v1"
 	"helm.sh/helm/v4/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/types"
@@ -32,11 +42,13 @@ import (
 	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
 	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
 	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/throttle"
 )
 
 const (
 	providerName    = "helm-test"
 	testReleaseName = "test-release"
+	testTarget      = "test-target"
 )
 
 type helmReleaseModifier func(release *v1beta1.Release)
@@ -76,7 +88,8 @@ type MockGetLastReleaseFn func(release string) (*release.Release, error)
 type MockInstallFn func(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error)
 type MockUpgradeFn func(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error)
 type MockRollBackFn func(release string) error
-type MockUninstallFn func(release string) error
+type MockUninstallFn func(release string) ([]string, error)
+type MockTestFn func(release string) (*release.Release, error)
 type MockPullAndLoadChartFn func(mg resource.Managed, creds *helmClient.RepoCreds) (*chart.Chart, error)
 
 type MockHelmClient struct {
@@ -85,6 +98,7 @@ type MockHelmClient struct {
 	MockUpgrade          MockUpgradeFn
 	MockRollBack         MockRollBackFn
 	MockUninstall        MockUninstallFn
+	MockTest             MockTestFn
 	MockPullAndLoadChart MockPullAndLoadChartFn
 }
 
@@ -104,10 +118,14 @@ func (c *MockHelmClient) Rollback(release string) error {
 	return c.MockRollBack(release)
 }
 
-func (c *MockHelmClient) Uninstall(release string) error {
+func (c *MockHelmClient) Uninstall(release string) ([]string, error) {
 	return c.MockUninstall(release)
 }
 
+func (c *MockHelmClient) Test(release string) (*release.Release, error) {
+	return c.MockTest(release)
+}
+
 func (c *MockHelmClient) PullAndLoadChart(mg resource.Managed, creds *helmClient.RepoCreds) (*chart.Chart, error) {
 	if c.MockPullAndLoadChart != nil {
 		return c.MockPullAndLoadChart(mg, creds)
@@ -122,15 +140,17 @@ type notHelmRelease struct {
 func Test_connector_Connect(t *testing.T) {
 	providerConfig := helmv1beta1.ProviderConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: providerName},
-		Spec: kconfig.ProviderConfigSpec{
-			Credentials: kconfig.ProviderCredentials{
-				Source: xpv2.CredentialsSourceNone,
-			},
-			Identity: &kconfig.Identity{
-				Type: kconfig.IdentityTypeGoogleApplicationCredentials,
-				ProviderCredentials: kconfig.ProviderCredentials{
+		Spec: helmv1beta1.ProviderConfigSpec{
+			ProviderConfigSpec: kconfig.ProviderConfigSpec{
+				Credentials: kconfig.ProviderCredentials{
 					Source: xpv2.CredentialsSourceNone,
 				},
+				Identity: &kconfig.Identity{
+					Type: kconfig.IdentityTypeGoogleApplicationCredentials,
+					ProviderCredentials: kconfig.ProviderCredentials{
+						Source: xpv2.CredentialsSourceNone,
+					},
+				},
 			},
 		},
 	}
@@ -148,15 +168,17 @@ func Test_connector_Connect(t *testing.T) {
 
 	providerConfigAzure := helmv1beta1.ProviderConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: providerName},
-		Spec: kconfig.ProviderConfigSpec{
-			Credentials: kconfig.ProviderCredentials{
-				Source: xpv2.CredentialsSourceNone,
-			},
-			Identity: &kconfig.Identity{
-				Type: kconfig.IdentityTypeAzureServicePrincipalCredentials,
-				ProviderCredentials: kconfig.ProviderCredentials{
+		Spec: helmv1beta1.ProviderConfigSpec{
+			ProviderConfigSpec: kconfig.ProviderConfigSpec{
+				Credentials: kconfig.ProviderCredentials{
 					Source: xpv2.CredentialsSourceNone,
 				},
+				Identity: &kconfig.Identity{
+					Type: kconfig.IdentityTypeAzureServicePrincipalCredentials,
+					ProviderCredentials: kconfig.ProviderCredentials{
+						Source: xpv2.CredentialsSourceNone,
+					},
+				},
 			},
 		},
 	}
@@ -349,16 +371,112 @@ func Test_connector_Connect(t *testing.T) {
 	}
 }
 
+// upgradePendingSince returns the condition of an upgrade that is pending
+// since the supplied time, and never gets to run.
+func upgradePendingSince(t time.Time) xpv2.Condition {
+	c := upgradePending(time.Time{})
+	c.LastTransitionTime = metav1.NewTime(t)
+	return c
+}
+
+func Test_connector_ConnectProviderConfigsOfOneTarget(t *testing.T) {
+	one, four := 1, 4
+	qps := kresource.MustParse("5")
+	pcs := map[string]helmv1beta1.ClusterProviderConfig{
+		"strict": {
+			ObjectMeta: metav1.ObjectMeta{Name: "strict", UID: "strict"},
+			Spec:       helmv1beta1.ProviderConfigSpec{Limits: &helmv1beta1.TargetLimits{MaxConcurrentOperations: &one}},
+		},
+		"lax": {
+			ObjectMeta: metav1.ObjectMeta{Name: "lax", UID: "lax"},
+			Spec:       helmv1beta1.ProviderConfigSpec{Limits: &helmv1beta1.TargetLimits{MaxConcurrentOperations: &four, QPS: &qps}},
+		},
+	}
+	var last *rest.Config
+	c := &connector{
+		logger: logging.NewNopLogger(),
+		client: &test.MockClient{
+			MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				pc, ok := pcs[key.Name]
+				if !ok {
+					return errBoom
+				}
+				*obj.(*helmv1beta1.ClusterProviderConfig) = pc
+				return nil
+			},
+			MockScheme: func() *runtime.Scheme {
+				s := runtime.NewScheme()
+				if err := namespacedapis.AddToScheme(s); err != nil {
+					t.Fatal(err)
+				}
+				return s
+			},
+		},
+		usage: resource.ModernTrackerFn(func(context.Context, resource.ModernManaged) error { return nil }),
+		clientBuilder: kubeclient.BuilderFn(func(context.Context, kconfig.ProviderConfigSpec) (client.Client, *rest.Config, error) {
+			return &test.MockClient{}, &rest.Config{Host: "https://target.example.org"}, nil
+		}),
+		newHelmClientFn: func(_ logging.Logger, rc *rest.Config, _ ...helmClient.ArgsApplier) (helmClient.Client, error) {
+			last = rc
+			return &MockHelmClient{}, nil
+		},
+		throttle:    throttle.NewRegistry(throttle.Limits{}),
+		connections: helmClient.NewConnectionCache(),
+	}
+
+	connect := func(pc string) *helmExternal {
+		t.Helper()
+		cr := helmRelease(func(r *v1beta1.Release) {
+			r.SetUID(pcs[pc].UID + "-release")
+			r.Spec.ProviderConfigReference.Name = pc
+		})
+		e, err := c.Connect(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("Connect(...): %v", err)
+		}
+		return e.(*helmExternal)
+	}
+	connect("strict")
+	lax := connect("lax")
+	laxConfig := last
+	strict := connect("strict")
+
+	// Both provider configs target the same cluster, which is limited by the
+	// strictest of their limits whichever connected last, and share its rate
+	// limiter.
+	want := throttle.Limits{MaxConcurrentOperations: 1, QPS: 5, Burst: 5}
+	for name, e := range map[string]*helmExternal{"strict": strict, "lax": lax} {
+		if diff := cmp.Diff(want, e.limits); diff != "" {
+			t.Errorf("Connect(...): %s: -want limits, +got limits: %s", name, diff)
+		}
+	}
+	if last.RateLimiter == nil || last.RateLimiter != laxConfig.RateLimiter {
+		t.Errorf("Connect(...): want the rate limiter of the target shared by the connections of both provider configs")
+	}
+	if strict.target != lax.target {
+		t.Errorf("Connect(...): want the same target for provider configs of the same cluster, got %q and %q", strict.target, lax.target)
+	}
+
+	if !strict.acquireSlot(helmRelease(func(r *v1beta1.Release) { r.SetUID("strict-release") })) {
+		t.Errorf("acquireSlot(...): want the first release to acquire the only slot of the target")
+	}
+	if lax.acquireSlot(helmRelease(func(r *v1beta1.Release) { r.SetUID("lax-release") })) {
+		t.Errorf("acquireSlot(...): want the release of the other provider config to wait for the only slot of the target")
+	}
+}
+
 func Test_helmExternal_Observe(t *testing.T) {
 	type args struct {
 		localKube client.Client
 		kube      client.Client
 		helm      helmClient.Client
+		throttle  *throttle.Registry
 		mg        resource.Managed
 	}
 	type want struct {
-		out managed.ExternalObservation
-		err error
+		out   managed.ExternalObservation
+		err   error
+		ready *xpv2.Condition
 	}
 	cases := map[string]struct {
 		args
@@ -388,6 +506,46 @@ func Test_helmExternal_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NoHelmReleaseExists_WaitingForDependencies": {
+			args: args{
+				localKube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "releases"}, "cert-manager")),
+				},
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return nil, driver.ErrReleaseNotFound
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.DependsOn = []v1beta1.ReleaseReference{{Name: "cert-manager"}}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				ready: func() *xpv2.Condition {
+					c := waitingForDependencies([]string{testNamespace + "/cert-manager"})
+					return &c
+				}(),
+			},
+		},
+		"NoHelmReleaseExists_Throttled": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return nil, driver.ErrReleaseNotFound
+					},
+				},
+				throttle: func() *throttle.Registry {
+					r := throttle.NewRegistry(throttle.Limits{})
+					r.TryAcquire(testTarget, "other-release", throttle.Limits{MaxConcurrentOperations: 1})
+					return r
+				}(),
+				mg: helmRelease(),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
 		"FailedToGetLastRelease": {
 			args: args{
 				localKube: nil,
@@ -438,6 +596,24 @@ func Test_helmExternal_Observe(t *testing.T) {
 				out: managed.ExternalObservation{ResourceExists: true},
 			},
 		},
+		"ReleaseUninstalledWithHistory": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{Info: &release.Info{Status: helmcommon.StatusUninstalled}}, nil
+					},
+				},
+				mg: helmRelease(
+					func(release *v1beta1.Release) {
+						now := metav1.Now()
+						release.SetDeletionTimestamp(&now)
+					},
+				),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
 		"FailedToCheckIsUpToDate": {
 			args: args{
 				localKube: nil,
@@ -511,6 +687,117 @@ func Test_helmExternal_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"UpgradeDeferredOutsideMaintenanceWindow": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    testChart,
+									Version: "0.9.0",
+								},
+							},
+						}, nil
+					},
+				},
+				// The upgrade is deferred without waiting for a slot.
+				throttle: func() *throttle.Registry {
+					r := throttle.NewRegistry(throttle.Limits{})
+					r.TryAcquire(testTarget, "other-release", throttle.Limits{MaxConcurrentOperations: 1})
+					return r
+				}(),
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.MaintenanceWindows = []v1beta1.MaintenanceWindow{
+						{Schedule: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+					}
+					r.Status.SetConditions(upgradePendingSince(time.Unix(0, 0)))
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				ready: func() *xpv2.Condition {
+					c := upgradePendingSince(time.Unix(0, 0))
+					return &c
+				}(),
+			},
+		},
+		"AdoptLateInitialized": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    testChart,
+									Version: testVersion,
+								},
+							},
+							Config: map[string]interface{}{"replicas": 2},
+						}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Version = ""
+					r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true, LateInitializeValues: true}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true, ConnectionDetails: managed.ConnectionDetails{}},
+			},
+		},
+		"AdoptDiffNotAccepted": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    testChart,
+									Version: "0.9.0",
+								},
+							},
+						}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"AdoptDiffAccepted": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    testChart,
+									Version: "0.9.0",
+								},
+							},
+						}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Adopt = &v1beta1.AdoptSpec{Enabled: true, AcceptDiff: true}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: managed.ConnectionDetails{}},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -519,6 +806,9 @@ func Test_helmExternal_Observe(t *testing.T) {
 				localKube: tc.args.localKube,
 				kube:      tc.args.kube,
 				helm:      tc.args.helm,
+				throttle:  tc.args.throttle,
+				target:    testTarget,
+				limits:    throttle.Limits{MaxConcurrentOperations: 1},
 			}
 			got, gotErr := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -528,10 +818,104 @@ func Test_helmExternal_Observe(t *testing.T) {
 			if diff := cmp.Diff(tc.want.out, got); diff != "" {
 				t.Fatalf("e.Observe(...): -want out, +got out: %s", diff)
 			}
+			if tc.want.ready == nil {
+				return
+			}
+			if diff := cmp.Diff(*tc.want.ready, tc.args.mg.GetCondition(xpv2.TypeReady), cmpopts.IgnoreFields(xpv2.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("e.Observe(...): -want ready condition, +got ready condition: %s", diff)
+			}
 		})
 	}
 }
 
+// testGitBranch returns a bare Git repository and a function that advances
+// its master branch by a commit, returning the new commit.
+func testGitBranch(t *testing.T) (string, func() string) {
+	t.Helper()
+	dir := t.TempDir()
+	bare := filepath.Join(dir, "charts.git")
+	if _, err := git.PlainInit(bare, true); err != nil {
+		t.Fatal(err)
+	}
+	work := filepath.Join(dir, "work")
+	r, err := git.PlainInit(work, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateRemote(&gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{bare}}); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	return bare, func() string {
+		n++
+		if err := os.WriteFile(filepath.Join(work, "Chart.yaml"), []byte(fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 0.%d.0\n", testChart, n)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add("Chart.yaml"); err != nil {
+			t.Fatal(err)
+		}
+		h, err := wt.Commit("Advance", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(int64(n), 0)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Push(&git.PushOptions{RefSpecs: []gitconfig.RefSpec{"refs/heads/master:refs/heads/master"}}); err != nil {
+			t.Fatal(err)
+		}
+		return h.String()
+	}
+}
+
+func Test_helmExternal_ObserveGitBranchAdvanced(t *testing.T) {
+	repo, advance := testGitBranch(t)
+	deployed := advance()
+
+	e := &helmExternal{
+		logger: logging.NewNopLogger(),
+		helm: &MockHelmClient{
+			MockGetLastRelease: func(r string) (*release.Release, error) {
+				return &release.Release{
+					Name: r,
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Metadata: &chart.Metadata{
+							Name:        testChart,
+							Version:     testVersion,
+							Annotations: map[string]string{helmClient.AnnotationGitCommit: deployed, helmClient.AnnotationGitRef: "master"},
+						},
+					},
+					Config: map[string]interface{}{},
+				}, nil
+			},
+		},
+		target: testTarget,
+		limits: throttle.Limits{MaxConcurrentOperations: 1},
+	}
+	mg := helmRelease(func(r *v1beta1.Release) {
+		r.Spec.ForProvider.Chart.Git = &v1beta1.GitChartSource{URL: repo, Ref: "master"}
+	})
+
+	got, err := e.Observe(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a release deployed from the head of its branch should be up to date")
+	}
+
+	advance()
+	got, err = e.Observe(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a release should be upgraded when its branch advanced")
+	}
+}
+
 func Test_helmExternal_Create(t *testing.T) {
 	type args struct {
 		localKube client.Client
@@ -541,7 +925,8 @@ func Test_helmExternal_Create(t *testing.T) {
 		updateFn  func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
 	}
 	type want struct {
-		err error
+		err        error
+		violations []v1beta1.PolicyViolation
 	}
 	cases := map[string]struct {
 		args
@@ -702,6 +1087,54 @@ func Test_helmExternal_Create(t *testing.T) {
 				err: nil,
 			},
 		},
+		"PolicyViolation": {
+			args: args{
+				helm: &MockHelmClient{
+					MockInstall: func(r string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
+						return nil, errors.Wrap(&policyViolationError{violations: []v1beta1.PolicyViolation{
+							{Policy: "p", Rule: "r", Object: "Pod/default/app", Message: "denied"},
+						}}, "error while running post render on files")
+					},
+				},
+				mg: helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(&policyViolationError{violations: []v1beta1.PolicyViolation{
+					{Policy: "p", Rule: "r", Object: "Pod/default/app", Message: "denied"},
+				}}, "error while running post render on files"), errFailedToInstall),
+				violations: []v1beta1.PolicyViolation{
+					{Policy: "p", Rule: "r", Object: "Pod/default/app", Message: "denied"},
+				},
+			},
+		},
+		"TargetNamespaceNotAllowed": {
+			args: args{
+				localKube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						obj.(*v1beta1.ReleaseTenancyPolicyList).Items = []v1beta1.ReleaseTenancyPolicy{
+							{ObjectMeta: metav1.ObjectMeta{Name: "tenancy"}},
+						}
+						return nil
+					}),
+				},
+				helm: &MockHelmClient{
+					MockInstall: func(r string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Namespace = "kube-system"
+				}),
+			},
+			want: want{
+				err: errors.Wrap(&policyViolationError{violations: []v1beta1.PolicyViolation{
+					{Policy: "tenancy", Rule: tenancyRuleTargetNamespace, Object: objectTargetNamespace, Message: `namespace "kube-system" is not allowed`},
+				}}, errFailedToInstall),
+				violations: []v1beta1.PolicyViolation{
+					{Policy: "tenancy", Rule: tenancyRuleTargetNamespace, Object: objectTargetNamespace, Message: `namespace "kube-system" is not allowed`},
+				},
+			},
+		},
 		"CreateNamespaceSuccess": {
 			args: args{
 				kube: &test.MockClient{
@@ -731,8 +1164,9 @@ func Test_helmExternal_Create(t *testing.T) {
 				helm:      tc.args.helm,
 				patch:     newPatcher(),
 			}
-			if tc.args.updateFn != nil {
+			if e.localKube == nil {
 				e.localKube = &test.MockClient{
+					MockList:   test.NewMockListFn(nil),
 					MockUpdate: tc.args.updateFn,
 				}
 			}
@@ -740,6 +1174,11 @@ func Test_helmExternal_Create(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("e.Create(...): -want error, +got error: %s", diff)
 			}
+			if cr, ok := tc.args.mg.(*v1beta1.Release); ok {
+				if diff := cmp.Diff(tc.want.violations, cr.Status.PolicyViolations); diff != "" {
+					t.Errorf("e.Create(...): -want policy violations, +got policy violations: %s", diff)
+				}
+			}
 		})
 	}
 }
@@ -749,6 +1188,7 @@ func Test_helmExternal_Update(t *testing.T) {
 		localKube client.Client
 		kube      client.Client
 		helm      helmClient.Client
+		redaction *helmClient.Redaction
 		mg        resource.Managed
 	}
 	type want struct {
@@ -769,8 +1209,8 @@ func Test_helmExternal_Update(t *testing.T) {
 		"RetryUninstallFails": {
 			args: args{
 				helm: &MockHelmClient{
-					MockUninstall: func(release string) error {
-						return errBoom
+					MockUninstall: func(release string) ([]string, error) {
+						return nil, errBoom
 					},
 				},
 				mg: helmRelease(func(r *v1beta1.Release) {
@@ -804,6 +1244,26 @@ func Test_helmExternal_Update(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"RetryRollbackFailsRedacted": {
+			args: args{
+				helm: &MockHelmClient{
+					MockRollBack: func(release string) error {
+						return errors.New("rendered password s3cr3t is invalid")
+					},
+				},
+				redaction: &helmClient.Redaction{Secrets: []string{"s3cr3t"}},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					l := int32(3)
+					r.Spec.RollbackRetriesLimit = &l
+					r.Status.Synced = true
+					r.Status.AtProvider.Revision = 3
+					r.Status.AtProvider.State = helmcommon.StatusFailed
+				}),
+			},
+			want: want{
+				err: errors.New("rendered password REDACTED is invalid"),
+			},
+		},
 		"RetryRollbackSuccess": {
 			args: args{
 				helm: &MockHelmClient{
@@ -886,6 +1346,7 @@ func Test_helmExternal_Update(t *testing.T) {
 				localKube: tc.args.localKube,
 				kube:      tc.args.kube,
 				helm:      tc.args.helm,
+				redaction: tc.args.redaction,
 				patch:     newPatcher(),
 			}
 			_, gotErr := e.Update(context.Background(), tc.args.mg)
@@ -896,6 +1357,93 @@ func Test_helmExternal_Update(t *testing.T) {
 	}
 }
 
+func Test_helmExternal_test(t *testing.T) {
+	testHook := func(phase release.HookPhase) *release.Hook {
+		return &release.Hook{Name: "test-connection", Events: []release.HookEvent{release.HookTest}, LastRun: release.HookExecution{StartedAt: time.Now(), Phase: phase}}
+	}
+	withTests := func(r *v1beta1.Release) {
+		r.Spec.ForProvider.RunTests = true
+		r.Status.AtProvider.Revision = 2
+	}
+
+	type args struct {
+		helm      helmClient.Client
+		redaction *helmClient.Redaction
+		cr        *v1beta1.Release
+	}
+	cases := map[string]struct {
+		args
+		want *v1beta1.ReleaseTestObservation
+	}{
+		"Disabled": {
+			args: args{
+				helm: &MockHelmClient{},
+				cr:   helmRelease(),
+			},
+		},
+		"Succeeded": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTest: func(_ string) (*release.Release, error) {
+						return &release.Release{Version: 2, Hooks: []*release.Hook{testHook(release.HookPhaseSucceeded)}}, nil
+					},
+				},
+				cr: helmRelease(withTests),
+			},
+			want: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestSucceeded},
+		},
+		"NoTests": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTest: func(_ string) (*release.Release, error) {
+						return &release.Release{Version: 2}, nil
+					},
+				},
+				cr: helmRelease(withTests),
+			},
+			want: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestSucceeded, Message: msgNoTests},
+		},
+		"Failed": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTest: func(_ string) (*release.Release, error) {
+						return &release.Release{Version: 2, Hooks: []*release.Hook{testHook(release.HookPhaseFailed)}}, errBoom
+					},
+				},
+				cr: helmRelease(withTests),
+			},
+			want: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestFailed, Message: "test test-connection failed"},
+		},
+		"FailedToRun": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTest: func(_ string) (*release.Release, error) {
+						return nil, errors.New("cannot reach s3cr3t.example.org")
+					},
+				},
+				redaction: &helmClient.Redaction{Secrets: []string{"s3cr3t"}},
+				cr:        helmRelease(withTests),
+			},
+			want: &v1beta1.ReleaseTestObservation{Revision: 2, Phase: v1beta1.TestFailed, Message: "cannot reach REDACTED.example.org"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &helmExternal{
+				logger:    logging.NewNopLogger(),
+				helm:      tc.args.helm,
+				redaction: tc.args.redaction,
+			}
+			e.test(tc.args.cr)
+			if diff := cmp.Diff(tc.want, tc.args.cr.Status.AtProvider.Test); diff != "" {
+				t.Errorf("e.test(...): -want test observation, +got test observation: %s", diff)
+			}
+			if c := tc.args.cr.Status.GetCondition(typeDeployed); c.Reason == xpv2.ConditionReason(reasonTestFailed) {
+				t.Errorf("e.test(...): tests must not set the %s condition", typeDeployed)
+			}
+		})
+	}
+}
 func Test_helmExternal_Delete(t *testing.T) {
 	type args struct {
 		localKube client.Client
@@ -904,7 +1452,8 @@ func Test_helmExternal_Delete(t *testing.T) {
 		mg        resource.Managed
 	}
 	type want struct {
-		err error
+		err  error
+		kept []string
 	}
 	cases := map[string]struct {
 		args
@@ -921,8 +1470,8 @@ func Test_helmExternal_Delete(t *testing.T) {
 		"FailedToUninstall": {
 			args: args{
 				helm: &MockHelmClient{
-					MockUninstall: func(release string) error {
-						return errBoom
+					MockUninstall: func(release string) ([]string, error) {
+						return nil, errBoom
 					},
 				},
 				mg: helmRelease(),
@@ -934,8 +1483,8 @@ func Test_helmExternal_Delete(t *testing.T) {
 		"Success": {
 			args: args{
 				helm: &MockHelmClient{
-					MockUninstall: func(release string) error {
-						return nil
+					MockUninstall: func(release string) ([]string, error) {
+						return nil, nil
 					},
 				},
 				mg: helmRelease(),
@@ -944,6 +1493,29 @@ func Test_helmExternal_Delete(t *testing.T) {
 				err: nil,
 			},
 		},
+		"DeletionProtected": {
+			args: args{
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.DeletionProtection = true
+				}),
+			},
+			want: want{
+				err: errors.New(errDeletionProtected),
+			},
+		},
+		"SuccessKeptResources": {
+			args: args{
+				helm: &MockHelmClient{
+					MockUninstall: func(release string) ([]string, error) {
+						return []string{"PersistentVolumeClaim/data"}, nil
+					},
+				},
+				mg: helmRelease(),
+			},
+			want: want{
+				kept: []string{"PersistentVolumeClaim/data"},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -957,6 +1529,38 @@ func Test_helmExternal_Delete(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
 			}
+			if cr, ok := tc.args.mg.(*v1beta1.Release); ok {
+				if diff := cmp.Diff(tc.want.kept, cr.Status.KeptResources); diff != "" {
+					t.Errorf("e.Delete(...): -want kept resources, +got kept resources: %s", diff)
+				}
+			}
 		})
 	}
 }
+
+func Test_helmExternal_Disconnect(t *testing.T) {
+	l := throttle.Limits{MaxConcurrentOperations: 1}
+	r := throttle.NewRegistry(throttle.Limits{})
+	release, _ := r.TryAcquire(testTarget, "holder", l)
+	defer release()
+	r.TryAcquire(testTarget, "gone", l)
+	r.TryAcquire(testTarget, "waiting", l)
+
+	// A Release queued in this reconcile keeps its place.
+	e := &helmExternal{throttle: r, target: testTarget, limits: l, id: "waiting", queued: true}
+	if err := e.Disconnect(context.Background()); err != nil {
+		t.Fatalf("e.Disconnect(...): %s", err)
+	}
+	if _, position := r.TryAcquire(testTarget, "waiting", l); position != 2 {
+		t.Errorf("TryAcquire(...): want position 2, got %d", position)
+	}
+
+	// A Release that no longer tries to acquire a slot leaves the queue.
+	e = &helmExternal{throttle: r, target: testTarget, limits: l, id: "gone"}
+	if err := e.Disconnect(context.Background()); err != nil {
+		t.Fatalf("e.Disconnect(...): %s", err)
+	}
+	if _, position := r.TryAcquire(testTarget, "waiting", l); position != 1 {
+		t.Errorf("TryAcquire(...): want position 1, got %d", position)
+	}
+}
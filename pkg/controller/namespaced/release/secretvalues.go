@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	errFailedToGetEncryptionKey    = "failed to get encryption key"
+	errMissingEncryptionKeyTmpl    = "missing key %q in encryption key secret"
+	errFailedToHashValues          = "failed to compute values sha"
+	errFailedToComposeSecretValues = "failed to compose secret values"
+	errRedactWithoutEncryption     = "redacting secret values requires an encryption key, since the manifest stored by Helm is rendered from them"
+)
+
+func redactSecretValues(cr *v1beta1.Release) bool {
+	return cr.Spec.ForProvider.SecretValues != nil && cr.Spec.ForProvider.SecretValues.Redact
+}
+
+// newRedaction returns the redaction of the values sourced from Secrets, or nil
+// if they are not to be redacted.
+func newRedaction(ctx context.Context, kube client.Client, cr *v1beta1.Release) (*helmClient.Redaction, error) {
+	if !redactSecretValues(cr) {
+		return nil, nil
+	}
+	if cr.Spec.ForProvider.SecretValues.EncryptionKeySecretRef == nil {
+		return nil, errors.New(errRedactWithoutEncryption)
+	}
+	vals, err := composeValuesFromSpec(ctx, kube, cr.Spec.ForProvider.ValuesSpec, cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	redacted, err := composeRedactedValuesFromSpec(ctx, kube, cr.Spec.ForProvider.ValuesSpec, cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &helmClient.Redaction{
+		Values:  normalizeConfig(redacted),
+		Secrets: redactedLeaves(vals, redacted),
+	}, nil
+}
+
+// redactedLeaves returns the values of all leaves that are redacted.
+func redactedLeaves(vals, redacted interface{}) []string {
+	switch r := redacted.(type) {
+	case map[string]interface{}:
+		v, ok := vals.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var out []string
+		for k := range r {
+			out = append(out, redactedLeaves(v[k], r[k])...)
+		}
+		return out
+	case []interface{}:
+		v, ok := vals.([]interface{})
+		if !ok {
+			return nil
+		}
+		var out []string
+		for i := range r {
+			if i < len(v) {
+				out = append(out, redactedLeaves(v[i], r[i])...)
+			}
+		}
+		return out
+	case string:
+		if r != redactedValue {
+			return nil
+		}
+		return leaves(vals)
+	}
+	return nil
+}
+
+func leaves(v interface{}) []string {
+	var out []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, v := range v {
+			out = append(out, leaves(v)...)
+		}
+	case []interface{}:
+		for _, v := range v {
+			out = append(out, leaves(v)...)
+		}
+	default:
+		out = append(out, fmt.Sprint(v))
+	}
+	return out
+}
+
+// encryptionKey returns the key used to encrypt the Helm release storage, or
+// nil if it is not to be encrypted.
+func encryptionKey(ctx context.Context, kube client.Client, cr *v1beta1.Release) ([]byte, error) {
+	sv := cr.Spec.ForProvider.SecretValues
+	if sv == nil || sv.EncryptionKeySecretRef == nil {
+		return nil, nil
+	}
+	ref := sv.EncryptionKeySecretRef
+	d, err := getSecretData(ctx, kube, types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace})
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetEncryptionKey)
+	}
+	key, ok := d[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errMissingEncryptionKeyTmpl, ref.Key)
+	}
+	return key, nil
+}
+
+// valuesSha returns the hash of the supplied values, normalized like Helm
+// stores them.
+func valuesSha(vals map[string]interface{}) (string, error) {
+	b, err := json.Marshal(normalizeConfig(vals))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+func withSecretValues(r *helmClient.Redaction, key []byte) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Redaction = r
+		config.EncryptionKey = key
+	}
+}
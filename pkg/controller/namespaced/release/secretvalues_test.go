@@ -0,0 +1,230 @@
+package release
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+func Test_newRedaction(t *testing.T) {
+	getSecrets := func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{
+			"password":    []byte("s3cr3t"),
+			"values.yaml": []byte("db:\n  user: admin\n  port: 5432\n"),
+		}}
+		return nil
+	}
+	keyRef := &xpv2.LocalSecretKeySelector{
+		LocalSecretReference: xpv2.LocalSecretReference{Name: testSecretName},
+		Key:                  "key",
+	}
+
+	type args struct {
+		kube client.Client
+		cr   *v1beta1.Release
+	}
+	type want struct {
+		r   *helmClient.Redaction
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Disabled": {
+			args: args{
+				cr: helmRelease(),
+			},
+		},
+		"Redacted": {
+			args: args{
+				kube: &test.MockClient{MockGet: getSecrets},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.SecretValues = &v1beta1.SecretValuesSpec{Redact: true, EncryptionKeySecretRef: keyRef}
+					r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"replicas": 2}`)}
+					r.Spec.ForProvider.ValuesFrom = []v1beta1.ValueFromSource{{
+						SecretKeyRef: &v1beta1.DataKeySelector{Name: testSecretName},
+					}}
+					r.Spec.ForProvider.Set = []v1beta1.SetVal{{
+						Name: "password",
+						ValueFrom: &v1beta1.ValueFromSource{
+							SecretKeyRef: &v1beta1.DataKeySelector{Name: testSecretName, Key: "password"},
+						},
+					}}
+				}),
+			},
+			want: want{
+				r: &helmClient.Redaction{
+					Values: map[string]interface{}{
+						"replicas": float64(2),
+						"password": redactedValue,
+						"db": map[string]interface{}{
+							"user": redactedValue,
+							"port": redactedValue,
+						},
+					},
+					Secrets: []string{"5432", "admin", "s3cr3t"},
+				},
+			},
+		},
+		"RedactedWithoutEncryption": {
+			args: args{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.SecretValues = &v1beta1.SecretValuesSpec{Redact: true}
+				}),
+			},
+			want: want{
+				err: errors.New(errRedactWithoutEncryption),
+			},
+		},
+		"FailedToComposeValues": {
+			args: args{
+				kube: &test.MockClient{MockGet: getSecrets},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.SecretValues = &v1beta1.SecretValuesSpec{Redact: true, EncryptionKeySecretRef: keyRef}
+					r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte("invalid-yaml")}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("error unmarshaling JSON: while decoding JSON: "+
+					"json: cannot unmarshal string into Go value of type map[string]interface {}"),
+					errFailedToUnmarshalDesiredValues),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := newRedaction(context.Background(), tc.args.kube, tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("newRedaction(...): -want error, +got error: %s", diff)
+			}
+			if r != nil {
+				sort.Strings(r.Secrets)
+			}
+			if diff := cmp.Diff(tc.want.r, r); diff != "" {
+				t.Errorf("newRedaction(...): -want redaction, +got redaction: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_redactedLeaves(t *testing.T) {
+	cases := map[string]struct {
+		vals     interface{}
+		redacted interface{}
+		want     []string
+	}{
+		"Leaf": {
+			vals:     map[string]interface{}{"password": "s3cr3t", "replicas": 2},
+			redacted: map[string]interface{}{"password": redactedValue, "replicas": 2},
+			want:     []string{"s3cr3t"},
+		},
+		"RedactedList": {
+			vals:     map[string]interface{}{"tokens": []interface{}{"a1", map[string]interface{}{"b": "b2"}}},
+			redacted: map[string]interface{}{"tokens": redactedValue},
+			want:     []string{"a1", "b2"},
+		},
+		"ListElements": {
+			vals:     map[string]interface{}{"users": []interface{}{map[string]interface{}{"name": "admin", "password": "s3cr3t"}}},
+			redacted: map[string]interface{}{"users": []interface{}{map[string]interface{}{"name": "admin", "password": redactedValue}}},
+			want:     []string{"s3cr3t"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := redactedLeaves(tc.vals, tc.redacted)
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("redactedLeaves(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_encryptionKey(t *testing.T) {
+	withKeyRef := func(r *v1beta1.Release) {
+		r.Spec.ForProvider.SecretValues = &v1beta1.SecretValuesSpec{
+			EncryptionKeySecretRef: &xpv2.LocalSecretKeySelector{
+				LocalSecretReference: xpv2.LocalSecretReference{Name: testSecretName},
+				Key:                  "key",
+			},
+		}
+	}
+
+	type args struct {
+		kube client.Client
+		cr   *v1beta1.Release
+	}
+	type want struct {
+		key []byte
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotConfigured": {
+			args: args{
+				cr: helmRelease(),
+			},
+		},
+		"Success": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Name != testSecretName || key.Namespace != testNamespace {
+						return errBoom
+					}
+					*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"key": []byte("0123456789abcdef0123456789abcdef")}}
+					return nil
+				}},
+				cr: helmRelease(withKeyRef),
+			},
+			want: want{
+				key: []byte("0123456789abcdef0123456789abcdef"),
+			},
+		},
+		"MissingKey": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"other": []byte("value")}}
+					return nil
+				}},
+				cr: helmRelease(withKeyRef),
+			},
+			want: want{
+				err: errors.Errorf(errMissingEncryptionKeyTmpl, "key"),
+			},
+		},
+		"FailedToGetSecret": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cr:   helmRelease(withKeyRef),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, errFailedToGetSecret, testNamespace), errFailedToGetEncryptionKey),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := encryptionKey(context.Background(), tc.args.kube, tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("encryptionKey(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.key, key); diff != "" {
+				t.Errorf("encryptionKey(...): -want key, +got key: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	errSQLStorageNotConfigured        = "sql must be configured for the SQL storage driver"
+	errFailedToGetSQLConnectionString = "failed to get SQL connection string"
+	errMissingSQLConnectionStringTmpl = "missing key %q in SQL connection string secret"
+)
+
+func storageDriver(cr *v1beta1.Release) v1beta1.StorageDriver {
+	if cr.Spec.ForProvider.Storage == nil {
+		return ""
+	}
+	return cr.Spec.ForProvider.Storage.Driver
+}
+
+// sqlConnectionString returns the connection string of the SQL storage driver,
+// or an empty string if the Release uses another driver.
+func sqlConnectionString(ctx context.Context, kube client.Client, cr *v1beta1.Release) (string, error) {
+	if storageDriver(cr) != v1beta1.StorageDriverSQL {
+		return "", nil
+	}
+	sql := cr.Spec.ForProvider.Storage.SQL
+	if sql == nil {
+		return "", errors.New(errSQLStorageNotConfigured)
+	}
+	ref := sql.ConnectionStringSecretRef
+	d, err := getSecretData(ctx, kube, types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace})
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToGetSQLConnectionString)
+	}
+	cs, ok := d[ref.Key]
+	if !ok {
+		return "", errors.Errorf(errMissingSQLConnectionStringTmpl, ref.Key)
+	}
+	return string(cs), nil
+}
+
+// withStorage configures the storage driver of the Release. Drivers storing
+// the release state of several target clusters keep it apart by the supplied
+// target, so that Releases of different provider configs for the same target
+// cluster share their state.
+func withStorage(cr *v1beta1.Release, connectionString, target string, controlPlane corev1client.SecretsGetter) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.StorageDriver = string(storageDriver(cr))
+		config.SQLConnectionString = connectionString
+		config.StorageScope = target
+		if storageDriver(cr) == v1beta1.StorageDriverControlPlane && controlPlane != nil {
+			config.ControlPlaneSecrets = controlPlane.Secrets(cr.Namespace)
+		}
+	}
+}
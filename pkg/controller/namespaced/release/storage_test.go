@@ -0,0 +1,158 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+func Test_sqlConnectionString(t *testing.T) {
+	withSQL := func(r *v1beta1.Release) {
+		r.Spec.ForProvider.Storage = &v1beta1.StorageSpec{
+			Driver: v1beta1.StorageDriverSQL,
+			SQL: &v1beta1.SQLStorageSpec{
+				ConnectionStringSecretRef: xpv2.LocalSecretKeySelector{
+					LocalSecretReference: xpv2.LocalSecretReference{Name: testSecretName},
+					Key:                  "dsn",
+				},
+			},
+		}
+	}
+
+	type args struct {
+		kube client.Client
+		cr   *v1beta1.Release
+	}
+	type want struct {
+		cs  string
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotSQL": {
+			args: args{
+				cr: helmRelease(),
+			},
+		},
+		"NotConfigured": {
+			args: args{
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Storage = &v1beta1.StorageSpec{Driver: v1beta1.StorageDriverSQL}
+				}),
+			},
+			want: want{
+				err: errors.New(errSQLStorageNotConfigured),
+			},
+		},
+		"Success": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Name != testSecretName || key.Namespace != testNamespace {
+						return errBoom
+					}
+					*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"dsn": []byte("postgres://helm@db/helm")}}
+					return nil
+				}},
+				cr: helmRelease(withSQL),
+			},
+			want: want{
+				cs: "postgres://helm@db/helm",
+			},
+		},
+		"MissingKey": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"other": []byte("value")}}
+					return nil
+				}},
+				cr: helmRelease(withSQL),
+			},
+			want: want{
+				err: errors.Errorf(errMissingSQLConnectionStringTmpl, "dsn"),
+			},
+		},
+		"FailedToGetSecret": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cr:   helmRelease(withSQL),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, errFailedToGetSecret, testNamespace), errFailedToGetSQLConnectionString),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cs, err := sqlConnectionString(context.Background(), tc.args.kube, tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("sqlConnectionString(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cs, cs); diff != "" {
+				t.Errorf("sqlConnectionString(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_withStorage(t *testing.T) {
+	controlPlane := fake.NewClientset().CoreV1()
+
+	cases := map[string]struct {
+		cr          *v1beta1.Release
+		wantDriver  string
+		wantSecrets bool
+	}{
+		"Default": {
+			cr: helmRelease(),
+		},
+		"ConfigMap": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Storage = &v1beta1.StorageSpec{Driver: v1beta1.StorageDriverConfigMap}
+			}),
+			wantDriver: helmClient.StorageDriverConfigMap,
+		},
+		"ControlPlane": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Storage = &v1beta1.StorageSpec{Driver: v1beta1.StorageDriverControlPlane}
+			}),
+			wantDriver:  helmClient.StorageDriverControlPlane,
+			wantSecrets: true,
+		},
+		"OtherProviderConfig": {
+			cr: helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ProviderConfigReference.Name = "other"
+				r.Spec.ForProvider.Storage = &v1beta1.StorageSpec{Driver: v1beta1.StorageDriverSQL}
+			}),
+			wantDriver: helmClient.StorageDriverSQL,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := &helmClient.Args{}
+			withStorage(tc.cr, "", testTarget, controlPlane)(args)
+			if diff := cmp.Diff(tc.wantDriver, args.StorageDriver); diff != "" {
+				t.Errorf("withStorage(...): -want driver, +got driver: %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSecrets, args.ControlPlaneSecrets != nil); diff != "" {
+				t.Errorf("withStorage(...): -want control plane secrets, +got control plane secrets: %s", diff)
+			}
+			// Releases of any provider config for the same target share
+			// their release state.
+			if diff := cmp.Diff(testTarget, args.StorageScope); diff != "" {
+				t.Errorf("withStorage(...): -want scope, +got scope: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	tenancyRuleTargetNamespace = "allowedNamespaces"
+	tenancyRuleAllowedKinds    = "allowedKinds"
+	tenancyRuleDeniedKinds     = "deniedKinds"
+
+	kindSelectorWildcard = "*"
+)
+
+const (
+	errFailedToListTenancyPolicies = "cannot list release tenancy policies"
+	errInvalidTenancySelectorTmpl  = "invalid namespace selector in release tenancy policy %q"
+
+	msgNamespaceNotAllowedTmpl = "namespace %q is not allowed"
+	msgKindNotAllowedTmpl      = "kind %q is not allowed"
+	msgKindDeniedTmpl          = "kind %q is denied"
+	objectTargetNamespace      = "spec.forProvider.namespace"
+)
+
+// tenancyValidator validates the target namespace and the rendered objects of
+// a Release against the ReleaseTenancyPolicies that apply to it.
+type tenancyValidator struct {
+	ctx  context.Context
+	kube client.Client
+	cr   *v1beta1.Release
+}
+
+func withTenancyValidator(ctx context.Context, kube client.Client, cr *v1beta1.Release) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Validators = append(config.Validators, tenancyValidator{ctx: ctx, kube: kube, cr: cr})
+	}
+}
+
+// Validate checks the target namespace of the Release and, if any, the
+// supplied rendered objects. It may be called without objects to check the
+// target namespace before anything is created on the target cluster.
+func (v tenancyValidator) Validate(objs []*unstructured.Unstructured) error {
+	pl := &v1beta1.ReleaseTenancyPolicyList{}
+	if err := v.kube.List(v.ctx, pl); err != nil {
+		return errors.Wrap(err, errFailedToListTenancyPolicies)
+	}
+	sort.Slice(pl.Items, func(i, j int) bool { return pl.Items[i].Name < pl.Items[j].Name })
+
+	target := targetNamespace(v.cr)
+
+	ns := &namespaceLabels{ctx: v.ctx, kube: v.kube, name: v.cr.Namespace}
+	var violations []v1beta1.PolicyViolation
+	for _, p := range pl.Items {
+		applies, err := tenancyPolicyApplies(p, ns)
+		if err != nil {
+			return err
+		}
+		if !applies {
+			continue
+		}
+
+		allowed := sets.New(p.Spec.AllowedNamespaces...).Insert(v.cr.Namespace)
+		if !allowed.Has(target) {
+			violations = append(violations, v1beta1.PolicyViolation{
+				Policy:  p.Name,
+				Rule:    tenancyRuleTargetNamespace,
+				Object:  objectTargetNamespace,
+				Message: fmt.Sprintf(msgNamespaceNotAllowedTmpl, target),
+			})
+		}
+
+		for _, o := range objs {
+			violations = append(violations, tenancyViolations(p, allowed, o)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &policyViolationError{violations: violations}
+	}
+	return nil
+}
+
+func tenancyPolicyApplies(p v1beta1.ReleaseTenancyPolicy, ns *namespaceLabels) (bool, error) {
+	if p.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+	if err != nil {
+		return false, errors.Wrapf(err, errInvalidTenancySelectorTmpl, p.Name)
+	}
+	return ns.matches(s)
+}
+
+// tenancyViolations returns the violations of a single rendered object.
+// Objects without a namespace are either cluster-scoped or installed into the
+// target namespace, which is checked separately, so only explicit namespaces
+// are checked here; cluster-scoped kinds are restricted through the kind
+// lists.
+func tenancyViolations(p v1beta1.ReleaseTenancyPolicy, allowedNamespaces sets.Set[string], o *unstructured.Unstructured) []v1beta1.PolicyViolation {
+	var violations []v1beta1.PolicyViolation
+	ref := objectRef(o)
+
+	if ns := o.GetNamespace(); ns != "" && !allowedNamespaces.Has(ns) {
+		violations = append(violations, v1beta1.PolicyViolation{
+			Policy:  p.Name,
+			Rule:    tenancyRuleTargetNamespace,
+			Object:  ref,
+			Message: fmt.Sprintf(msgNamespaceNotAllowedTmpl, ns),
+		})
+	}
+
+	gk := o.GroupVersionKind().GroupKind()
+	switch {
+	case matchesAnyKind(p.Spec.DeniedKinds, gk):
+		violations = append(violations, v1beta1.PolicyViolation{
+			Policy:  p.Name,
+			Rule:    tenancyRuleDeniedKinds,
+			Object:  ref,
+			Message: fmt.Sprintf(msgKindDeniedTmpl, gk.String()),
+		})
+	case len(p.Spec.AllowedKinds) > 0 && !matchesAnyKind(p.Spec.AllowedKinds, gk):
+		violations = append(violations, v1beta1.PolicyViolation{
+			Policy:  p.Name,
+			Rule:    tenancyRuleAllowedKinds,
+			Object:  ref,
+			Message: fmt.Sprintf(msgKindNotAllowedTmpl, gk.String()),
+		})
+	}
+
+	return violations
+}
+
+func matchesAnyKind(selectors []v1beta1.KindSelector, gk schema.GroupKind) bool {
+	for _, s := range selectors {
+		if (s.Group == kindSelectorWildcard || s.Group == gk.Group) &&
+			(s.Kind == kindSelectorWildcard || s.Kind == gk.Kind) {
+			return true
+		}
+	}
+	return false
+}
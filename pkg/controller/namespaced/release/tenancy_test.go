@@ -0,0 +1,212 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func tenancyPolicyList(p ...v1beta1.ReleaseTenancyPolicy) func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		list.(*v1beta1.ReleaseTenancyPolicyList).Items = p
+		return nil
+	}
+}
+
+func Test_tenancyValidator_Validate(t *testing.T) {
+	namespaceOnly := v1beta1.ReleaseTenancyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-only"},
+		Spec: v1beta1.ReleaseTenancyPolicySpec{
+			AllowedNamespaces: []string{"shared"},
+			DeniedKinds: []v1beta1.KindSelector{
+				{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+				{Group: "apiextensions.k8s.io", Kind: "*"},
+			},
+		},
+	}
+	workloadsOnly := v1beta1.ReleaseTenancyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "workloads-only"},
+		Spec: v1beta1.ReleaseTenancyPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tenant": "true"},
+			},
+			AllowedKinds: []v1beta1.KindSelector{
+				{Kind: "Pod"},
+				{Group: "apps", Kind: "*"},
+			},
+		},
+	}
+
+	type args struct {
+		kube client.Client
+		cr   *v1beta1.Release
+		objs []*unstructured.Unstructured
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"FailedToListPolicies": {
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cr:   helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToListTenancyPolicies),
+			},
+		},
+		"NoPolicies": {
+			args: args{
+				kube: &test.MockClient{MockList: tenancyPolicyList()},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Namespace = "kube-system"
+				}),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+		},
+		"Compliant": {
+			args: args{
+				kube: &test.MockClient{MockList: tenancyPolicyList(namespaceOnly)},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Namespace = "shared"
+				}),
+				objs: []*unstructured.Unstructured{testPod("app", false)},
+			},
+		},
+		"TargetNamespaceNotAllowed": {
+			args: args{
+				kube: &test.MockClient{MockList: tenancyPolicyList(namespaceOnly)},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Namespace = "kube-system"
+				}),
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "namespace-only",
+						Rule:    tenancyRuleTargetNamespace,
+						Object:  objectTargetNamespace,
+						Message: `namespace "kube-system" is not allowed`,
+					},
+				}},
+			},
+		},
+		"ObjectNamespaceAndKindDenied": {
+			args: args{
+				kube: &test.MockClient{MockList: tenancyPolicyList(namespaceOnly)},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{
+					testClusterRole(),
+					func() *unstructured.Unstructured {
+						p := testPod("escape", false)
+						p.SetNamespace("kube-system")
+						return p
+					}(),
+				},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "namespace-only",
+						Rule:    tenancyRuleDeniedKinds,
+						Object:  "ClusterRole/admin",
+						Message: `kind "ClusterRole.rbac.authorization.k8s.io" is denied`,
+					},
+					{
+						Policy:  "namespace-only",
+						Rule:    tenancyRuleTargetNamespace,
+						Object:  "Pod/kube-system/escape",
+						Message: `namespace "kube-system" is not allowed`,
+					},
+				}},
+			},
+		},
+		"SelectorDoesNotMatch": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: tenancyPolicyList(workloadsOnly),
+					MockGet:  namespaceWithLabels(nil),
+				},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.SetLabels(map[string]string{"tenant": "true"})
+				}),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+		},
+		"FailedToGetNamespace": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: tenancyPolicyList(workloadsOnly),
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToGetNamespace),
+			},
+		},
+		"RelabelledReleaseDoesNotEscape": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: tenancyPolicyList(workloadsOnly),
+					MockGet:  namespaceWithLabels(map[string]string{"tenant": "true"}),
+				},
+				cr: helmRelease(func(r *v1beta1.Release) {
+					r.SetLabels(map[string]string{"tenant": "false"})
+				}),
+				objs: []*unstructured.Unstructured{testClusterRole()},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "workloads-only",
+						Rule:    tenancyRuleAllowedKinds,
+						Object:  "ClusterRole/admin",
+						Message: `kind "ClusterRole.rbac.authorization.k8s.io" is not allowed`,
+					},
+				}},
+			},
+		},
+		"KindNotAllowed": {
+			args: args{
+				kube: &test.MockClient{
+					MockList: tenancyPolicyList(workloadsOnly),
+					MockGet:  namespaceWithLabels(map[string]string{"tenant": "true"}),
+				},
+				cr:   helmRelease(),
+				objs: []*unstructured.Unstructured{testPod("app", false), testClusterRole()},
+			},
+			want: want{
+				err: &policyViolationError{violations: []v1beta1.PolicyViolation{
+					{
+						Policy:  "workloads-only",
+						Rule:    tenancyRuleAllowedKinds,
+						Object:  "ClusterRole/admin",
+						Message: `kind "ClusterRole.rbac.authorization.k8s.io" is not allowed`,
+					},
+				}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := tenancyValidator{ctx: context.Background(), kube: tc.args.kube, cr: tc.args.cr}
+			gotErr := v.Validate(tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Errorf("Validate(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ktypes "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+// reasonTerminalFailure indicates that a Release failed to deploy
+// permanently, and is not deployed again until its spec or a source it
+// references changes.
+const reasonTerminalFailure xpv2.ConditionReason = "TerminalFailure"
+
+const (
+	errFailedToGetPullSecret = "failed to get chart pull secret"
+	msgTerminalFailureTmpl   = "not retried until the spec or a referenced source changes: %s"
+)
+
+// terminalFailure returns a condition that indicates the Release failed to
+// deploy permanently for the supplied reason.
+func terminalFailure(msg string) xpv2.Condition {
+	return xpv2.Condition{
+		Type:               xpv2.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonTerminalFailure,
+		Message:            fmt.Sprintf(msgTerminalFailureTmpl, msg),
+	}
+}
+
+// inputsSha returns the hash of the spec of the Release and of the sources it
+// references, i.e. its values, patches, chart pull secrets and chart archive.
+func (e *helmExternal) inputsSha(ctx context.Context, cr *v1beta1.Release) (string, error) {
+	cv, err := composeValuesFromSpec(ctx, e.localKube, cr.Spec.ForProvider.ValuesSpec, cr.Namespace)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToComposeValues)
+	}
+	p, err := e.patch.getFromSpec(ctx, e.localKube, cr.Spec.ForProvider.PatchesFrom, cr.Spec.ForProvider.Patches, cr.Namespace)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToLoadPatches)
+	}
+	pullSecret := ""
+	if ref := cr.Spec.ForProvider.Chart.PullSecretRef; ref.Name != "" {
+		s := &corev1.Secret{}
+		err := e.localKube.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: ref.Name}, s)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return "", errors.Wrap(err, errFailedToGetPullSecret)
+		}
+		pullSecret = s.GetResourceVersion()
+	}
+	depSecrets := make([]string, 0, len(cr.Spec.ForProvider.Chart.DependencyPullSecrets))
+	for _, d := range cr.Spec.ForProvider.Chart.DependencyPullSecrets {
+		ref := d.SecretRef
+		s := &corev1.Secret{}
+		err := e.localKube.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: ref.Name}, s)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return "", errors.Wrap(err, errFailedToGetPullSecret)
+		}
+		depSecrets = append(depSecrets, s.GetResourceVersion())
+	}
+	content, err := contentDigest(ctx, e.localKube, cr.Spec.ForProvider.Chart, cr.Namespace)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return "", errors.Wrap(err, errFailedToGetChartContent)
+	}
+	b, err := json.Marshal(struct {
+		ForProvider v1beta1.ReleaseParameters
+		Values      map[string]interface{}
+		Patches     []ktypes.Patch
+		PullSecret  string
+		DepSecrets  []string
+		Content     string
+	}{cr.Spec.ForProvider, normalizeConfig(cv), p, pullSecret, depSecrets, content})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+// recordTerminalFailure records the spec and sources the Release failed to
+// deploy with, if it failed permanently. Otherwise it clears them.
+func (e *helmExternal) recordTerminalFailure(ctx context.Context, cr *v1beta1.Release, err error) {
+	cr.Status.TerminalFailureSha = ""
+	if !helmClient.IsTerminal(err) {
+		return
+	}
+	sha, herr := e.inputsSha(ctx, cr)
+	if herr != nil {
+		e.logger.Debug("Cannot record terminal failure", "error", herr)
+		return
+	}
+	cr.Status.TerminalFailureSha = sha
+}
+
+// failedTerminally returns true, and marks the Release as failed, if it
+// failed to deploy permanently with its current spec and sources.
+func (e *helmExternal) failedTerminally(ctx context.Context, cr *v1beta1.Release) bool {
+	if cr.Status.TerminalFailureSha == "" {
+		return false
+	}
+	sha, err := e.inputsSha(ctx, cr)
+	if err != nil || sha != cr.Status.TerminalFailureSha {
+		// Deploying again reports why the sources cannot be read.
+		cr.Status.TerminalFailureSha = ""
+		return false
+	}
+	cr.Status.SetConditions(terminalFailure(cr.GetCondition(typeDeployed).Message))
+	return true
+}
@@ -0,0 +1,68 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func TestFailedTerminally(t *testing.T) {
+	errNotFound := repo.ChartNotFoundError{Chart: testChart, RepoURL: "https://charts.example.org"}
+
+	cases := map[string]struct {
+		err    error
+		change func(cr *v1beta1.Release)
+		want   bool
+	}{
+		"Transient": {
+			err: errors.New("boom"),
+		},
+		"Terminal": {
+			err:  errNotFound,
+			want: true,
+		},
+		"SpecChanged": {
+			err: errNotFound,
+			change: func(cr *v1beta1.Release) {
+				cr.Spec.ForProvider.Chart.Version = "1.2.3"
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &helmExternal{
+				logger:    logging.NewNopLogger(),
+				localKube: &test.MockClient{},
+				patch:     newPatcher(),
+			}
+			cr := helmRelease()
+			cr.Status.SetConditions(deployed(opInstall, tc.err))
+
+			e.recordTerminalFailure(context.Background(), cr, tc.err)
+			if tc.change != nil {
+				tc.change(cr)
+			}
+			got := e.failedTerminally(context.Background(), cr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("failedTerminally(...): -want, +got: %s", diff)
+			}
+			if !got {
+				if diff := cmp.Diff("", cr.Status.TerminalFailureSha); diff != "" {
+					t.Errorf("failedTerminally(...): want the failure cleared, -want, +got: %s", diff)
+				}
+				return
+			}
+			if diff := cmp.Diff(reasonTerminalFailure, cr.GetCondition(xpv2.TypeReady).Reason); diff != "" {
+				t.Errorf("failedTerminally(...): -want reason, +got reason: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+	"time"
+
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/throttle"
+)
+
+// reasonThrottled indicates that an install or upgrade of a Release waits
+// for other operations against its target cluster to finish.
+const reasonThrottled xpv2.ConditionReason = "Throttled"
+
+// throttledPollInterval is how often a throttled Release tries again to
+// acquire an operation slot of its target cluster.
+const throttledPollInterval = 10 * time.Second
+
+const msgThrottledTmpl = "waiting for other operations against the target cluster of provider config %q, position %d in queue"
+
+// throttled returns a condition that indicates an install or upgrade of the
+// Release waits at the supplied position in the queue of its target cluster.
+func throttled(providerConfig string, position int) xpv2.Condition {
+	return xpv2.Condition{
+		Type:               xpv2.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonThrottled,
+		Message:            fmt.Sprintf(msgThrottledTmpl, providerConfig, position),
+	}
+}
+
+// providerConfigLimits returns the limits the supplied provider config sets
+// for its target cluster, falling back to the supplied defaults.
+func providerConfigLimits(defaults throttle.Limits, pc client.Object) (throttle.Limits, error) {
+	var l *namespacedv1beta1.TargetLimits
+	switch p := pc.(type) {
+	case *namespacedv1beta1.ProviderConfig:
+		l = p.Spec.Limits
+	case *namespacedv1beta1.ClusterProviderConfig:
+		l = p.Spec.Limits
+	}
+	if l == nil {
+		return defaults, nil
+	}
+	return throttle.LimitsFor(defaults, l.MaxConcurrentOperations, l.QPS, l.Burst)
+}
+
+// acquireSlot returns true if the Release may install or upgrade now. It
+// otherwise marks the Release as throttled. A slot is held until the client
+// is disconnected.
+func (e *helmExternal) acquireSlot(cr *v1beta1.Release) bool {
+	if e.throttle == nil || e.releaseSlot != nil {
+		return true
+	}
+	release, position := e.throttle.TryAcquire(e.target, string(cr.GetUID()), e.limits)
+	if release == nil {
+		e.queued = true
+		pc := ""
+		if ref := cr.GetProviderConfigReference(); ref != nil {
+			pc = ref.Name
+		}
+		cr.Status.SetConditions(throttled(pc, position))
+		return false
+	}
+	e.releaseSlot = release
+	return true
+}
@@ -31,6 +31,8 @@ import (
 const (
 	keyDefaultValuesFrom = "values.yaml"
 	keyDefaultSet        = "value"
+
+	redactedValue = "REDACTED"
 )
 
 const (
@@ -41,6 +43,16 @@ const (
 )
 
 func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1.ValuesSpec, namespace string) (map[string]interface{}, error) {
+	return composeValues(ctx, kube, spec, namespace, false)
+}
+
+// composeRedactedValuesFromSpec composes values like composeValuesFromSpec, but
+// replaces every value sourced from a Secret with a placeholder.
+func composeRedactedValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1.ValuesSpec, namespace string) (map[string]interface{}, error) {
+	return composeValues(ctx, kube, spec, namespace, true)
+}
+
+func composeValues(ctx context.Context, kube client.Client, spec v1beta1.ValuesSpec, namespace string, redact bool) (map[string]interface{}, error) { //nolint:gocyclo // easier to follow as a unit
 	base := map[string]interface{}{}
 
 	for _, vf := range spec.ValuesFrom {
@@ -53,6 +65,9 @@ func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1
 		if err = yaml.Unmarshal([]byte(s), &currVals); err != nil {
 			return nil, errors.Wrap(err, errFailedToUnmarshalDesiredValues)
 		}
+		if redact && vf.SecretKeyRef != nil {
+			currVals = redactValues(currVals)
+		}
 		base = mergeMaps(base, currVals)
 	}
 
@@ -79,6 +94,9 @@ func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1
 		if v == "" {
 			return nil, errors.New(errMissingValueForSet)
 		}
+		if redact && s.ValueFrom != nil && s.ValueFrom.SecretKeyRef != nil {
+			v = redactedValue
+		}
 
 		if err := strvals.ParseInto(fmt.Sprintf("%s=%s", s.Name, v), base); err != nil {
 			return nil, errors.Wrap(err, errFailedParsingSetData)
@@ -88,6 +106,20 @@ func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1
 	return base, nil
 }
 
+// redactValues returns a copy of the supplied values with every leaf replaced
+// by a placeholder, keeping the structure of nested maps.
+func redactValues(in map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		if m, ok := v.(map[string]interface{}); ok {
+			out[k] = redactValues(m)
+			continue
+		}
+		out[k] = redactedValue
+	}
+	return out
+}
+
 // Copied from helm cli
 // https://github.com/helm/helm/blob/9bc7934f350233fa72a11d2d29065aa78ab62792/pkg/cli/values/options.go#L88
 func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
@@ -0,0 +1,374 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
+)
+
+const (
+	errGetReleaseSet            = "cannot get ReleaseSet"
+	errUpdateReleaseSetStatus   = "cannot update ReleaseSet status"
+	errInvalidSelector          = "invalid provider config selector"
+	errListProviderConfigs      = "cannot list provider configs"
+	errListReleases             = "cannot list Releases"
+	errBuildReleaseTmpl         = "cannot build Release for provider config %s"
+	errCreateReleaseTmpl        = "cannot create Release %s"
+	errGetReleaseTmpl           = "cannot get Release %s"
+	errReleaseNotControlledTmpl = "cannot create Release %s: it exists and is not controlled by the ReleaseSet"
+	errUpdateReleaseTmpl        = "cannot update Release %s"
+	errDeleteReleaseTmpl        = "cannot delete Release %s"
+	errRollBackReleaseTmpl      = "cannot roll back Release %s"
+	msgRolloutTmpl              = "%d of %d releases up to date and available"
+)
+
+const (
+	reasonCreateRelease   event.Reason = "CreateRelease"
+	reasonUpdateRelease   event.Reason = "UpdateRelease"
+	reasonDeleteRelease   event.Reason = "DeleteRelease"
+	reasonRollBackRelease event.Reason = "RollBackRelease"
+	reasonHaltRollout     event.Reason = "HaltRollout"
+)
+
+const (
+	providerConfigKindCluster    = "ClusterProviderConfig"
+	providerConfigKindNamespaced = "ProviderConfig"
+)
+
+// Setup adds a controller that reconciles ReleaseSets.
+func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
+	name := "releaseset/" + strings.ToLower(v1beta1.ReleaseSetGroupKind)
+
+	r := &Reconciler{
+		client:       mgr.GetClient(),
+		log:          o.Logger.WithValues("controller", name),
+		record:       event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+		timeout:      timeout,
+		pollInterval: o.PollInterval,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1beta1.ReleaseSet{}).
+		Owns(&v1beta1.Release{}).
+		Watches(&namespacedv1beta1.ProviderConfig{}, handler.EnqueueRequestsFromMapFunc(r.releaseSetsFor(providerConfigKindNamespaced))).
+		Watches(&namespacedv1beta1.ClusterProviderConfig{}, handler.EnqueueRequestsFromMapFunc(r.releaseSetsFor(providerConfigKindCluster))).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(r)
+}
+
+// SetupGated adds a controller that reconciles ReleaseSets once their CRD is
+// installed.
+func SetupGated(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
+	o.Gate.Register(func() {
+		if err := Setup(mgr, o, timeout); err != nil {
+			mgr.GetLogger().Error(err, "unable to setup reconciler", "gvk", v1beta1.ReleaseSetGroupVersionKind.String())
+		}
+	}, v1beta1.ReleaseSetGroupVersionKind, v1beta1.ReleaseGroupVersionKind)
+	return nil
+}
+
+// A Reconciler generates a Release per provider config selected by a
+// ReleaseSet, and rolls changes of the ReleaseSet out to them.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+	record event.Recorder
+
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// Reconcile a ReleaseSet.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rs := &v1beta1.ReleaseSet{}
+	if err := r.client.Get(ctx, req.NamespacedName, rs); err != nil {
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetReleaseSet)
+	}
+	// The generated Releases are garbage collected through their owner
+	// references.
+	if meta.WasDeleted(rs) {
+		return reconcile.Result{}, nil
+	}
+
+	requeue, err := r.reconcile(ctx, rs)
+	if err != nil {
+		log.Debug("Cannot reconcile ReleaseSet", "error", err)
+		rs.Status.SetConditions(xpv2.ReconcileError(err))
+		// Requeue explicitly, as the error is only recorded in the status, so
+		// that the ReleaseSet is retried with backoff.
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, rs), errUpdateReleaseSetStatus)
+	}
+
+	after := r.pollInterval
+	if requeue > 0 && requeue < after {
+		after = requeue
+	}
+	rs.Status.SetConditions(xpv2.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: after}, errors.Wrap(r.client.Status().Update(ctx, rs), errUpdateReleaseSetStatus)
+}
+
+// reconcile rolls the ReleaseSet out to the Releases of the selected provider
+// configs. It returns when to reconcile again, if earlier than the poll
+// interval.
+func (r *Reconciler) reconcile(ctx context.Context, rs *v1beta1.ReleaseSet) (time.Duration, error) { //nolint:gocyclo // easier to follow as a unit
+	pcs, pcLabels, err := r.selectProviderConfigs(ctx, rs)
+	if err != nil {
+		return 0, err
+	}
+	waves, err := rolloutWaves(rs, pcs, pcLabels)
+	if err != nil {
+		return 0, err
+	}
+
+	children, orphans, err := r.releases(ctx, rs, pcs)
+	if err != nil {
+		return 0, err
+	}
+
+	desired := make(map[string]*v1beta1.Release, len(pcs))
+	for _, pc := range pcs {
+		d, err := desiredRelease(rs, pc)
+		if err != nil {
+			return 0, errors.Wrapf(err, errBuildReleaseTmpl, pc)
+		}
+		desired[pc] = d
+	}
+
+	wasHalted := rs.Status.Rollout != nil && rs.Status.Rollout.Phase == v1beta1.RolloutHalted
+	active, halted, requeue, err := progressRollout(rs, waves, desired, children, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if halted {
+		if !wasHalted {
+			r.record.Event(rs, event.Warning(reasonHaltRollout, errors.New(rs.Status.Rollout.Message)))
+		}
+		if rs.Spec.Rollout.RollbackOnHalt && !rs.Status.Rollout.RolledBack {
+			if err := r.rollback(ctx, rs, active, desired, children); err != nil {
+				return 0, err
+			}
+			rs.Status.Rollout.RolledBack = true
+		}
+	} else {
+		p := planRollout(active, maxUnavailable(rs, len(pcs)), desired, children)
+		if err := r.apply(ctx, rs, p, desired, children); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, c := range orphans {
+		if err := r.client.Delete(ctx, c); client.IgnoreNotFound(err) != nil {
+			return 0, errors.Wrapf(err, errDeleteReleaseTmpl, c.GetName())
+		}
+		r.record.Event(rs, event.Normal(reasonDeleteRelease, fmt.Sprintf("Deleted Release %s", c.GetName())))
+	}
+
+	rs.Status = aggregateStatus(rs.Status, waves, desired, children)
+	switch {
+	case halted:
+		rs.Status.SetConditions(xpv2.Unavailable().WithMessage(rs.Status.Rollout.Message))
+	case rs.Status.Total > 0 && rs.Status.Available == rs.Status.Total:
+		rs.Status.SetConditions(xpv2.Available())
+	default:
+		rs.Status.SetConditions(xpv2.Unavailable().WithMessage(fmt.Sprintf(msgRolloutTmpl, rs.Status.Available, rs.Status.Total)))
+	}
+	return requeue, nil
+}
+
+// apply creates and updates the Releases as planned.
+func (r *Reconciler) apply(ctx context.Context, rs *v1beta1.ReleaseSet, p rollout, desired, children map[string]*v1beta1.Release) error {
+	for _, pc := range p.create {
+		d := desired[pc]
+		err := r.client.Create(ctx, d)
+		if kerrors.IsAlreadyExists(err) {
+			// The cache may not have caught up with a Release created
+			// before, but a Release of someone else is never taken over.
+			c := &v1beta1.Release{}
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: d.GetNamespace(), Name: d.GetName()}, c); err != nil {
+				return errors.Wrapf(err, errGetReleaseTmpl, d.GetName())
+			}
+			if !metav1.IsControlledBy(c, rs) {
+				return errors.Errorf(errReleaseNotControlledTmpl, d.GetName())
+			}
+			children[pc] = c
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errCreateReleaseTmpl, d.GetName())
+		}
+		children[pc] = d
+		r.record.Event(rs, event.Normal(reasonCreateRelease, fmt.Sprintf("Created Release %s for %s", d.GetName(), pc)))
+	}
+	for _, pc := range p.update {
+		c := children[pc]
+		if err := updateRelease(c, desired[pc]); err != nil {
+			return errors.Wrapf(err, errUpdateReleaseTmpl, c.GetName())
+		}
+		if err := r.client.Update(ctx, c); err != nil {
+			return errors.Wrapf(err, errUpdateReleaseTmpl, c.GetName())
+		}
+		r.record.Event(rs, event.Normal(reasonUpdateRelease, fmt.Sprintf("Updated Release %s for %s", c.GetName(), pc)))
+	}
+	return nil
+}
+
+// rollback rolls the Releases updated by the halted rollout back to their
+// previous spec.
+func (r *Reconciler) rollback(ctx context.Context, rs *v1beta1.ReleaseSet, pcs []string, desired, children map[string]*v1beta1.Release) error {
+	for _, pc := range pcs {
+		c, ok := children[pc]
+		if !ok {
+			continue
+		}
+		rolled, err := rollbackRelease(c, desired[pc])
+		if err != nil {
+			return errors.Wrapf(err, errRollBackReleaseTmpl, c.GetName())
+		}
+		if !rolled {
+			continue
+		}
+		if err := r.client.Update(ctx, c); err != nil {
+			return errors.Wrapf(err, errRollBackReleaseTmpl, c.GetName())
+		}
+		r.record.Event(rs, event.Normal(reasonRollBackRelease, fmt.Sprintf("Rolled back Release %s for %s", c.GetName(), pc)))
+	}
+	return nil
+}
+
+// selectProviderConfigs returns the sorted names of the provider configs
+// selected by the ReleaseSet, and their labels.
+func (r *Reconciler) selectProviderConfigs(ctx context.Context, rs *v1beta1.ReleaseSet) ([]string, map[string]map[string]string, error) {
+	sel, err := metav1.LabelSelectorAsSelector(&rs.Spec.ProviderConfigSelector.Selector)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errInvalidSelector)
+	}
+
+	var pcs []metav1.ObjectMeta
+	switch providerConfigKind(rs) {
+	case providerConfigKindNamespaced:
+		l := &namespacedv1beta1.ProviderConfigList{}
+		if err := r.client.List(ctx, l, client.InNamespace(rs.GetNamespace()), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, nil, errors.Wrap(err, errListProviderConfigs)
+		}
+		for _, pc := range l.Items {
+			pcs = append(pcs, pc.ObjectMeta)
+		}
+	default:
+		l := &namespacedv1beta1.ClusterProviderConfigList{}
+		if err := r.client.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, nil, errors.Wrap(err, errListProviderConfigs)
+		}
+		for _, pc := range l.Items {
+			pcs = append(pcs, pc.ObjectMeta)
+		}
+	}
+
+	names := make([]string, 0, len(pcs))
+	pcLabels := make(map[string]map[string]string, len(pcs))
+	for _, pc := range pcs {
+		names = append(names, pc.GetName())
+		pcLabels[pc.GetName()] = pc.GetLabels()
+	}
+	sort.Strings(names)
+	return names, pcLabels, nil
+}
+
+// releases returns the Releases controlled by the ReleaseSet by the name of
+// their provider config, and the ones whose provider config is no longer
+// selected.
+func (r *Reconciler) releases(ctx context.Context, rs *v1beta1.ReleaseSet, pcs []string) (map[string]*v1beta1.Release, []*v1beta1.Release, error) {
+	l := &v1beta1.ReleaseList{}
+	if err := r.client.List(ctx, l, client.InNamespace(rs.GetNamespace())); err != nil {
+		return nil, nil, errors.Wrap(err, errListReleases)
+	}
+
+	selected := sets.New(pcs...)
+	children := map[string]*v1beta1.Release{}
+	var orphans []*v1beta1.Release
+	for i := range l.Items {
+		c := &l.Items[i]
+		if !metav1.IsControlledBy(c, rs) {
+			continue
+		}
+		ref := c.Spec.ProviderConfigReference
+		if ref == nil || ref.Kind != providerConfigKind(rs) || !selected.Has(ref.Name) || children[ref.Name] != nil {
+			orphans = append(orphans, c)
+			continue
+		}
+		children[ref.Name] = c
+	}
+	return children, orphans, nil
+}
+
+// releaseSetsFor returns a function that maps a provider config of the
+// supplied kind to requests for the ReleaseSets that select that kind.
+func (r *Reconciler) releaseSetsFor(kind string) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1beta1.ReleaseSetList{}
+		if err := r.client.List(ctx, l, client.InNamespace(o.GetNamespace())); err != nil {
+			r.log.Debug("Cannot list ReleaseSets", "error", err)
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		for i := range l.Items {
+			rs := &l.Items[i]
+			if providerConfigKind(rs) != kind {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: rs.GetNamespace(), Name: rs.GetName()}})
+		}
+		return reqs
+	}
+}
+
+func providerConfigKind(rs *v1beta1.ReleaseSet) string {
+	if rs.Spec.ProviderConfigSelector.Kind == providerConfigKindNamespaced {
+		return providerConfigKindNamespaced
+	}
+	return providerConfigKindCluster
+}
@@ -0,0 +1,310 @@
+package releaseset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"helm.sh/helm/v4/pkg/release/common"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+	namespacedv1beta1 "github.com/crossplane-contrib/provider-helm/apis/namespaced/v1beta1"
+)
+
+var errBoom = errors.New("boom")
+
+func TestReconcile(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testSetName}}
+	getSet := func(rs *v1beta1.ReleaseSet) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if rs == nil {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "releasesets"}, testSetName)
+			}
+			rs.DeepCopyInto(obj.(*v1beta1.ReleaseSet))
+			return nil
+		}
+	}
+	list := func(pcs []string, releases ...v1beta1.Release) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *namespacedv1beta1.ClusterProviderConfigList:
+				for _, pc := range pcs {
+					l.Items = append(l.Items, namespacedv1beta1.ClusterProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: pc}})
+				}
+			case *v1beta1.ReleaseList:
+				l.Items = releases
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		result  reconcile.Result
+		err     error
+		created []string
+		updated []string
+		deleted []string
+		status  *v1beta1.ReleaseSetStatus
+	}
+	cases := map[string]struct {
+		kube   *test.MockClient
+		create test.MockCreateFn
+		want   want
+	}{
+		"NotFound": {
+			kube: &test.MockClient{MockGet: getSet(nil)},
+		},
+		"GetError": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{err: errors.Wrap(errBoom, errGetReleaseSet)},
+		},
+		"ListProviderConfigsError": {
+			kube: &test.MockClient{
+				MockGet:          getSet(releaseSet()),
+				MockList:         test.NewMockListFn(errBoom),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{xpv2.ReconcileError(errors.Wrap(errBoom, errListProviderConfigs))}},
+				},
+			},
+		},
+		"ListProviderConfigsAndStatusUpdateError": {
+			kube: &test.MockClient{
+				MockGet:          getSet(releaseSet()),
+				MockList:         test.NewMockListFn(errBoom),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true},
+				err:    errors.Wrap(errBoom, errUpdateReleaseSetStatus),
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{xpv2.ReconcileError(errors.Wrap(errBoom, errListProviderConfigs))}},
+				},
+			},
+		},
+		"CreateAndDelete": {
+			kube: &test.MockClient{
+				MockGet: getSet(releaseSet()),
+				MockList: func() test.MockListFn {
+					orphan := *child(t, releaseSet(), "gone", true)
+					return list([]string{"b", "a"}, orphan)
+				}(),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: pollInterval},
+				created: []string{releaseName(testSetName, "a"), releaseName(testSetName, "b")},
+				deleted: []string{releaseName(testSetName, "gone")},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{
+						xpv2.Unavailable().WithMessage("0 of 2 releases up to date and available"),
+						xpv2.ReconcileSuccess(),
+					}},
+					Releases: []v1beta1.ReleaseSetMemberStatus{
+						{ProviderConfig: "a", Release: releaseName(testSetName, "a"), UpToDate: true, Wave: defaultWaveName},
+						{ProviderConfig: "b", Release: releaseName(testSetName, "b"), UpToDate: true, Wave: defaultWaveName},
+					},
+					Total:    2,
+					UpToDate: 2,
+					Rollout: &v1beta1.ReleaseSetRolloutStatus{
+						Phase:    v1beta1.RolloutProgressing,
+						WaveName: defaultWaveName,
+						Message:  `rolling out wave "default"`,
+					},
+				},
+			},
+		},
+		"CreateExistingControlled": {
+			kube: &test.MockClient{
+				MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if r, ok := obj.(*v1beta1.Release); ok {
+						child(t, releaseSet(), "a", false).DeepCopyInto(r)
+						return nil
+					}
+					return getSet(releaseSet())(ctx, key, obj)
+				},
+				MockList:         list([]string{"a"}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			create: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+				return kerrors.NewAlreadyExists(schema.GroupResource{Resource: "releases"}, obj.GetName())
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: pollInterval},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{
+						xpv2.Unavailable().WithMessage("0 of 1 releases up to date and available"),
+						xpv2.ReconcileSuccess(),
+					}},
+					Releases: []v1beta1.ReleaseSetMemberStatus{
+						{ProviderConfig: "a", Release: releaseName(testSetName, "a"), Version: testVersion, UpToDate: true, Wave: defaultWaveName},
+					},
+					Total:    1,
+					UpToDate: 1,
+					Rollout: &v1beta1.ReleaseSetRolloutStatus{
+						Phase:    v1beta1.RolloutProgressing,
+						WaveName: defaultWaveName,
+						Message:  `rolling out wave "default"`,
+					},
+				},
+			},
+		},
+		"CreateExistingNotControlled": {
+			kube: &test.MockClient{
+				MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if r, ok := obj.(*v1beta1.Release); ok {
+						r.SetName(key.Name)
+						r.SetNamespace(key.Namespace)
+						return nil
+					}
+					return getSet(releaseSet())(ctx, key, obj)
+				},
+				MockList:         list([]string{"a"}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			create: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+				return kerrors.NewAlreadyExists(schema.GroupResource{Resource: "releases"}, obj.GetName())
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{
+						xpv2.ReconcileError(errors.Errorf(errReleaseNotControlledTmpl, releaseName(testSetName, "a"))),
+					}},
+					Rollout: &v1beta1.ReleaseSetRolloutStatus{
+						Phase:    v1beta1.RolloutProgressing,
+						WaveName: defaultWaveName,
+						Message:  `rolling out wave "default"`,
+					},
+				},
+			},
+		},
+		"RollOut": {
+			kube: &test.MockClient{
+				MockGet: getSet(releaseSet(withVersion(testNewVersion))),
+				MockList: func() test.MockListFn {
+					return list([]string{"a", "b"}, *child(t, releaseSet(), "a", true), *child(t, releaseSet(), "b", true))
+				}(),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: pollInterval},
+				updated: []string{releaseName(testSetName, "a")},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{
+						xpv2.Unavailable().WithMessage("0 of 2 releases up to date and available"),
+						xpv2.ReconcileSuccess(),
+					}},
+					Releases: []v1beta1.ReleaseSetMemberStatus{
+						{ProviderConfig: "a", Release: releaseName(testSetName, "a"), Version: testVersion, UpToDate: true, Wave: defaultWaveName},
+						{ProviderConfig: "b", Release: releaseName(testSetName, "b"), Version: testVersion, Wave: defaultWaveName},
+					},
+					Total:    2,
+					UpToDate: 1,
+					Rollout: &v1beta1.ReleaseSetRolloutStatus{
+						Phase:    v1beta1.RolloutProgressing,
+						WaveName: defaultWaveName,
+						Message:  `rolling out wave "default"`,
+					},
+				},
+			},
+		},
+		"HaltAndRollBack": {
+			kube: &test.MockClient{
+				MockGet: getSet(releaseSet(withVersion(testNewVersion), func(rs *v1beta1.ReleaseSet) {
+					zero := intstr.FromInt32(0)
+					rs.Spec.Rollout.FailureThreshold = &zero
+					rs.Spec.Rollout.RollbackOnHalt = true
+				})),
+				MockList: func() test.MockListFn {
+					a := child(t, releaseSet(), "a", true)
+					d, _ := desiredRelease(releaseSet(withVersion(testNewVersion)), "a")
+					_ = updateRelease(a, d)
+					a.Status.AtProvider.State = common.StatusFailed
+					return list([]string{"a", "b"}, *a, *child(t, releaseSet(), "b", true))
+				}(),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: pollInterval},
+				updated: []string{releaseName(testSetName, "a")},
+				status: &v1beta1.ReleaseSetStatus{
+					ConditionedStatus: xpv2.ConditionedStatus{Conditions: []xpv2.Condition{
+						xpv2.Unavailable().WithMessage("rollout halted, releases failed for a"),
+						xpv2.ReconcileSuccess(),
+					}},
+					Releases: []v1beta1.ReleaseSetMemberStatus{
+						{ProviderConfig: "a", Release: releaseName(testSetName, "a"), Version: testVersion, Wave: defaultWaveName, Failed: true},
+						{ProviderConfig: "b", Release: releaseName(testSetName, "b"), Version: testVersion, Wave: defaultWaveName},
+					},
+					Total: 2,
+					Rollout: &v1beta1.ReleaseSetRolloutStatus{
+						Phase:      v1beta1.RolloutHalted,
+						WaveName:   defaultWaveName,
+						Failed:     []string{"a"},
+						RolledBack: true,
+						Message:    "rollout halted, releases failed for a",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			tc.kube.MockCreate = func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+				if tc.create != nil {
+					return tc.create(context.Background(), obj)
+				}
+				got.created = append(got.created, obj.GetName())
+				return nil
+			}
+			tc.kube.MockUpdate = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				got.updated = append(got.updated, obj.GetName())
+				// The API server bumps the generation on spec changes.
+				obj.SetGeneration(obj.GetGeneration() + 1)
+				return nil
+			}
+			tc.kube.MockDelete = func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+				got.deleted = append(got.deleted, obj.GetName())
+				return nil
+			}
+			if tc.kube.MockStatusUpdate != nil {
+				su := tc.kube.MockStatusUpdate
+				tc.kube.MockStatusUpdate = func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					got.status = obj.(*v1beta1.ReleaseSet).Status.DeepCopy()
+					return su(ctx, obj, opts...)
+				}
+			}
+
+			r := &Reconciler{
+				client:       tc.kube,
+				log:          logging.NewNopLogger(),
+				record:       event.NewNopRecorder(),
+				timeout:      timeout,
+				pollInterval: pollInterval,
+			}
+			got.result, got.err = r.Reconcile(context.Background(), req)
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors(), test.EquateConditions(), cmpopts.IgnoreFields(xpv2.Condition{}, "LastTransitionTime"), cmpopts.IgnoreFields(v1beta1.ReleaseSetRolloutStatus{}, "Revision", "WaveStartTime")); diff != "" {
+				t.Errorf("Reconcile(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseset
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+const (
+	// annotationTemplateHash is the hash of the desired state of a generated
+	// Release, used to tell whether it matches the current ReleaseSet.
+	annotationTemplateHash = "helm.m.crossplane.io/release-set-hash"
+	// annotationPreviousRelease holds the spec of a generated Release before
+	// it was last updated, to roll back to if the rollout halts.
+	annotationPreviousRelease = "helm.m.crossplane.io/release-set-previous"
+)
+
+const (
+	errFailedToMergeValues = "failed to merge override values"
+	errFailedToHashRelease = "failed to hash Release"
+	errFailedToRecordSpec  = "failed to record previous spec of Release"
+	errFailedToReadSpec    = "failed to read previous spec of Release"
+)
+
+// A previousRelease is the state of a generated Release before it was last
+// updated.
+type previousRelease struct {
+	Hash string              `json:"hash"`
+	Spec v1beta1.ReleaseSpec `json:"spec"`
+}
+
+// A rollout lists the provider configs whose Releases are to be created or
+// updated.
+type rollout struct {
+	create []string
+	update []string
+}
+
+// desiredRelease returns the Release the ReleaseSet generates for the supplied
+// provider config.
+func desiredRelease(rs *v1beta1.ReleaseSet, pc string) (*v1beta1.Release, error) {
+	t := rs.Spec.Template
+	fp := t.Spec.ForProvider.DeepCopy()
+	for _, o := range rs.Spec.Overrides {
+		if o.ProviderConfigName != pc {
+			continue
+		}
+		v, err := mergeValues(fp.Values, o.Values)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToMergeValues)
+		}
+		fp.Values = v
+		fp.ValuesFrom = append(fp.ValuesFrom, o.ValuesFrom...)
+		fp.Set = append(fp.Set, o.Set...)
+	}
+
+	r := &v1beta1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      releaseName(rs.GetName(), pc),
+			Namespace: rs.GetNamespace(),
+		},
+		Spec: v1beta1.ReleaseSpec{
+			ManagedResourceSpec: xpv2.ManagedResourceSpec{
+				ProviderConfigReference: &xpv2.ProviderConfigReference{Kind: providerConfigKind(rs), Name: pc},
+				ManagementPolicies:      t.Spec.ManagementPolicies,
+			},
+			ForProvider:          *fp,
+			RollbackRetriesLimit: t.Spec.RollbackRetriesLimit,
+		},
+	}
+	meta.AddLabels(r, t.Metadata.Labels)
+	meta.AddAnnotations(r, t.Metadata.Annotations)
+	// The Helm release has the same name in every cluster, unless the
+	// template says otherwise.
+	if meta.GetExternalName(r) == "" {
+		meta.SetExternalName(r, rs.GetName())
+	}
+
+	h, err := releaseHash(r)
+	if err != nil {
+		return nil, err
+	}
+	meta.AddAnnotations(r, map[string]string{annotationTemplateHash: h})
+	meta.AddOwnerReference(r, meta.AsController(meta.TypedReferenceTo(rs, v1beta1.ReleaseSetGroupVersionKind)))
+	return r, nil
+}
+
+// releaseName returns the name of the Release generated by the named
+// ReleaseSet for the supplied provider config. The name is suffixed with a hash
+// of both names, so that names containing dashes do not collide, and the
+// names are truncated to keep it a valid object name.
+func releaseName(rs, pc string) string {
+	h := fmt.Sprintf("%x", sha256.Sum256([]byte(rs+"/"+pc)))[:10]
+	n := rs + "-" + pc
+	if l := validation.DNS1123SubdomainMaxLength - len(h) - 1; len(n) > l {
+		n = strings.TrimRight(n[:l], "-.")
+	}
+	return n + "-" + h
+}
+
+func releaseHash(r *v1beta1.Release) (string, error) {
+	b, err := json.Marshal(struct {
+		Labels      map[string]string
+		Annotations map[string]string
+		Spec        v1beta1.ReleaseSpec
+	}{r.GetLabels(), r.GetAnnotations(), r.Spec})
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToHashRelease)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:16], nil
+}
+
+// updateRelease updates an existing Release to the desired one, keeping the
+// labels and annotations set by others. The previous spec is recorded to roll
+// back to.
+func updateRelease(existing, desired *v1beta1.Release) error {
+	if h := existing.GetAnnotations()[annotationTemplateHash]; h != "" {
+		b, err := json.Marshal(previousRelease{Hash: h, Spec: existing.Spec})
+		if err != nil {
+			return errors.Wrap(err, errFailedToRecordSpec)
+		}
+		meta.AddAnnotations(existing, map[string]string{annotationPreviousRelease: string(b)})
+	}
+	meta.AddLabels(existing, desired.GetLabels())
+	meta.AddAnnotations(existing, desired.GetAnnotations())
+	existing.Spec = desired.Spec
+	return nil
+}
+
+// rollbackRelease restores the spec a Release had before it was last updated
+// to the desired one. It returns false if there is nothing to roll back.
+func rollbackRelease(existing, desired *v1beta1.Release) (bool, error) {
+	raw, ok := existing.GetAnnotations()[annotationPreviousRelease]
+	if !ok || !upToDate(existing, desired) {
+		return false, nil
+	}
+	p := previousRelease{}
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return false, errors.Wrap(err, errFailedToReadSpec)
+	}
+	meta.RemoveAnnotations(existing, annotationPreviousRelease)
+	meta.AddAnnotations(existing, map[string]string{annotationTemplateHash: p.Hash})
+	existing.Spec = p.Spec
+	return true, nil
+}
+
+func upToDate(existing, desired *v1beta1.Release) bool {
+	return existing.GetAnnotations()[annotationTemplateHash] == desired.GetAnnotations()[annotationTemplateHash]
+}
+
+// available returns true if the supplied Release is available at its current
+// generation.
+func available(r *v1beta1.Release) bool {
+	c := r.GetCondition(xpv2.TypeReady)
+	if c.Status != corev1.ConditionTrue || c.Reason != xpv2.ReasonAvailable {
+		return false
+	}
+	return c.ObservedGeneration == 0 || c.ObservedGeneration == r.GetGeneration()
+}
+
+// tested returns true if the supplied Release does not run tests, or if the
+// tests of its deployed revision succeeded.
+func tested(r *v1beta1.Release) bool {
+	if !r.Spec.ForProvider.RunTests {
+		return true
+	}
+	t := r.Status.AtProvider.Test
+	return t != nil && t.Revision == r.Status.AtProvider.Revision && t.Phase == v1beta1.TestSucceeded
+}
+
+// testsFailed returns true if the tests of the deployed revision of the
+// supplied Release failed.
+func testsFailed(r *v1beta1.Release) bool {
+	t := r.Status.AtProvider.Test
+	return t != nil && t.Revision == r.Status.AtProvider.Revision && t.Phase == v1beta1.TestFailed
+}
+
+// maxUnavailable returns the number of Releases that may be updating or
+// unavailable at the same time, at least one.
+func maxUnavailable(rs *v1beta1.ReleaseSet, total int) int {
+	mu := intstr.FromInt32(1)
+	if rs.Spec.Rollout.MaxUnavailable != nil {
+		mu = *rs.Spec.Rollout.MaxUnavailable
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(&mu, total, false)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// planRollout decides which Releases of the supplied provider configs to
+// create and update. Missing Releases are always created, as they do not take
+// anything down. Outdated Releases are updated in the order of their provider
+// configs, as long as no more than budget Releases are unavailable. Releases that are already
+// unavailable are updated regardless, as the update may fix them.
+func planRollout(pcs []string, budget int, desired, existing map[string]*v1beta1.Release) rollout {
+	unavailable := 0
+	for _, pc := range pcs {
+		if c, ok := existing[pc]; ok && !available(c) {
+			unavailable++
+		}
+	}
+
+	r := rollout{}
+	for _, pc := range pcs {
+		c, ok := existing[pc]
+		switch {
+		case !ok:
+			r.create = append(r.create, pc)
+		case upToDate(c, desired[pc]):
+		case !available(c):
+			r.update = append(r.update, pc)
+		case unavailable < budget:
+			r.update = append(r.update, pc)
+			unavailable++
+		}
+	}
+	return r
+}
+
+// aggregateStatus reports the Release of every selected provider config.
+func aggregateStatus(s v1beta1.ReleaseSetStatus, waves []wave, desired, existing map[string]*v1beta1.Release) v1beta1.ReleaseSetStatus {
+	out := v1beta1.ReleaseSetStatus{
+		ConditionedStatus: s.ConditionedStatus,
+		Rollout:           s.Rollout,
+	}
+	waveOf := map[string]string{}
+	var pcs []string
+	for _, w := range waves {
+		for _, pc := range w.pcs {
+			waveOf[pc] = w.name
+			pcs = append(pcs, pc)
+		}
+	}
+	sort.Strings(pcs)
+	out.Total = int32(len(pcs)) //nolint:gosec // the number of provider configs does not overflow
+
+	var failed sets.Set[string]
+	if s.Rollout != nil {
+		failed = sets.New(s.Rollout.Failed...)
+	}
+	for _, pc := range pcs {
+		m := v1beta1.ReleaseSetMemberStatus{ProviderConfig: pc, Release: desired[pc].GetName(), Wave: waveOf[pc], Failed: failed.Has(pc)}
+		if c, ok := existing[pc]; ok {
+			m.Release = c.GetName()
+			m.Version = c.Status.AtProvider.Version
+			m.Revision = c.Status.AtProvider.Revision
+			m.UpToDate = upToDate(c, desired[pc])
+			m.Available = m.UpToDate && available(c)
+		}
+		if m.UpToDate {
+			out.UpToDate++
+		}
+		if m.Available {
+			out.Available++
+		}
+		out.Releases = append(out.Releases, m)
+	}
+	return out
+}
+
+// mergeValues merges the override values into the base values.
+func mergeValues(base, override runtime.RawExtension) (runtime.RawExtension, error) {
+	if len(override.Raw) == 0 {
+		return base, nil
+	}
+	b := map[string]interface{}{}
+	if err := yaml.Unmarshal(base.Raw, &b); err != nil {
+		return runtime.RawExtension{}, err
+	}
+	o := map[string]interface{}{}
+	if err := yaml.Unmarshal(override.Raw, &o); err != nil {
+		return runtime.RawExtension{}, err
+	}
+	raw, err := json.Marshal(mergeMaps(b, o))
+	return runtime.RawExtension{Raw: raw}, err
+}
+
+// Copied from helm cli
+// https://github.com/helm/helm/blob/9bc7934f350233fa72a11d2d29065aa78ab62792/pkg/cli/values/options.go#L88
+func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(a))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		if v, ok := v.(map[string]interface{}); ok {
+			if bv, ok := out[k]; ok {
+				if bv, ok := bv.(map[string]interface{}); ok {
+					out[k] = mergeMaps(bv, v)
+					continue
+				}
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
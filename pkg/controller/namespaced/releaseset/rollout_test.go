@@ -0,0 +1,321 @@
+package releaseset
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+const (
+	testNamespace  = "fleet"
+	testSetName    = "ingress"
+	testChart      = "ingress-nginx"
+	testVersion    = "4.10.0"
+	testNewVersion = "4.11.0"
+
+	timeout      = time.Minute
+	pollInterval = 10 * time.Minute
+)
+
+type releaseSetModifier func(*v1beta1.ReleaseSet)
+
+func releaseSet(mods ...releaseSetModifier) *v1beta1.ReleaseSet {
+	rs := &v1beta1.ReleaseSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testSetName,
+			Namespace: testNamespace,
+			UID:       "set-uid",
+		},
+		Spec: v1beta1.ReleaseSetSpec{
+			Template: v1beta1.ReleaseTemplate{
+				Spec: v1beta1.ReleaseTemplateSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart: v1beta1.ChartSpec{Name: testChart, Version: testVersion},
+					},
+				},
+			},
+		},
+	}
+	for _, m := range mods {
+		m(rs)
+	}
+	return rs
+}
+
+func withVersion(v string) releaseSetModifier {
+	return func(rs *v1beta1.ReleaseSet) {
+		rs.Spec.Template.Spec.ForProvider.Chart.Version = v
+	}
+}
+
+func withMaxUnavailable(mu intstr.IntOrString) releaseSetModifier {
+	return func(rs *v1beta1.ReleaseSet) {
+		rs.Spec.Rollout.MaxUnavailable = &mu
+	}
+}
+
+// child returns the Release generated by the supplied ReleaseSet, available
+// if requested.
+func child(t *testing.T, rs *v1beta1.ReleaseSet, pc string, avail bool) *v1beta1.Release {
+	t.Helper()
+	r, err := desiredRelease(rs, pc)
+	if err != nil {
+		t.Fatalf("desiredRelease(...): %v", err)
+	}
+	r.SetGeneration(1)
+	if avail {
+		c := xpv2.Available()
+		c.ObservedGeneration = 1
+		r.Status.SetConditions(c)
+	}
+	r.Status.AtProvider.Version = rs.Spec.Template.Spec.ForProvider.Chart.Version
+	return r
+}
+
+func desiredReleases(t *testing.T, rs *v1beta1.ReleaseSet, pcs ...string) map[string]*v1beta1.Release {
+	t.Helper()
+	out := map[string]*v1beta1.Release{}
+	for _, pc := range pcs {
+		r, err := desiredRelease(rs, pc)
+		if err != nil {
+			t.Fatalf("desiredRelease(...): %v", err)
+		}
+		out[pc] = r
+	}
+	return out
+}
+
+func TestDesiredRelease(t *testing.T) {
+	rs := releaseSet(func(rs *v1beta1.ReleaseSet) {
+		rs.Spec.ProviderConfigSelector.Kind = providerConfigKindNamespaced
+		rs.Spec.Template.Metadata.Labels = map[string]string{"team": "platform"}
+		rs.Spec.Template.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"controller":{"replicas":2,"kind":"Deployment"}}`)}
+		rs.Spec.Template.Spec.ForProvider.Set = []v1beta1.SetVal{{Name: "a", Value: "template"}}
+		rs.Spec.Overrides = []v1beta1.ReleaseSetOverride{
+			{
+				ProviderConfigName: "eu-1",
+				ValuesSpec: v1beta1.ValuesSpec{
+					Values: runtime.RawExtension{Raw: []byte(`{"controller":{"replicas":5}}`)},
+					Set:    []v1beta1.SetVal{{Name: "a", Value: "override"}},
+				},
+			},
+			{
+				ProviderConfigName: "us-1",
+				ValuesSpec: v1beta1.ValuesSpec{
+					Values: runtime.RawExtension{Raw: []byte(`{"controller":{"replicas":9}}`)},
+				},
+			},
+		}
+	})
+
+	got, err := desiredRelease(rs, "eu-1")
+	if err != nil {
+		t.Fatalf("desiredRelease(...): %v", err)
+	}
+
+	if diff := cmp.Diff(releaseName(testSetName, "eu-1"), got.GetName()); diff != "" {
+		t.Errorf("desiredRelease(...): -want name, +got name:\n%s", diff)
+	}
+	if diff := cmp.Diff(testSetName, meta.GetExternalName(got)); diff != "" {
+		t.Errorf("desiredRelease(...): -want external name, +got external name:\n%s", diff)
+	}
+	if diff := cmp.Diff("platform", got.GetLabels()["team"]); diff != "" {
+		t.Errorf("desiredRelease(...): -want label, +got label:\n%s", diff)
+	}
+	if !metav1.IsControlledBy(got, rs) {
+		t.Errorf("desiredRelease(...): Release is not controlled by the ReleaseSet")
+	}
+	if diff := cmp.Diff(&xpv2.ProviderConfigReference{Kind: providerConfigKindNamespaced, Name: "eu-1"}, got.Spec.ProviderConfigReference); diff != "" {
+		t.Errorf("desiredRelease(...): -want provider config, +got provider config:\n%s", diff)
+	}
+	if diff := cmp.Diff(`{"controller":{"kind":"Deployment","replicas":5}}`, string(got.Spec.ForProvider.Values.Raw)); diff != "" {
+		t.Errorf("desiredRelease(...): -want values, +got values:\n%s", diff)
+	}
+	if diff := cmp.Diff([]v1beta1.SetVal{{Name: "a", Value: "template"}, {Name: "a", Value: "override"}}, got.Spec.ForProvider.Set); diff != "" {
+		t.Errorf("desiredRelease(...): -want set, +got set:\n%s", diff)
+	}
+	if len(rs.Spec.Template.Spec.ForProvider.Set) != 1 {
+		t.Errorf("desiredRelease(...): template was modified")
+	}
+
+	other, err := desiredRelease(rs, "us-1")
+	if err != nil {
+		t.Fatalf("desiredRelease(...): %v", err)
+	}
+	if upToDate(got, other) {
+		t.Errorf("upToDate(...): Releases with different overrides have the same hash")
+	}
+}
+
+func TestReleaseName(t *testing.T) {
+	if releaseName("a-b", "c") == releaseName("a", "b-c") {
+		t.Errorf("releaseName(...): names of different provider configs collide")
+	}
+	long := releaseName(strings.Repeat("r", 200), strings.Repeat("p", 100))
+	if errs := validation.IsDNS1123Subdomain(long); len(errs) > 0 {
+		t.Errorf("releaseName(...): invalid name %q: %v", long, errs)
+	}
+	if releaseName(strings.Repeat("r", 200), strings.Repeat("p", 100)+"-1") == long {
+		t.Errorf("releaseName(...): truncated names collide")
+	}
+}
+
+func TestPlanRollout(t *testing.T) {
+	old := releaseSet()
+	pcs := []string{"a", "b", "c", "d"}
+
+	cases := map[string]struct {
+		rs       *v1beta1.ReleaseSet
+		existing func(t *testing.T) map[string]*v1beta1.Release
+		want     rollout
+	}{
+		"CreateMissing": {
+			rs: releaseSet(),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"b": child(t, old, "b", true)}
+			},
+			want: rollout{create: []string{"a", "c", "d"}},
+		},
+		"UpToDate": {
+			rs: releaseSet(),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{
+					"a": child(t, old, "a", true), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", true), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{},
+		},
+		"FirstWave": {
+			rs: releaseSet(withVersion(testNewVersion)),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{
+					"a": child(t, old, "a", true), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", true), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{update: []string{"a"}},
+		},
+		"WaitForWave": {
+			rs: releaseSet(withVersion(testNewVersion)),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				rs := releaseSet(withVersion(testNewVersion))
+				return map[string]*v1beta1.Release{
+					"a": child(t, rs, "a", false), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", true), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{},
+		},
+		"NextWave": {
+			rs: releaseSet(withVersion(testNewVersion)),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				rs := releaseSet(withVersion(testNewVersion))
+				return map[string]*v1beta1.Release{
+					"a": child(t, rs, "a", true), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", true), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{update: []string{"b"}},
+		},
+		"MaxUnavailablePercent": {
+			rs: releaseSet(withVersion(testNewVersion), withMaxUnavailable(intstr.FromString("50%"))),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{
+					"a": child(t, old, "a", true), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", true), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{update: []string{"a", "b"}},
+		},
+		"UpdateUnavailable": {
+			rs: releaseSet(withVersion(testNewVersion)),
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{
+					"a": child(t, old, "a", true), "b": child(t, old, "b", true),
+					"c": child(t, old, "c", false), "d": child(t, old, "d", true),
+				}
+			},
+			want: rollout{update: []string{"c"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := planRollout(pcs, maxUnavailable(tc.rs, len(pcs)), desiredReleases(t, tc.rs, pcs...), tc.existing(t))
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(rollout{})); diff != "" {
+				t.Errorf("planRollout(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAggregateStatus(t *testing.T) {
+	old := releaseSet()
+	rs := releaseSet(withVersion(testNewVersion))
+	pcs := []string{"a", "b", "c"}
+	existing := map[string]*v1beta1.Release{
+		"a": child(t, rs, "a", true),
+		"b": child(t, old, "b", true),
+	}
+
+	want := v1beta1.ReleaseSetStatus{
+		Releases: []v1beta1.ReleaseSetMemberStatus{
+			{ProviderConfig: "a", Release: releaseName(testSetName, "a"), Version: testNewVersion, UpToDate: true, Available: true, Wave: "canary"},
+			{ProviderConfig: "b", Release: releaseName(testSetName, "b"), Version: testVersion, Wave: defaultWaveName},
+			{ProviderConfig: "c", Release: releaseName(testSetName, "c"), Wave: defaultWaveName},
+		},
+		Total:     3,
+		UpToDate:  1,
+		Available: 1,
+	}
+	got := aggregateStatus(v1beta1.ReleaseSetStatus{}, []wave{{name: "canary", pcs: pcs[:1]}, {name: defaultWaveName, pcs: pcs[1:]}}, desiredReleases(t, rs, pcs...), existing)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("aggregateStatus(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMaxUnavailable(t *testing.T) {
+	cases := map[string]struct {
+		rs    *v1beta1.ReleaseSet
+		total int
+		want  int
+	}{
+		"Default": {
+			rs:    releaseSet(),
+			total: 10,
+			want:  1,
+		},
+		"Absolute": {
+			rs:    releaseSet(withMaxUnavailable(intstr.FromInt32(3))),
+			total: 10,
+			want:  3,
+		},
+		"PercentRoundsDown": {
+			rs:    releaseSet(withMaxUnavailable(intstr.FromString("25%"))),
+			total: 10,
+			want:  2,
+		},
+		"AtLeastOne": {
+			rs:    releaseSet(withMaxUnavailable(intstr.FromString("10%"))),
+			total: 3,
+			want:  1,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, maxUnavailable(tc.rs, tc.total)); diff != "" {
+				t.Errorf("maxUnavailable(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
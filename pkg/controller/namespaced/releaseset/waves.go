@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseset
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"helm.sh/helm/v4/pkg/release/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+// defaultWaveName is the name of the wave of the provider configs not
+// selected by any configured wave.
+const defaultWaveName = "default"
+
+const defaultProgressDeadline = 10 * time.Minute
+
+const (
+	errInvalidWaveSelectorTmpl = "invalid selector of rollout wave %q"
+	errFailedToHashRollout     = "failed to hash rollout revision"
+	msgRolloutHaltedTmpl       = "rollout halted, releases failed for %s"
+	msgRolloutSoakingTmpl      = "wave %q is available, soaking until %s"
+	msgRolloutProgressingTmpl  = "rolling out wave %q"
+	msgRolloutCompleted        = "rollout completed"
+)
+
+// A wave is a group of provider configs rolled out to together.
+type wave struct {
+	name string
+	pcs  []string
+}
+
+// rolloutWaves splits the supplied sorted provider configs into the rollout
+// waves of the ReleaseSet.
+func rolloutWaves(rs *v1beta1.ReleaseSet, pcs []string, pcLabels map[string]map[string]string) ([]wave, error) {
+	assigned := sets.New[string]()
+	waves := make([]wave, 0, len(rs.Spec.Rollout.Waves)+1)
+	for _, w := range rs.Spec.Rollout.Waves {
+		sel, err := metav1.LabelSelectorAsSelector(&w.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, errInvalidWaveSelectorTmpl, w.Name)
+		}
+		cur := wave{name: w.Name}
+		for _, pc := range pcs {
+			if assigned.Has(pc) || !sel.Matches(labels.Set(pcLabels[pc])) {
+				continue
+			}
+			cur.pcs = append(cur.pcs, pc)
+			assigned.Insert(pc)
+		}
+		waves = append(waves, cur)
+	}
+
+	rest := wave{name: defaultWaveName}
+	for _, pc := range pcs {
+		if !assigned.Has(pc) {
+			rest.pcs = append(rest.pcs, pc)
+		}
+	}
+	if len(rest.pcs) > 0 || len(waves) == 0 {
+		waves = append(waves, rest)
+	}
+	return waves, nil
+}
+
+// rolloutRevision identifies the template and overrides of the ReleaseSet.
+func rolloutRevision(rs *v1beta1.ReleaseSet) (string, error) {
+	b, err := json.Marshal(struct {
+		Template  v1beta1.ReleaseTemplate
+		Overrides []v1beta1.ReleaseSetOverride
+	}{rs.Spec.Template, rs.Spec.Overrides})
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToHashRollout)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:16], nil
+}
+
+// progressRollout advances the rollout of the ReleaseSet through its waves,
+// and records its state in the status of the ReleaseSet. It returns the
+// provider configs of the waves rolled out to so far, whether the rollout is
+// halted, and when to check on the rollout again, if earlier than usual.
+func progressRollout(rs *v1beta1.ReleaseSet, waves []wave, desired, existing map[string]*v1beta1.Release, now time.Time) ([]string, bool, time.Duration, error) { //nolint:gocyclo // easier to follow as a unit
+	rev, err := rolloutRevision(rs)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	st := rs.Status.Rollout
+	if st == nil || st.Revision != rev {
+		st = &v1beta1.ReleaseSetRolloutStatus{Revision: rev, WaveStartTime: &metav1.Time{Time: now}}
+		rs.Status.Rollout = st
+	}
+	// The waves may have changed since the rollout started.
+	if int(st.Wave) >= len(waves) {
+		st.Wave = int32(len(waves) - 1) //nolint:gosec // the number of waves does not overflow
+	}
+
+	total := 0
+	for _, w := range waves {
+		total += len(w.pcs)
+	}
+	deadline := defaultProgressDeadline
+	if d := rs.Spec.Rollout.ProgressDeadline; d != nil {
+		deadline = d.Duration
+	}
+
+	for {
+		w := waves[st.Wave]
+		st.WaveName = w.name
+
+		var active []string
+		for _, aw := range waves[:st.Wave+1] {
+			active = append(active, aw.pcs...)
+		}
+
+		// The failures that halted the rollout are kept once the Releases
+		// were rolled back.
+		if st.RolledBack {
+			st.Phase = v1beta1.RolloutHalted
+			return active, true, 0, nil
+		}
+
+		expired := now.Sub(st.WaveStartTime.Time) >= deadline
+		st.Failed = failedReleases(active, desired, existing, expired)
+		if halt(rs, len(st.Failed), total) {
+			st.Phase = v1beta1.RolloutHalted
+			st.Message = fmt.Sprintf(msgRolloutHaltedTmpl, strings.Join(st.Failed, ", "))
+			return active, true, 0, nil
+		}
+
+		if !waveDone(w, desired, existing, sets.New(st.Failed...)) {
+			st.Phase = v1beta1.RolloutProgressing
+			st.Message = fmt.Sprintf(msgRolloutProgressingTmpl, w.name)
+			st.SoakStartTime = nil
+			var requeue time.Duration
+			if !expired {
+				requeue = st.WaveStartTime.Add(deadline).Sub(now)
+			}
+			return active, false, requeue, nil
+		}
+
+		if int(st.Wave) == len(waves)-1 {
+			st.Phase = v1beta1.RolloutCompleted
+			st.Message = msgRolloutCompleted
+			return active, false, 0, nil
+		}
+
+		if soak := rs.Spec.Rollout.SoakDuration; soak != nil && len(w.pcs) > 0 {
+			if st.SoakStartTime == nil {
+				st.SoakStartTime = &metav1.Time{Time: now}
+			}
+			end := st.SoakStartTime.Add(soak.Duration)
+			if end.After(now) {
+				st.Phase = v1beta1.RolloutSoaking
+				st.Message = fmt.Sprintf(msgRolloutSoakingTmpl, w.name, end.UTC().Format(time.RFC3339))
+				return active, false, end.Sub(now), nil
+			}
+		}
+
+		st.Wave++
+		st.WaveStartTime = &metav1.Time{Time: now}
+		st.SoakStartTime = nil
+	}
+}
+
+// failedReleases returns the provider configs whose up to date Releases
+// failed or failed their tests. Releases that are not available count as
+// failed once the progress deadline expired.
+func failedReleases(pcs []string, desired, existing map[string]*v1beta1.Release, expired bool) []string {
+	var failed []string
+	for _, pc := range pcs {
+		c, ok := existing[pc]
+		if !ok || !upToDate(c, desired[pc]) {
+			continue
+		}
+		if c.Status.AtProvider.State == common.StatusFailed || testsFailed(c) || (expired && !available(c)) {
+			failed = append(failed, pc)
+		}
+	}
+	return failed
+}
+
+// halt returns true if more Releases failed than the failure threshold of
+// the ReleaseSet allows.
+func halt(rs *v1beta1.ReleaseSet, failed, total int) bool {
+	t := rs.Spec.Rollout.FailureThreshold
+	if t == nil {
+		return false
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(t, total, false)
+	if err != nil {
+		return false
+	}
+	return failed > n
+}
+
+// waveDone returns true if every Release of the wave is up to date,
+// available and tested, or failed within the failure threshold.
+func waveDone(w wave, desired, existing map[string]*v1beta1.Release, failed sets.Set[string]) bool {
+	for _, pc := range w.pcs {
+		if failed.Has(pc) {
+			continue
+		}
+		c, ok := existing[pc]
+		if !ok || !upToDate(c, desired[pc]) || !available(c) || !tested(c) {
+			return false
+		}
+	}
+	return true
+}
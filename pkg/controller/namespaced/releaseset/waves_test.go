@@ -0,0 +1,391 @@
+package releaseset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"helm.sh/helm/v4/pkg/release/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane-contrib/provider-helm/apis/namespaced/release/v1beta1"
+)
+
+func withWaves(waves ...v1beta1.RolloutWave) releaseSetModifier {
+	return func(rs *v1beta1.ReleaseSet) {
+		rs.Spec.Rollout.Waves = waves
+	}
+}
+
+func TestRolloutWaves(t *testing.T) {
+	pcs := []string{"eu-1", "eu-2", "us-1", "us-2"}
+	pcLabels := map[string]map[string]string{
+		"eu-1": {"region": "eu", "canary": "true"},
+		"eu-2": {"region": "eu"},
+		"us-1": {"region": "us", "canary": "true"},
+		"us-2": {"region": "us"},
+	}
+	canary := v1beta1.RolloutWave{Name: "canary", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+	eu := v1beta1.RolloutWave{Name: "eu", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}}
+
+	cases := map[string]struct {
+		rs   *v1beta1.ReleaseSet
+		want []wave
+	}{
+		"NoWaves": {
+			rs:   releaseSet(),
+			want: []wave{{name: defaultWaveName, pcs: pcs}},
+		},
+		"FirstMatchingWave": {
+			rs: releaseSet(withWaves(canary, eu)),
+			want: []wave{
+				{name: "canary", pcs: []string{"eu-1", "us-1"}},
+				{name: "eu", pcs: []string{"eu-2"}},
+				{name: defaultWaveName, pcs: []string{"us-2"}},
+			},
+		},
+		"AllSelected": {
+			rs: releaseSet(withWaves(canary, v1beta1.RolloutWave{Name: "rest"})),
+			want: []wave{
+				{name: "canary", pcs: []string{"eu-1", "us-1"}},
+				{name: "rest", pcs: []string{"eu-2", "us-2"}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := rolloutWaves(tc.rs, pcs, pcLabels)
+			if err != nil {
+				t.Fatalf("rolloutWaves(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(wave{})); diff != "" {
+				t.Errorf("rolloutWaves(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProgressRollout(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	waves := []wave{{name: "canary", pcs: []string{"a"}}, {name: defaultWaveName, pcs: []string{"b", "c"}}}
+	old := releaseSet()
+	target := func(mods ...releaseSetModifier) *v1beta1.ReleaseSet {
+		return releaseSet(append([]releaseSetModifier{withVersion(testNewVersion)}, mods...)...)
+	}
+	inProgress := func(rs *v1beta1.ReleaseSet, wave int32, started time.Time, soaking *time.Time) releaseSetModifier {
+		return func(s *v1beta1.ReleaseSet) {
+			rev, err := rolloutRevision(rs)
+			if err != nil {
+				t.Fatalf("rolloutRevision(...): %v", err)
+			}
+			s.Status.Rollout = &v1beta1.ReleaseSetRolloutStatus{Revision: rev, Wave: wave, WaveStartTime: &metav1.Time{Time: started}}
+			if soaking != nil {
+				s.Status.Rollout.SoakStartTime = &metav1.Time{Time: *soaking}
+			}
+		}
+	}
+	soak := func(d time.Duration) releaseSetModifier {
+		return func(rs *v1beta1.ReleaseSet) {
+			rs.Spec.Rollout.SoakDuration = &metav1.Duration{Duration: d}
+		}
+	}
+	threshold := func(v intstr.IntOrString) releaseSetModifier {
+		return func(rs *v1beta1.ReleaseSet) {
+			rs.Spec.Rollout.FailureThreshold = &v
+		}
+	}
+	failed := func(r *v1beta1.Release) *v1beta1.Release {
+		r.Status.AtProvider.State = common.StatusFailed
+		return r
+	}
+	runTests := func(rs *v1beta1.ReleaseSet) {
+		rs.Spec.Template.Spec.ForProvider.RunTests = true
+	}
+	testedWith := func(p v1beta1.TestPhase, r *v1beta1.Release) *v1beta1.Release {
+		r.Status.AtProvider.Test = &v1beta1.ReleaseTestObservation{Revision: r.Status.AtProvider.Revision, Phase: p}
+		return r
+	}
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	type want struct {
+		active  []string
+		halted  bool
+		requeue time.Duration
+		status  *v1beta1.ReleaseSetRolloutStatus
+	}
+	cases := map[string]struct {
+		rs       func() *v1beta1.ReleaseSet
+		existing func(t *testing.T) map[string]*v1beta1.Release
+		want     want
+	}{
+		"StartFirstWave": {
+			rs: func() *v1beta1.ReleaseSet { return target() },
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, old, "a", true), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a"},
+				requeue: defaultProgressDeadline,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutProgressing,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now},
+					Message:       `rolling out wave "canary"`,
+				},
+			},
+		},
+		"StartSoaking": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(soak(time.Hour), inProgress(target(), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, target(), "a", true), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a"},
+				requeue: time.Hour,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutSoaking,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					SoakStartTime: &metav1.Time{Time: now},
+					Message:       `wave "canary" is available, soaking until 2025-06-01T13:00:00Z`,
+				},
+			},
+		},
+		"NextWaveAfterSoak": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(soak(time.Hour), inProgress(target(), 0, now.Add(-2*time.Hour), ago(time.Hour)))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, target(), "a", true), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a", "b", "c"},
+				requeue: defaultProgressDeadline,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutProgressing,
+					Wave:          1,
+					WaveName:      defaultWaveName,
+					WaveStartTime: &metav1.Time{Time: now},
+					Message:       `rolling out wave "default"`,
+				},
+			},
+		},
+		"Completed": {
+			rs: func() *v1beta1.ReleaseSet { return target(inProgress(target(), 1, now.Add(-time.Minute), nil)) },
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, target(), "a", true), "b": child(t, target(), "b", true), "c": child(t, target(), "c", true)}
+			},
+			want: want{
+				active: []string{"a", "b", "c"},
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutCompleted,
+					Wave:          1,
+					WaveName:      defaultWaveName,
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					Message:       msgRolloutCompleted,
+				},
+			},
+		},
+		"HaltOnFailedRelease": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(threshold(intstr.FromInt32(0)), inProgress(target(), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": failed(child(t, target(), "a", false)), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active: []string{"a"},
+				halted: true,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutHalted,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					Failed:        []string{"a"},
+					Message:       "rollout halted, releases failed for a",
+				},
+			},
+		},
+		"WaitForTests": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(runTests, inProgress(target(runTests), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, target(runTests), "a", true), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a"},
+				requeue: defaultProgressDeadline - time.Minute,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutProgressing,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					Message:       `rolling out wave "canary"`,
+				},
+			},
+		},
+		"NextWaveAfterTests": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(runTests, inProgress(target(runTests), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": testedWith(v1beta1.TestSucceeded, child(t, target(runTests), "a", true)), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a", "b", "c"},
+				requeue: defaultProgressDeadline,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutProgressing,
+					Wave:          1,
+					WaveName:      defaultWaveName,
+					WaveStartTime: &metav1.Time{Time: now},
+					Message:       `rolling out wave "default"`,
+				},
+			},
+		},
+		"HaltOnFailedTests": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(runTests, threshold(intstr.FromInt32(0)), inProgress(target(runTests), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": testedWith(v1beta1.TestFailed, child(t, target(runTests), "a", true)), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active: []string{"a"},
+				halted: true,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutHalted,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					Failed:        []string{"a"},
+					Message:       "rollout halted, releases failed for a",
+				},
+			},
+		},
+		"HaltOnProgressDeadline": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(threshold(intstr.FromInt32(0)), inProgress(target(), 0, now.Add(-time.Hour), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, target(), "a", false), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active: []string{"a"},
+				halted: true,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutHalted,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Hour)},
+					Failed:        []string{"a"},
+					Message:       "rollout halted, releases failed for a",
+				},
+			},
+		},
+		"ToleratedFailure": {
+			rs: func() *v1beta1.ReleaseSet {
+				return target(threshold(intstr.FromString("50%")), inProgress(target(), 0, now.Add(-time.Minute), nil))
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": failed(child(t, target(), "a", false)), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active:  []string{"a", "b", "c"},
+				requeue: defaultProgressDeadline,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutProgressing,
+					Wave:          1,
+					WaveName:      defaultWaveName,
+					WaveStartTime: &metav1.Time{Time: now},
+					Failed:        []string{"a"},
+					Message:       `rolling out wave "default"`,
+				},
+			},
+		},
+		"StayHaltedAfterRollback": {
+			rs: func() *v1beta1.ReleaseSet {
+				rs := target(inProgress(target(), 0, now.Add(-time.Minute), nil))
+				rs.Status.Rollout.Phase = v1beta1.RolloutHalted
+				rs.Status.Rollout.Failed = []string{"a"}
+				rs.Status.Rollout.Message = "rollout halted, releases failed for a"
+				rs.Status.Rollout.RolledBack = true
+				return rs
+			},
+			existing: func(t *testing.T) map[string]*v1beta1.Release {
+				return map[string]*v1beta1.Release{"a": child(t, old, "a", true), "b": child(t, old, "b", true), "c": child(t, old, "c", true)}
+			},
+			want: want{
+				active: []string{"a"},
+				halted: true,
+				status: &v1beta1.ReleaseSetRolloutStatus{
+					Phase:         v1beta1.RolloutHalted,
+					WaveName:      "canary",
+					WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					Failed:        []string{"a"},
+					RolledBack:    true,
+					Message:       "rollout halted, releases failed for a",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rs := tc.rs()
+			got := want{}
+			var err error
+			got.active, got.halted, got.requeue, err = progressRollout(rs, waves, desiredReleases(t, rs, "a", "b", "c"), tc.existing(t), now)
+			if err != nil {
+				t.Fatalf("progressRollout(...): %v", err)
+			}
+			got.status = rs.Status.Rollout
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmpopts.IgnoreFields(v1beta1.ReleaseSetRolloutStatus{}, "Revision")); diff != "" {
+				t.Errorf("progressRollout(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRollbackRelease(t *testing.T) {
+	old := releaseSet()
+	rs := releaseSet(withVersion(testNewVersion))
+
+	c := child(t, old, "a", true)
+	prev := c.DeepCopy()
+	d, err := desiredRelease(rs, "a")
+	if err != nil {
+		t.Fatalf("desiredRelease(...): %v", err)
+	}
+	if err := updateRelease(c, d); err != nil {
+		t.Fatalf("updateRelease(...): %v", err)
+	}
+	if !upToDate(c, d) {
+		t.Fatalf("updateRelease(...): Release is not up to date")
+	}
+
+	rolled, err := rollbackRelease(c, d)
+	if err != nil {
+		t.Fatalf("rollbackRelease(...): %v", err)
+	}
+	if !rolled {
+		t.Fatalf("rollbackRelease(...): want rolled back")
+	}
+	if diff := cmp.Diff(prev.Spec, c.Spec); diff != "" {
+		t.Errorf("rollbackRelease(...): -want spec, +got spec:\n%s", diff)
+	}
+	if diff := cmp.Diff(prev.GetAnnotations(), c.GetAnnotations()); diff != "" {
+		t.Errorf("rollbackRelease(...): -want annotations, +got annotations:\n%s", diff)
+	}
+
+	rolled, err = rollbackRelease(c, d)
+	if err != nil {
+		t.Fatalf("rollbackRelease(...): %v", err)
+	}
+	if rolled {
+		t.Errorf("rollbackRelease(...): rolled back twice")
+	}
+}
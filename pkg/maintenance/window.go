@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance evaluates recurring maintenance windows.
+package maintenance
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+const (
+	errInvalidScheduleTmpl = "invalid schedule %q: want five fields: minute hour day-of-month month day-of-week"
+	errInvalidFieldTmpl    = "invalid %s field %q"
+	errInvalidTimeZoneTmpl = "invalid time zone %q"
+	errInvalidDuration     = "duration must be positive"
+)
+
+// searchDays bounds the search for the next start of a window, as some
+// schedules never match, like the 30th of February.
+const searchDays = 5 * 366
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = [5]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day-of-week", min: 0, max: 7},
+}
+
+// A Window is a maintenance window that starts on a cron schedule and lasts
+// for a fixed duration.
+type Window struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are set if the day-of-month or day-of-week fields
+	// are unrestricted. Like cron, a day matches either restricted field if
+	// both are restricted.
+	domAny, dowAny bool
+
+	duration time.Duration
+	location *time.Location
+}
+
+// NewWindow returns a maintenance window from a cron schedule with five
+// fields, a duration, and an IANA time zone, UTC if empty.
+func NewWindow(schedule string, duration time.Duration, timeZone string) (*Window, error) {
+	if duration <= 0 {
+		return nil, errors.New(errInvalidDuration)
+	}
+	loc := time.UTC
+	if timeZone != "" {
+		l, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, errInvalidTimeZoneTmpl, timeZone)
+		}
+		loc = l
+	}
+
+	parts := strings.Fields(schedule)
+	if len(parts) != len(fields) {
+		return nil, errors.Errorf(errInvalidScheduleTmpl, schedule)
+	}
+	var bits [5]uint64
+	for i, p := range parts {
+		b, err := parseField(p, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	// Sunday is both 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Window{
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		domAny:   parts[2] == "*",
+		dowAny:   parts[4] == "*",
+		duration: duration,
+		location: loc,
+	}, nil
+}
+
+// parseField parses a comma separated list of values, ranges, and steps like
+// "*/15", "1-5" or "0,30" into a bit set.
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+		if r, st, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(st)
+			if err != nil || n < 1 {
+				return 0, errors.Errorf(errInvalidFieldTmpl, f.name, s)
+			}
+			rng, step = r, n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			l, h, _ := strings.Cut(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(l)
+			hi, err2 = strconv.Atoi(h)
+			if err1 != nil || err2 != nil {
+				return 0, errors.Errorf(errInvalidFieldTmpl, f.name, s)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, errors.Errorf(errInvalidFieldTmpl, f.name, s)
+			}
+			lo, hi = n, n
+			// Like cron, a single value with a step runs to the maximum.
+			if step > 1 {
+				hi = f.max
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, errors.Errorf(errInvalidFieldTmpl, f.name, s)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first start of the window after the supplied time, or the
+// zero time if the schedule never matches.
+func (w *Window) Next(t time.Time) time.Time {
+	t = t.In(w.location).Truncate(time.Minute).Add(time.Minute)
+	y, mo, d := t.Date()
+	h, m := t.Hour(), t.Minute()
+
+	for i := 0; i < searchDays; i++ {
+		day := time.Date(y, mo, d+i, 0, 0, 0, 0, w.location)
+		if i > 0 {
+			h, m = 0, 0
+		}
+		if !w.matchesDay(day) {
+			continue
+		}
+		for ; h < 24; h, m = h+1, 0 {
+			if w.hour&(1<<uint(h)) == 0 {
+				continue
+			}
+			for ; m < 60; m++ {
+				if w.minute&(1<<uint(m)) == 0 {
+					continue
+				}
+				start := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, w.location)
+				// Times skipped by daylight saving changes are normalized
+				// to a later hour, which may not match the schedule.
+				if start.Hour() == h && start.Minute() == m {
+					return start
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Active returns true if the supplied time is within the window.
+func (w *Window) Active(t time.Time) bool {
+	s := w.Next(t.Add(-w.duration))
+	return !s.IsZero() && !s.After(t)
+}
+
+func (w *Window) matchesDay(day time.Time) bool {
+	if w.month&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	dom := w.dom&(1<<uint(day.Day())) != 0
+	dow := w.dow&(1<<uint(day.Weekday())) != 0
+	switch {
+	case w.domAny && w.dowAny:
+		return true
+	case w.domAny:
+		return dow
+	case w.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Active returns true if the supplied time is within any of the windows.
+func Active(windows []*Window, t time.Time) bool {
+	for _, w := range windows {
+		if w.Active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStart returns the first start of any of the windows after the supplied
+// time, or the zero time if none of them ever starts.
+func NextStart(windows []*Window, t time.Time) time.Time {
+	var next time.Time
+	for _, w := range windows {
+		if n := w.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
@@ -0,0 +1,184 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func mustWindow(t *testing.T, schedule string, d time.Duration, tz string) *Window {
+	t.Helper()
+	w, err := NewWindow(schedule, d, tz)
+	if err != nil {
+		t.Fatalf("NewWindow(%q): %v", schedule, err)
+	}
+	return w
+}
+
+func TestNewWindow(t *testing.T) {
+	cases := map[string]struct {
+		schedule string
+		duration time.Duration
+		tz       string
+		wantErr  bool
+	}{
+		"Valid": {
+			schedule: "0 2 * * 1-5",
+			duration: time.Hour,
+			tz:       "Europe/Berlin",
+		},
+		"Steps": {
+			schedule: "*/15 0-6/2 1,15 * 0,7",
+			duration: time.Minute,
+		},
+		"TooFewFields": {
+			schedule: "0 2 * *",
+			duration: time.Hour,
+			wantErr:  true,
+		},
+		"OutOfRange": {
+			schedule: "0 24 * * *",
+			duration: time.Hour,
+			wantErr:  true,
+		},
+		"InvalidStep": {
+			schedule: "*/0 * * * *",
+			duration: time.Hour,
+			wantErr:  true,
+		},
+		"InvalidTimeZone": {
+			schedule: "0 2 * * *",
+			duration: time.Hour,
+			tz:       "Mars/Olympus",
+			wantErr:  true,
+		},
+		"NoDuration": {
+			schedule: "0 2 * * *",
+			wantErr:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewWindow(tc.schedule, tc.duration, tc.tz)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("NewWindow(...): want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWindowNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	cases := map[string]struct {
+		schedule string
+		tz       string
+		from     time.Time
+		want     time.Time
+	}{
+		"LaterToday": {
+			schedule: "30 2 * * *",
+			from:     time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 10, 2, 30, 0, 0, time.UTC),
+		},
+		"Tomorrow": {
+			schedule: "30 2 * * *",
+			from:     time.Date(2025, 3, 10, 2, 30, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 11, 2, 30, 0, 0, time.UTC),
+		},
+		"Weekday": {
+			schedule: "0 22 * * 6",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC),
+		},
+		"SundayAsSeven": {
+			schedule: "0 0 * * 7",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+		},
+		"DayOfMonthOrWeek": {
+			schedule: "0 0 20 * 6",
+			from:     time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+		},
+		"NextMonth": {
+			schedule: "0 0 1 * *",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"TimeZone": {
+			schedule: "0 2 * * *",
+			tz:       "Europe/Berlin",
+			from:     time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 1, 11, 2, 0, 0, 0, berlin),
+		},
+		"SkippedByDaylightSaving": {
+			schedule: "30 2 * * *",
+			tz:       "Europe/Berlin",
+			from:     time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 31, 2, 30, 0, 0, berlin),
+		},
+		"Never": {
+			schedule: "0 0 30 2 *",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := mustWindow(t, tc.schedule, time.Hour, tc.tz).Next(tc.from)
+			if !got.Equal(tc.want) {
+				t.Errorf("Next(%v): want %v, got %v", tc.from, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestWindowActive(t *testing.T) {
+	w := mustWindow(t, "0 22 * * 6", 4*time.Hour, "")
+
+	cases := map[string]struct {
+		at   time.Time
+		want bool
+	}{
+		"Before": {
+			at: time.Date(2025, 3, 15, 21, 59, 0, 0, time.UTC),
+		},
+		"AtStart": {
+			at:   time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		"PastMidnight": {
+			at:   time.Date(2025, 3, 16, 1, 59, 59, 0, time.UTC),
+			want: true,
+		},
+		"AtEnd": {
+			at: time.Date(2025, 3, 16, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, w.Active(tc.at)); diff != "" {
+				t.Errorf("Active(%v): -want, +got:\n%s", tc.at, diff)
+			}
+		})
+	}
+}
+
+func TestNextStart(t *testing.T) {
+	windows := []*Window{
+		mustWindow(t, "0 22 * * 6", time.Hour, ""),
+		mustWindow(t, "0 3 * * 3", time.Hour, ""),
+	}
+	from := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	want := time.Date(2025, 3, 12, 3, 0, 0, 0, time.UTC)
+	if got := NextStart(windows, from); !got.Equal(want) {
+		t.Errorf("NextStart(...): want %v, got %v", want, got)
+	}
+	if Active(windows, from) {
+		t.Errorf("Active(...): want false")
+	}
+}
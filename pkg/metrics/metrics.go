@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exposes Prometheus metrics of the Helm operations of the
+// provider.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "provider_helm"
+
+// Results of an operation.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Results of a chart cache lookup.
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Sources of registry credentials.
+const (
+	AuthSourceSecret    = "secret"
+	AuthSourceECR       = "ecr"
+	AuthSourceGCP       = "gcp"
+	AuthSourceACR       = "acr"
+	AuthSourceDocker    = "docker"
+	AuthSourceAnonymous = "anonymous"
+)
+
+var (
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of Helm install, upgrade, rollback and uninstall operations.",
+		Buckets:   []float64{0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{"operation", "chart", "result"})
+
+	chartPullDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "chart_pull_duration_seconds",
+		Help:      "Duration of chart pulls.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{"repository", "result"})
+
+	chartPullBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "chart_pull_bytes_total",
+		Help:      "Bytes of the charts pulled.",
+	}, []string{"repository"})
+
+	chartCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "chart_cache_lookups_total",
+		Help:      "Lookups of charts in the local chart cache, by hit or miss.",
+	}, []string{"result"})
+
+	registryAuth = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registry_auth_total",
+		Help:      "Resolutions of registry credentials, by the source of the credentials.",
+	}, []string{"source"})
+
+	releaseInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "release_info",
+		Help:      "The chart deployed by a Release. Always 1.",
+	}, []string{"kind", "namespace", "name", "chart", "version", "app_version"})
+)
+
+// Register the metrics with the supplied registry.
+func Register(r prometheus.Registerer) {
+	r.MustRegister(operationDuration, chartPullDuration, chartPullBytes, chartCacheLookups, registryAuth, releaseInfo)
+}
+
+// Result returns the result label of an operation that returned the supplied
+// error.
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// ObserveOperation records a Helm operation on a release of the supplied
+// chart that started at the supplied time.
+func ObserveOperation(operation, chart string, start time.Time, err error) {
+	operationDuration.WithLabelValues(operation, chart, Result(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveChartPull records a pull of a chart from the supplied repository
+// that started at the supplied time.
+func ObserveChartPull(repository string, start time.Time, bytes int64, err error) {
+	chartPullDuration.WithLabelValues(repository, Result(err)).Observe(time.Since(start).Seconds())
+	if err == nil {
+		chartPullBytes.WithLabelValues(repository).Add(float64(bytes))
+	}
+}
+
+// ObserveChartCacheLookup records a lookup of a chart in the chart cache.
+func ObserveChartCacheLookup(result string) {
+	chartCacheLookups.WithLabelValues(result).Inc()
+}
+
+// ObserveRegistryAuth records a resolution of registry credentials.
+func ObserveRegistryAuth(source string) {
+	registryAuth.WithLabelValues(source).Inc()
+}
+
+// SetReleaseInfo records the chart version deployed by a Release.
+func SetReleaseInfo(kind, ns, name, chart, version, appVersion string) {
+	DeleteReleaseInfo(kind, ns, name)
+	releaseInfo.WithLabelValues(kind, ns, name, chart, version, appVersion).Set(1)
+}
+
+// DeleteReleaseInfo stops recording the chart version deployed by a Release.
+func DeleteReleaseInfo(kind, ns, name string) {
+	releaseInfo.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": ns, "name": name})
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSetReleaseInfo(t *testing.T) {
+	releaseInfo.Reset()
+
+	SetReleaseInfo("Release.helm.crossplane.io", "", "wordpress", "wordpress", "15.2.5", "6.1.1")
+	SetReleaseInfo("Release.helm.crossplane.io", "", "wordpress", "wordpress", "15.2.6", "6.1.1")
+	SetReleaseInfo("Release.helm.crossplane.io", "", "redis", "redis", "17.0.0", "7.0.0")
+
+	want := `
+# HELP provider_helm_release_info The chart deployed by a Release. Always 1.
+# TYPE provider_helm_release_info gauge
+provider_helm_release_info{app_version="6.1.1",chart="wordpress",kind="Release.helm.crossplane.io",name="wordpress",namespace="",version="15.2.6"} 1
+provider_helm_release_info{app_version="7.0.0",chart="redis",kind="Release.helm.crossplane.io",name="redis",namespace="",version="17.0.0"} 1
+`
+	if err := testutil.CollectAndCompare(releaseInfo, strings.NewReader(want)); err != nil {
+		t.Errorf("SetReleaseInfo(...): %v", err)
+	}
+
+	DeleteReleaseInfo("Release.helm.crossplane.io", "", "wordpress")
+	if diff := cmp.Diff(1, testutil.CollectAndCount(releaseInfo)); diff != "" {
+		t.Errorf("DeleteReleaseInfo(...): -want series, +got series: %s", diff)
+	}
+}
+
+func TestObserveChartPull(t *testing.T) {
+	chartPullBytes.Reset()
+	chartPullDuration.Reset()
+
+	ObserveChartPull("https://charts.bitnami.com/bitnami", time.Now(), 1024, nil)
+	ObserveChartPull("https://charts.bitnami.com/bitnami", time.Now(), 1024, errors.New("boom"))
+
+	if diff := cmp.Diff(1024.0, testutil.ToFloat64(chartPullBytes.WithLabelValues("https://charts.bitnami.com/bitnami"))); diff != "" {
+		t.Errorf("ObserveChartPull(...): want bytes of successful pulls only, -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(2, testutil.CollectAndCount(chartPullDuration)); diff != "" {
+		t.Errorf("ObserveChartPull(...): want a series per result, -want, +got: %s", diff)
+	}
+}
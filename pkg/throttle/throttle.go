@@ -0,0 +1,307 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle limits the operations and requests Releases send to their
+// target clusters.
+package throttle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const errNegativeLimitTmpl = "invalid %s %s: must not be negative"
+
+// staleAfter is how long a waiter keeps its place in the queue of a target
+// without trying again.
+const staleAfter = 2 * time.Minute
+
+// Limits of a target cluster.
+type Limits struct {
+	// MaxConcurrentOperations is the maximum number of installs and upgrades
+	// running against the target at the same time. Unlimited if zero.
+	MaxConcurrentOperations int
+	// QPS is the maximum rate of requests per second to the target, shared
+	// by all Releases. Unlimited if zero.
+	QPS float32
+	// Burst is the maximum burst of requests to the target. Defaults to QPS.
+	Burst int
+}
+
+// LimitsFor returns the supplied default limits, overridden by those a
+// ProviderConfig sets. Limits it does not set keep their defaults.
+func LimitsFor(defaults Limits, maxConcurrentOperations *int, qps *resource.Quantity, burst *int) (Limits, error) {
+	l := defaults
+	if maxConcurrentOperations != nil {
+		if *maxConcurrentOperations < 0 {
+			return Limits{}, errors.Errorf(errNegativeLimitTmpl, "maximum of concurrent operations", strconv.Itoa(*maxConcurrentOperations))
+		}
+		l.MaxConcurrentOperations = *maxConcurrentOperations
+	}
+	if qps != nil {
+		if qps.Sign() < 0 {
+			return Limits{}, errors.Errorf(errNegativeLimitTmpl, "QPS", qps.String())
+		}
+		l.QPS = float32(qps.AsApproximateFloat64())
+	}
+	if burst != nil {
+		if *burst < 0 {
+			return Limits{}, errors.Errorf(errNegativeLimitTmpl, "burst", strconv.Itoa(*burst))
+		}
+		l.Burst = *burst
+	}
+	return l, nil
+}
+
+// strictest returns the strictest of the supplied limits, each of which is
+// unlimited if zero.
+func strictest(ls map[string]Limits) Limits {
+	var s Limits
+	for _, l := range ls {
+		s.MaxConcurrentOperations = lower(s.MaxConcurrentOperations, l.MaxConcurrentOperations)
+		if l.QPS <= 0 {
+			continue
+		}
+		if s.QPS <= 0 || l.QPS < s.QPS {
+			s.QPS = l.QPS
+		}
+		s.Burst = lower(s.Burst, burstOf(l))
+	}
+	return s
+}
+
+// lower returns the lower of two limits, each of which is unlimited if zero.
+func lower(a, b int) int {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// burstOf returns the burst of the supplied limits, defaulting to their QPS.
+func burstOf(l Limits) int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return max(int(l.QPS), 1)
+}
+
+type waiter struct {
+	id   string
+	seen time.Time
+}
+
+type target struct {
+	running map[string]bool
+	queue   []waiter
+
+	// limits are those of each provider config connecting to the target.
+	limits map[string]Limits
+
+	qps     float32
+	burst   int
+	limiter flowcontrol.RateLimiter
+}
+
+// A Registry tracks the operations running against, and the requests sent
+// to, target clusters.
+type Registry struct {
+	mu       sync.Mutex
+	targets  map[string]*target
+	configs  map[string]string
+	defaults Limits
+	now      func() time.Time
+}
+
+// NewRegistry returns a Registry with the supplied default limits.
+func NewRegistry(defaults Limits) *Registry {
+	return &Registry{targets: map[string]*target{}, configs: map[string]string{}, defaults: defaults, now: time.Now}
+}
+
+// Targets tracks the target clusters of all Releases of the provider.
+var Targets = NewRegistry(Limits{})
+
+// SetDefaults sets the limits of target clusters whose ProviderConfigs do not
+// override them.
+func (r *Registry) SetDefaults(l Limits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults = l
+}
+
+// Defaults returns the limits of target clusters whose ProviderConfigs do not
+// override them.
+func (r *Registry) Defaults() Limits {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.defaults
+}
+
+func (r *Registry) target(key string) *target {
+	t, ok := r.targets[key]
+	if !ok {
+		t = &target{running: map[string]bool{}, limits: map[string]Limits{}}
+		r.targets[key] = t
+	}
+	return t
+}
+
+// SetLimits records the limits the supplied provider config sets for the
+// target it connects to, and returns the limits of the target, i.e. the
+// strictest limits of all provider configs connecting to it. A provider config
+// that connects to another target no longer limits the previous one.
+func (r *Registry) SetLimits(key, providerConfig string, l Limits) Limits {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.configs[providerConfig]; ok && prev != key {
+		if t, ok := r.targets[prev]; ok {
+			delete(t.limits, providerConfig)
+		}
+	}
+	r.configs[providerConfig] = key
+	t := r.target(key)
+	t.limits[providerConfig] = l
+	return strictest(t.limits)
+}
+
+// TargetLimits returns the limits the target the supplied provider config
+// last connected to would have if the provider config set the supplied
+// limits. It returns the supplied limits if the provider config has not
+// connected to a target yet.
+func (r *Registry) TargetLimits(providerConfig string, l Limits) Limits {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.configs[providerConfig]
+	if !ok {
+		return l
+	}
+	ls := maps.Clone(r.targets[key].limits)
+	ls[providerConfig] = l
+	return strictest(ls)
+}
+
+// TryAcquire tries to acquire an operation slot of the target for the
+// supplied waiter. Slots are granted in the order waiters first tried to
+// acquire one. It returns a function that releases the slot if one was
+// granted, and otherwise the position of the waiter in the queue.
+func (r *Registry) TryAcquire(key, id string, l Limits) (func(), int) {
+	if l.MaxConcurrentOperations <= 0 {
+		return func() {}, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	t := r.target(key)
+	release := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(t.running, id)
+	}
+	if t.running[id] {
+		return release, 0
+	}
+
+	queue := t.queue[:0]
+	ahead := -1
+	for _, w := range t.queue {
+		if now.Sub(w.seen) > staleAfter && w.id != id {
+			continue
+		}
+		if w.id == id {
+			w.seen = now
+			ahead = len(queue)
+		}
+		queue = append(queue, w)
+	}
+	t.queue = queue
+	if ahead < 0 {
+		ahead = len(t.queue)
+		t.queue = append(t.queue, waiter{id: id, seen: now})
+	}
+
+	if ahead < l.MaxConcurrentOperations-len(t.running) {
+		t.queue = append(t.queue[:ahead], t.queue[ahead+1:]...)
+		t.running[id] = true
+		return release, 0
+	}
+	return nil, ahead + 1
+}
+
+// Leave removes the supplied waiter from the queue of the target, so that
+// waiters behind it do not wait for it any longer. It is a no-op if the
+// waiter holds a slot or is not queued.
+func (r *Registry) Leave(key, id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.targets[key]
+	if !ok {
+		return
+	}
+	for i, w := range t.queue {
+		if w.id == id {
+			t.queue = append(t.queue[:i], t.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// TargetKey returns the key of the target cluster a REST config connects to.
+// Configs connecting to the same API server with the same CA share a key, and
+// thereby their limits, operation slots and rate limiter, even if they belong
+// to different provider configs.
+func TargetKey(rc *rest.Config) string {
+	if rc == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(rc.CAData)
+	h.Write([]byte{0})
+	h.Write([]byte(rc.CAFile))
+	return fmt.Sprintf("%s/%x", rc.Host, h.Sum(nil)[:8])
+}
+
+// RateLimiter returns the rate limiter shared by all requests to the target,
+// or nil if they are not limited.
+func (r *Registry) RateLimiter(key string, l Limits) flowcontrol.RateLimiter {
+	if l.QPS <= 0 {
+		return nil
+	}
+	burst := burstOf(l)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := r.target(key)
+	if t.limiter == nil || t.qps != l.QPS || t.burst != burst {
+		t.qps, t.burst = l.QPS, burst
+		t.limiter = flowcontrol.NewTokenBucketRateLimiter(l.QPS, burst)
+	}
+	return t.limiter
+}
@@ -0,0 +1,264 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/rest"
+)
+
+func TestLimitsFor(t *testing.T) {
+	defaults := Limits{MaxConcurrentOperations: 2, QPS: 5, Burst: 10}
+	one, three, negative := 1, 3, -1
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+
+	type args struct {
+		maxConcurrentOperations *int
+		qps                     *resource.Quantity
+		burst                   *int
+	}
+	type want struct {
+		l   Limits
+		err error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Defaults": {
+			want: want{l: defaults},
+		},
+		"Overridden": {
+			args: args{
+				maxConcurrentOperations: &one,
+				qps:                     quantity("500m"),
+				burst:                   &three,
+			},
+			want: want{l: Limits{MaxConcurrentOperations: 1, QPS: 0.5, Burst: 3}},
+		},
+		"Unlimited": {
+			args: args{qps: quantity("0")},
+			want: want{l: Limits{MaxConcurrentOperations: 2, Burst: 10}},
+		},
+		"NegativeQPS": {
+			args: args{qps: quantity("-1")},
+			want: want{
+				err: errors.Errorf(errNegativeLimitTmpl, "QPS", "-1"),
+			},
+		},
+		"NegativeBurst": {
+			args: args{burst: &negative},
+			want: want{
+				err: errors.Errorf(errNegativeLimitTmpl, "burst", "-1"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l, err := LimitsFor(defaults, tc.args.maxConcurrentOperations, tc.args.qps, tc.args.burst)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("LimitsFor(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.l, l); diff != "" {
+				t.Errorf("LimitsFor(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSetLimits(t *testing.T) {
+	r := NewRegistry(Limits{})
+
+	// Two provider configs connect to the same target. The target is limited
+	// by the strictest of their limits, whichever connected last.
+	a := Limits{MaxConcurrentOperations: 1, QPS: 20, Burst: 40}
+	b := Limits{MaxConcurrentOperations: 4, QPS: 5}
+	want := Limits{MaxConcurrentOperations: 1, QPS: 5, Burst: 5}
+	if diff := cmp.Diff(a, r.SetLimits("target", "a", a)); diff != "" {
+		t.Errorf("SetLimits(...): -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(want, r.SetLimits("target", "b", b)); diff != "" {
+		t.Errorf("SetLimits(...): -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(want, r.SetLimits("target", "a", a)); diff != "" {
+		t.Errorf("SetLimits(...): limits should not depend on the order provider configs connect in: -want, +got: %s", diff)
+	}
+
+	// Operation slots and the rate limiter are shared at the strictest limits.
+	if release, _ := r.TryAcquire("target", "release-of-a", want); release == nil {
+		t.Errorf("TryAcquire(...): want acquired")
+	}
+	if release, position := r.TryAcquire("target", "release-of-b", want); release != nil || position != 1 {
+		t.Errorf("TryAcquire(...): want queued at position 1 behind the release of the other provider config, got position %d", position)
+	}
+	if diff := cmp.Diff(float32(5), r.RateLimiter("target", want).QPS()); diff != "" {
+		t.Errorf("RateLimiter(...): -want QPS, +got QPS: %s", diff)
+	}
+
+	// A provider config that lifts its limits leaves the target limited by
+	// those of the other.
+	if diff := cmp.Diff(Limits{MaxConcurrentOperations: 4, QPS: 5, Burst: 5}, r.TargetLimits("a", Limits{})); diff != "" {
+		t.Errorf("TargetLimits(...): -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(Limits{QPS: 1}, r.TargetLimits("unknown", Limits{QPS: 1})); diff != "" {
+		t.Errorf("TargetLimits(...): a provider config that has not connected should have its own limits: -want, +got: %s", diff)
+	}
+
+	// A provider config that connects to another target no longer limits
+	// its previous one.
+	r.SetLimits("other", "b", b)
+	if diff := cmp.Diff(a, r.TargetLimits("a", a)); diff != "" {
+		t.Errorf("TargetLimits(...): -want, +got: %s", diff)
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	l := Limits{MaxConcurrentOperations: 1}
+
+	type step struct {
+		id       string
+		release  bool
+		acquired bool
+		position int
+	}
+	cases := map[string]struct {
+		limits Limits
+		steps  []step
+	}{
+		"Unlimited": {
+			steps: []step{
+				{id: "a", acquired: true},
+				{id: "b", acquired: true},
+			},
+		},
+		"Exhausted": {
+			limits: l,
+			steps: []step{
+				{id: "a", acquired: true},
+				{id: "b", position: 1},
+				{id: "c", position: 2},
+				{id: "a", acquired: true},
+			},
+		},
+		"Fair": {
+			limits: l,
+			steps: []step{
+				{id: "a", acquired: true},
+				{id: "b", position: 1},
+				{id: "c", position: 2},
+				{id: "a", release: true},
+				// A slot is free, but b waits longer than c.
+				{id: "c", position: 2},
+				{id: "b", acquired: true},
+				{id: "b", release: true},
+				{id: "c", acquired: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry(Limits{})
+			held := map[string]func(){}
+			for i, s := range tc.steps {
+				if s.release {
+					held[s.id]()
+					continue
+				}
+				release, position := r.TryAcquire("target", s.id, tc.limits)
+				if got := release != nil; got != s.acquired {
+					t.Fatalf("step %d: TryAcquire(%q): want acquired %t, got %t", i, s.id, s.acquired, got)
+				}
+				if position != s.position {
+					t.Fatalf("step %d: TryAcquire(%q): want position %d, got %d", i, s.id, s.position, position)
+				}
+				if release != nil {
+					held[s.id] = release
+				}
+			}
+		})
+	}
+}
+
+func TestTryAcquireStaleWaiter(t *testing.T) {
+	l := Limits{MaxConcurrentOperations: 1}
+	now := time.Now()
+	r := NewRegistry(Limits{})
+	r.now = func() time.Time { return now }
+
+	release, _ := r.TryAcquire("target", "a", l)
+	r.TryAcquire("target", "b", l)
+	release()
+
+	// b gave up waiting, so c should not wait for it.
+	now = now.Add(staleAfter + time.Second)
+	if release, position := r.TryAcquire("target", "c", l); release == nil {
+		t.Errorf("TryAcquire(...): want acquired, got position %d", position)
+	}
+}
+
+func TestLeave(t *testing.T) {
+	l := Limits{MaxConcurrentOperations: 1}
+	r := NewRegistry(Limits{})
+
+	release, _ := r.TryAcquire("target", "a", l)
+	r.TryAcquire("target", "b", l)
+	if _, position := r.TryAcquire("target", "c", l); position != 2 {
+		t.Fatalf("TryAcquire(...): want position 2, got %d", position)
+	}
+
+	// b no longer waits, so c should move up in the queue right away.
+	r.Leave("target", "b")
+	if _, position := r.TryAcquire("target", "c", l); position != 1 {
+		t.Errorf("TryAcquire(...): want position 1 after b left, got %d", position)
+	}
+
+	// Leaving does not release a slot.
+	r.Leave("target", "a")
+	if _, position := r.TryAcquire("target", "c", l); position != 1 {
+		t.Errorf("TryAcquire(...): want position 1 while a holds the slot, got %d", position)
+	}
+	release()
+	if release, position := r.TryAcquire("target", "c", l); release == nil {
+		t.Errorf("TryAcquire(...): want acquired, got position %d", position)
+	}
+}
+
+func TestTargetKey(t *testing.T) {
+	a := TargetKey(&rest.Config{Host: "https://a.example.org", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}})
+	if b := TargetKey(&rest.Config{Host: "https://a.example.org", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}, BearerToken: "other"}); a != b {
+		t.Errorf("TargetKey(...): want the same key for the same host and CA, got %q and %q", a, b)
+	}
+	if c := TargetKey(&rest.Config{Host: "https://a.example.org", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("other")}}); a == c {
+		t.Errorf("TargetKey(...): want different keys for different CAs")
+	}
+	if d := TargetKey(&rest.Config{Host: "https://b.example.org", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}}); a == d {
+		t.Errorf("TargetKey(...): want different keys for different hosts")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	r := NewRegistry(Limits{})
+
+	if rl := r.RateLimiter("target", Limits{}); rl != nil {
+		t.Errorf("RateLimiter(...): want nil without QPS, got %v", rl)
+	}
+
+	l := Limits{QPS: 5, Burst: 10}
+	a := r.RateLimiter("target", l)
+	if b := r.RateLimiter("target", l); a != b {
+		t.Errorf("RateLimiter(...): want the same rate limiter for a target")
+	}
+	if c := r.RateLimiter("other", l); a == c {
+		t.Errorf("RateLimiter(...): want different rate limiters for different targets")
+	}
+	if d := r.RateLimiter("target", Limits{QPS: 1}); a == d {
+		t.Errorf("RateLimiter(...): want a new rate limiter when the limits change")
+	}
+}
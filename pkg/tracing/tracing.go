@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports OpenTelemetry traces of the reconciles and Helm
+// operations of the provider.
+package tracing
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/crossplane-contrib/provider-helm"
+	serviceName = "provider-helm"
+
+	errCreateExporter = "cannot create OTLP trace exporter"
+)
+
+// Attributes of the spans.
+const (
+	AttrRelease        = attribute.Key("helm.release")
+	AttrChart          = attribute.Key("helm.chart")
+	AttrVersion        = attribute.Key("helm.chart.version")
+	AttrRepository     = attribute.Key("helm.chart.repository")
+	AttrTarget         = attribute.Key("helm.target_cluster")
+	AttrProviderConfig = attribute.Key("crossplane.provider_config")
+)
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint is the host and port of the OTLP gRPC collector traces are
+	// exported to. Tracing is disabled if empty.
+	Endpoint string
+	// Insecure exports traces without TLS.
+	Insecure bool
+	// SampleRatio is the ratio of reconciles traced, between 0 and 1.
+	SampleRatio float64
+	// Version of the provider, as recorded in the traces.
+	Version string
+}
+
+// Setup exports the spans of the provider to the configured collector. It
+// returns a function that flushes and stops the export.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exp, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateExporter)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", o.Version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start starts a span that is a child of the span of the supplied context, if
+// any. Spans are not recorded unless tracing was set up.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends a span, marking it failed if the supplied error is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
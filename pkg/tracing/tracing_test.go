@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartEnd(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx, parent := Start(context.Background(), "Reconcile", AttrRelease.String("wordpress"))
+	_, child := Start(ctx, "Install", AttrChart.String("wordpress"))
+	End(child, errors.New("boom"))
+	End(parent, nil)
+
+	spans := sr.Ended()
+	if diff := cmp.Diff(2, len(spans)); diff != "" {
+		t.Fatalf("End(...): -want spans, +got spans: %s", diff)
+	}
+	install, reconcile := spans[0], spans[1]
+	if install.Parent().SpanID() != reconcile.SpanContext().SpanID() {
+		t.Errorf("Start(...): want Install to be a child of Reconcile")
+	}
+	if diff := cmp.Diff(codes.Error, install.Status().Code); diff != "" {
+		t.Errorf("End(...): want a failed span, -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(codes.Unset, reconcile.Status().Code); diff != "" {
+		t.Errorf("End(...): want a successful span, -want, +got: %s", diff)
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Setup(...): %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown(...): %v", err)
+	}
+}